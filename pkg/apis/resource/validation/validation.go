@@ -619,6 +619,9 @@ func validateResourceSliceSpec(spec, oldSpec *resource.ResourceSliceSpec, fldPat
 		allErrs = append(allErrs, apimachineryvalidation.ValidateImmutableField(spec.Pool.Name, oldSpec.Pool.Name, fldPath.Child("pool", "name"))...)
 		allErrs = append(allErrs, apimachineryvalidation.ValidateImmutableField(spec.Driver, oldSpec.Driver, fldPath.Child("driver"))...)
 		allErrs = append(allErrs, apimachineryvalidation.ValidateImmutableField(spec.NodeName, oldSpec.NodeName, fldPath.Child("nodeName"))...)
+		if spec.Pool.Generation < oldSpec.Pool.Generation {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("pool", "generation"), "must not decrease"))
+		}
 	}
 
 	setFields := make([]string, 0, 4)
@@ -935,15 +938,73 @@ func validateSingleAllocatableDeviceCapacity(capacity resource.DeviceCapacity, f
 	return allErrs
 }
 
+// ValidateCapacityRequestPolicy validates the internal consistency of a
+// capacity request policy, independent of the capacity it is attached to:
+// at most one of validValues and validRange may be specified, validValues
+// must not be empty when specified, min must not exceed max, step must be
+// positive, and the default must lie within the range and be reachable from
+// min in whole steps.
+func ValidateCapacityRequestPolicy(policy *resource.CapacityRequestPolicy, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if policy == nil {
+		return allErrs
+	}
+	if len(policy.ValidValues) > 0 && policy.ValidRange != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath, `exactly one policy can be specified, cannot specify "validValues" and "validRange" at the same time`))
+		return allErrs
+	}
+	if policy.ValidValues != nil && len(policy.ValidValues) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("validValues"), "must contain at least one value when specified"))
+	}
+	if policy.ValidRange != nil {
+		allErrs = append(allErrs, validateRequestPolicyRangeConsistency(policy.Default, *policy.ValidRange, fldPath.Child("validRange"))...)
+	}
+	return allErrs
+}
+
+// validateRequestPolicyRangeConsistency checks a range against itself and the
+// policy's default, without reference to the device's capacity.
+func validateRequestPolicyRangeConsistency(defaultValue *apiresource.Quantity, valueRange resource.CapacityRequestPolicyRange, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if valueRange.Min == nil {
+		allErrs = append(allErrs, field.Required(fldPath.Child("min"), "required when validRange is defined"))
+		return allErrs
+	}
+	if defaultValue != nil && defaultValue.Cmp(*valueRange.Min) < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("min"), defaultValue.String(), fmt.Sprintf("default is less than min: %s", valueRange.Min.String())))
+	}
+	if valueRange.Max != nil {
+		if valueRange.Min.Cmp(*valueRange.Max) > 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("max"), valueRange.Min.String(), fmt.Sprintf("min is larger than max: %s", valueRange.Max.String())))
+		}
+		if defaultValue != nil && defaultValue.Cmp(*valueRange.Max) > 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("max"), defaultValue.String(), fmt.Sprintf("default is more than max: %s", valueRange.Max.String())))
+		}
+	}
+	if valueRange.Step != nil {
+		if valueRange.Step.Sign() <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("step"), valueRange.Step.String(), "must be greater than zero"))
+			return allErrs
+		}
+		if defaultValue != nil {
+			allErrs = append(allErrs, validateRequestPolicyRangeStep(*defaultValue, *valueRange.Min, *valueRange.Step, fldPath.Child("step"))...)
+		}
+		if valueRange.Max != nil {
+			allErrs = append(allErrs, validateRequestPolicyRangeStep(*valueRange.Max, *valueRange.Min, *valueRange.Step, fldPath.Child("step"))...)
+		}
+	}
+	return allErrs
+}
+
 // validateRequestPolicy validates at most one of ValidRequestValues can be defined.
 // If any ValidRequestValues are defined, Default must also be defined and valid.
 func validateRequestPolicy(maxCapacity apiresource.Quantity, policy *resource.CapacityRequestPolicy, fldPath *field.Path) field.ErrorList {
-	var allErrs field.ErrorList
+	allErrs := ValidateCapacityRequestPolicy(policy, fldPath)
 	if len(policy.ValidValues) > 0 && policy.ValidRange != nil {
-		allErrs = append(allErrs, field.Forbidden(fldPath, `exactly one policy can be specified, cannot specify "validValues" and "validRange" at the same time`))
-	} else {
-		allErrs = append(allErrs, validateValidRequestValues(maxCapacity, policy, fldPath)...)
+		// Already rejected; the capacity checks would only add noise.
+		return allErrs
 	}
+	allErrs = append(allErrs, validateValidRequestValues(maxCapacity, policy, fldPath)...)
 	return allErrs
 }
 
@@ -997,39 +1058,27 @@ func validateRequestPolicyValidValues(defaultValue apiresource.Quantity, maxCapa
 	return allErrs
 }
 
+// validateRequestPolicyRange checks a range against the device's capacity.
+// The internal consistency of the range (min vs. max, step positivity, the
+// default's reachability) is covered by ValidateCapacityRequestPolicy.
 func validateRequestPolicyRange(defaultValue apiresource.Quantity, maxCapacity apiresource.Quantity, valueRange resource.CapacityRequestPolicyRange, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 	if valueRange.Min == nil {
-		allErrs = append(allErrs, field.Required(fldPath.Child("min"), "required when validRange is defined"))
+		// Already rejected as inconsistent.
 		return allErrs
 	}
 	if valueRange.Min.Cmp(maxCapacity) > 0 {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("min"), valueRange.Min.String(), fmt.Sprintf("min is larger than capacity value: %s", maxCapacity.String())))
 	}
-	if defaultValue.Cmp(*valueRange.Min) < 0 {
-		allErrs = append(allErrs, field.Invalid(fldPath.Child("min"), defaultValue.String(), fmt.Sprintf("default is less than min: %s", valueRange.Min.String())))
-	}
-	if valueRange.Max != nil {
-		if valueRange.Min.Cmp(*valueRange.Max) > 0 {
-			allErrs = append(allErrs, field.Invalid(fldPath.Child("max"), valueRange.Min.String(), fmt.Sprintf("min is larger than max: %s", valueRange.Max.String())))
-		}
-		if valueRange.Max.Cmp(maxCapacity) > 0 {
-			allErrs = append(allErrs, field.Invalid(fldPath.Child("max"), valueRange.Max.String(), fmt.Sprintf("max is larger than capacity value: %s", maxCapacity.String())))
-		}
-		if defaultValue.Cmp(*valueRange.Max) > 0 {
-			allErrs = append(allErrs, field.Invalid(fldPath.Child("max"), defaultValue.String(), fmt.Sprintf("default is more than max: %s", valueRange.Max.String())))
-		}
+	if valueRange.Max != nil && valueRange.Max.Cmp(maxCapacity) > 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("max"), valueRange.Max.String(), fmt.Sprintf("max is larger than capacity value: %s", maxCapacity.String())))
 	}
-	if valueRange.Step != nil {
+	if valueRange.Step != nil && valueRange.Step.Sign() > 0 {
 		added := valueRange.Min.DeepCopy()
 		added.Add(*valueRange.Step)
 		if added.Cmp(maxCapacity) > 0 {
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("step"), valueRange.Step.String(), fmt.Sprintf("one step %s is larger than capacity value: %s", added.String(), maxCapacity.String())))
 		}
-		allErrs = append(allErrs, validateRequestPolicyRangeStep(defaultValue, *valueRange.Min, *valueRange.Step, fldPath.Child("step"))...)
-		if valueRange.Max != nil {
-			allErrs = append(allErrs, validateRequestPolicyRangeStep(*valueRange.Max, *valueRange.Min, *valueRange.Step, fldPath.Child("step"))...)
-		}
 	}
 	return allErrs
 }
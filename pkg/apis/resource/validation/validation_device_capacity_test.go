@@ -148,10 +148,28 @@ func TestValidateDeviceCapacity(t *testing.T) {
 		"invalid-range-large-min-small-max": {
 			capacity: testDeviceCapacity(maxCapacity, testCapacityRequestPolicy(&two, nil, testValidRange(ptr.To(overCapacity), ptr.To(one), nil))),
 			wantFailures: field.ErrorList{
-				field.Invalid(validRangeField.Child("min"), "20Gi", "min is larger than capacity value: 10Gi"),
 				field.Invalid(validRangeField.Child("min"), "2Gi", "default is less than min: 20Gi"),
 				field.Invalid(validRangeField.Child("max"), "20Gi", "min is larger than max: 1Gi"),
 				field.Invalid(validRangeField.Child("max"), "2Gi", "default is more than max: 1Gi"),
+				field.Invalid(validRangeField.Child("min"), "20Gi", "min is larger than capacity value: 10Gi"),
+			},
+		},
+		"invalid-range-zero-step": {
+			capacity: testDeviceCapacity(maxCapacity, testCapacityRequestPolicy(&one, nil, testValidRange(ptr.To(one), ptr.To(maxCapacity), ptr.To(apiresource.MustParse("0"))))),
+			wantFailures: field.ErrorList{
+				field.Invalid(validRangeField.Child("step"), "0", "must be greater than zero"),
+			},
+		},
+		"invalid-range-negative-step": {
+			capacity: testDeviceCapacity(maxCapacity, testCapacityRequestPolicy(&one, nil, testValidRange(ptr.To(one), ptr.To(maxCapacity), ptr.To(apiresource.MustParse("-1Gi"))))),
+			wantFailures: field.ErrorList{
+				field.Invalid(validRangeField.Child("step"), "-1Gi", "must be greater than zero"),
+			},
+		},
+		"invalid-empty-valid-values": {
+			capacity: testDeviceCapacity(maxCapacity, testCapacityRequestPolicy(&one, []apiresource.Quantity{}, nil)),
+			wantFailures: field.ErrorList{
+				field.Required(validValuesField, "must contain at least one value when specified"),
 			},
 		},
 		"invalid-range-large-max": {
@@ -181,3 +199,56 @@ func TestValidateDeviceCapacity(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateCapacityRequestPolicy(t *testing.T) {
+	one := apiresource.MustParse("1Gi")
+	two := apiresource.MustParse("2Gi")
+	ten := apiresource.MustParse("10Gi")
+
+	policyField := field.NewPath("requestPolicy")
+	validRangeField := policyField.Child("validRange")
+
+	scenarios := map[string]struct {
+		policy       *resource.CapacityRequestPolicy
+		wantFailures field.ErrorList
+	}{
+		"nil-policy": {
+			policy: nil,
+		},
+		"valid-range": {
+			policy: testCapacityRequestPolicy(&one, nil, testValidRange(ptr.To(one), ptr.To(ten), ptr.To(one))),
+		},
+		"both-policies": {
+			policy: testCapacityRequestPolicy(&one, []apiresource.Quantity{one}, testValidRange(ptr.To(one), nil, nil)),
+			wantFailures: field.ErrorList{
+				field.Forbidden(policyField, `exactly one policy can be specified, cannot specify "validValues" and "validRange" at the same time`),
+			},
+		},
+		"min-larger-than-max": {
+			policy: testCapacityRequestPolicy(&two, nil, testValidRange(ptr.To(ten), ptr.To(one), nil)),
+			wantFailures: field.ErrorList{
+				field.Invalid(validRangeField.Child("min"), "2Gi", "default is less than min: 10Gi"),
+				field.Invalid(validRangeField.Child("max"), "10Gi", "min is larger than max: 1Gi"),
+				field.Invalid(validRangeField.Child("max"), "2Gi", "default is more than max: 1Gi"),
+			},
+		},
+		"non-positive-step": {
+			policy: testCapacityRequestPolicy(&one, nil, testValidRange(ptr.To(one), ptr.To(ten), ptr.To(apiresource.MustParse("0")))),
+			wantFailures: field.ErrorList{
+				field.Invalid(validRangeField.Child("step"), "0", "must be greater than zero"),
+			},
+		},
+		"default-not-reachable-by-step": {
+			policy: testCapacityRequestPolicy(&two, nil, testValidRange(ptr.To(one), ptr.To(apiresource.MustParse("9Gi")), ptr.To(two))),
+			wantFailures: field.ErrorList{
+				field.Invalid(validRangeField.Child("step"), "2Gi", "value is not a multiple of a given step (2Gi) from (1Gi)"),
+			},
+		},
+	}
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			errs := ValidateCapacityRequestPolicy(scenario.policy, policyField)
+			assertFailures(t, scenario.wantFailures, errs)
+		})
+	}
+}
@@ -909,6 +909,25 @@ func TestValidateResourceSliceUpdate(t *testing.T) {
 				return slice
 			},
 		},
+		"valid-update-pool-generation-increase": {
+			oldResourceSlice: validResourceSlice,
+			update: func(slice *resourceapi.ResourceSlice) *resourceapi.ResourceSlice {
+				slice.Spec.Pool.Generation++
+				return slice
+			},
+		},
+		"invalid-update-pool-generation-decrease": {
+			wantFailures: field.ErrorList{field.Forbidden(field.NewPath("spec", "pool", "generation"), "must not decrease")},
+			oldResourceSlice: func() *resourceapi.ResourceSlice {
+				slice := validResourceSlice.DeepCopy()
+				slice.Spec.Pool.Generation = 1
+				return slice
+			}(),
+			update: func(slice *resourceapi.ResourceSlice) *resourceapi.ResourceSlice {
+				slice.Spec.Pool.Generation = 0
+				return slice
+			},
+		},
 		"invalid-update-to-invalid-nodeselector-label-value": {
 			wantFailures: field.ErrorList{field.Invalid(field.NewPath("spec", "nodeSelector", "nodeSelectorTerms").Index(0).Child("matchExpressions").Index(0).Child("values").Index(0), "-1", "a valid label must be an empty string or consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyValue',  or 'my_value',  or '12345', regex used for validation is '(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])?')")},
 			oldResourceSlice: func() *resourceapi.ResourceSlice {
@@ -301,6 +301,14 @@ const (
 	// based on "structured parameters".
 	DynamicResourceAllocation featuregate.Feature = "DynamicResourceAllocation"
 
+	// owner: @fabiendupont
+	//
+	// Enables enhanced topology hints carrying interconnect metrics (hop
+	// count, distance, bandwidth) and a composite placement score, so the
+	// topology manager can rank candidate NUMA placements instead of only
+	// preferring the narrowest affinity mask.
+	EnhancedTopologyHints featuregate.Feature = "EnhancedTopologyHints"
+
 	// owner: @HirazawaUi
 	// kep: http://kep.k8s.io/3721
 	//
@@ -1232,6 +1240,10 @@ var defaultVersionedKubernetesFeatureGates = map[featuregate.Feature]featuregate
 		{Version: version.MustParse("1.32"), Default: false, PreRelease: featuregate.Beta},
 		{Version: version.MustParse("1.34"), Default: true, PreRelease: featuregate.GA}, // lock to default in 1.35
 	},
+	EnhancedTopologyHints: {
+		{Version: version.MustParse("1.34"), Default: false, PreRelease: featuregate.Alpha},
+	},
+
 	EnvFiles: {
 		{Version: version.MustParse("1.34"), Default: false, PreRelease: featuregate.Alpha},
 	},
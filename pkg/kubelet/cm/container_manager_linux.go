@@ -302,6 +302,9 @@ func NewContainerManager(mountUtil mount.Interface, cadvisorInterface cadvisor.I
 	if err != nil {
 		return nil, err
 	}
+	// Detect NUMA distance matrix changes across restarts (BIOS updates, CXL
+	// memory re-provisioning) so stale placement assumptions are flagged.
+	cm.topologyManager.CheckTopologyDrift(nodeConfig.KubeletRootDir)
 
 	klog.InfoS("Creating device plugin manager")
 	cm.deviceManager, err = devicemanager.NewManagerImpl(machineInfo.Topology, cm.topologyManager)
@@ -337,6 +340,12 @@ func NewContainerManager(mountUtil mount.Interface, cadvisorInterface cadvisor.I
 		return nil, err
 	}
 	cm.topologyManager.AddHintProvider(cm.cpuManager)
+	// Share the CPU manager's committed CPU placement with device hint
+	// scoring, so devices close to a container's CPUs score better.
+	cm.deviceManager.SetCPUNUMAAffinityLookup(cm.cpuManager.GetCPUNUMAAffinity)
+	// Score device hints under the configured policy options rather than the
+	// scoring defaults.
+	cm.deviceManager.SetTopologyPolicyOptions(cm.topologyManager.PolicyOptions())
 
 	cm.memoryManager, err = memorymanager.NewManager(
 		context.TODO(),
@@ -352,6 +361,12 @@ func NewContainerManager(mountUtil mount.Interface, cadvisorInterface cadvisor.I
 		return nil, err
 	}
 	cm.topologyManager.AddHintProvider(cm.memoryManager)
+	// Let enhanced memory hint scoring penalize NUMA nodes under memory
+	// pressure, per the memory-pressure-free-fraction policy option.
+	cm.memoryManager.SetFreeFractionThreshold(cm.topologyManager.PolicyOptions().MemoryPressureFreeFraction)
+	// Score memory hints under the configured policy options rather than the
+	// scoring defaults.
+	cm.memoryManager.SetTopologyPolicyOptions(cm.topologyManager.PolicyOptions())
 
 	// Create a single channel for all resource updates. This channel is consumed
 	// by the Kubelet's main sync loop.
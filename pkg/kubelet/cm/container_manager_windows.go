@@ -181,6 +181,12 @@ func NewContainerManager(mountUtil mount.Interface, cadvisorInterface cadvisor.I
 			return nil, err
 		}
 		cm.topologyManager.AddHintProvider(cm.memoryManager)
+		// Let enhanced memory hint scoring penalize NUMA nodes under memory
+		// pressure, per the memory-pressure-free-fraction policy option.
+		cm.memoryManager.SetFreeFractionThreshold(cm.topologyManager.PolicyOptions().MemoryPressureFreeFraction)
+		// Score memory hints under the configured policy options rather than
+		// the scoring defaults.
+		cm.memoryManager.SetTopologyPolicyOptions(cm.topologyManager.PolicyOptions())
 	}
 
 	klog.InfoS("Creating device plugin manager")
@@ -189,6 +195,12 @@ func NewContainerManager(mountUtil mount.Interface, cadvisorInterface cadvisor.I
 		return nil, err
 	}
 	cm.topologyManager.AddHintProvider(cm.deviceManager)
+	// Share the CPU manager's committed CPU placement with device hint
+	// scoring, so devices close to a container's CPUs score better.
+	cm.deviceManager.SetCPUNUMAAffinityLookup(cm.cpuManager.GetCPUNUMAAffinity)
+	// Score device hints under the configured policy options rather than the
+	// scoring defaults.
+	cm.deviceManager.SetTopologyPolicyOptions(cm.topologyManager.PolicyOptions())
 
 	return cm, nil
 }
@@ -94,6 +94,11 @@ type Manager interface {
 	// as well as exclusively allocated cpus
 	GetCPUAffinity(podUID, containerName string) cpuset.CPUSet
 
+	// GetCPUNUMAAffinity returns the NUMA nodes hosting the CPUs assigned to
+	// the given container, in ascending order. Containers running on the
+	// shared pool report the nodes of the whole pool.
+	GetCPUNUMAAffinity(podUID, containerName string) []int
+
 	// GetAllCPUs returns all the CPUs known by cpumanager, as reported by the
 	// hardware discovery. Maps to the CPU capacity.
 	GetAllCPUs() cpuset.CPUSet
@@ -525,3 +530,8 @@ func (m *manager) GetExclusiveCPUs(podUID, containerName string) cpuset.CPUSet {
 func (m *manager) GetCPUAffinity(podUID, containerName string) cpuset.CPUSet {
 	return m.state.GetCPUSetOrDefault(podUID, containerName)
 }
+
+func (m *manager) GetCPUNUMAAffinity(podUID, containerName string) []int {
+	cpus := m.state.GetCPUSetOrDefault(podUID, containerName)
+	return m.topology.CPUDetails.KeepOnly(cpus).NUMANodes().List()
+}
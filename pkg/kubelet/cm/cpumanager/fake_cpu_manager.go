@@ -85,6 +85,11 @@ func (m *fakeManager) GetCPUAffinity(podUID, containerName string) cpuset.CPUSet
 	return cpuset.CPUSet{}
 }
 
+func (m *fakeManager) GetCPUNUMAAffinity(podUID, containerName string) []int {
+	klog.InfoS("GetCPUNUMAAffinity", "podUID", podUID, "containerName", containerName)
+	return nil
+}
+
 func (m *fakeManager) GetAllCPUs() cpuset.CPUSet {
 	klog.InfoS("GetAllCPUs")
 	return cpuset.CPUSet{}
@@ -722,7 +722,7 @@ func (p *staticPolicy) generateCPUTopologyHints(availableCPUs cpuset.CPUSet, reu
 
 // isHintSocketAligned function return true if numa nodes in hint are socket aligned.
 func (p *staticPolicy) isHintSocketAligned(hint topologymanager.TopologyHint, minAffinitySize int) bool {
-	numaNodesBitMask := hint.NUMANodeAffinity.GetBits()
+	numaNodesBitMask := hint.NodeIDs()
 	numaNodesPerSocket := p.topology.NumNUMANodes / p.topology.NumSockets
 	if numaNodesPerSocket == 0 {
 		return false
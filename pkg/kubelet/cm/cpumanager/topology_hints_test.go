@@ -18,7 +18,6 @@ package cpumanager
 
 import (
 	"reflect"
-	"sort"
 	"testing"
 
 	cadvisorapi "github.com/google/cadvisor/info/v1"
@@ -247,12 +246,8 @@ func TestGetTopologyHints(t *testing.T) {
 		if len(tc.expectedHints) == 0 && len(hints) == 0 {
 			continue
 		}
-		sort.SliceStable(hints, func(i, j int) bool {
-			return hints[i].LessThan(hints[j])
-		})
-		sort.SliceStable(tc.expectedHints, func(i, j int) bool {
-			return tc.expectedHints[i].LessThan(tc.expectedHints[j])
-		})
+		topologymanager.SortHints(hints)
+		topologymanager.SortHints(tc.expectedHints)
 		if !reflect.DeepEqual(tc.expectedHints, hints) {
 			t.Errorf("Expected in result to be %v , got %v", tc.expectedHints, hints)
 		}
@@ -297,12 +292,8 @@ func TestGetPodTopologyHints(t *testing.T) {
 		if len(tc.expectedHints) == 0 && len(podHints) == 0 {
 			continue
 		}
-		sort.SliceStable(podHints, func(i, j int) bool {
-			return podHints[i].LessThan(podHints[j])
-		})
-		sort.SliceStable(tc.expectedHints, func(i, j int) bool {
-			return tc.expectedHints[i].LessThan(tc.expectedHints[j])
-		})
+		topologymanager.SortHints(podHints)
+		topologymanager.SortHints(tc.expectedHints)
 		if !reflect.DeepEqual(tc.expectedHints, podHints) {
 			t.Errorf("Expected in result to be %v , got %v", tc.expectedHints, podHints)
 		}
@@ -476,12 +467,8 @@ func TestGetPodTopologyHintsWithPolicyOptions(t *testing.T) {
 			}
 
 			podHints := m.GetPodTopologyHints(&testCase.pod)[string(v1.ResourceCPU)]
-			sort.SliceStable(podHints, func(i, j int) bool {
-				return podHints[i].LessThan(podHints[j])
-			})
-			sort.SliceStable(testCase.expectedHints, func(i, j int) bool {
-				return testCase.expectedHints[i].LessThan(testCase.expectedHints[j])
-			})
+			topologymanager.SortHints(podHints)
+			topologymanager.SortHints(testCase.expectedHints)
 			if !reflect.DeepEqual(testCase.expectedHints, podHints) {
 				t.Errorf("Expected in result to be %v , got %v", testCase.expectedHints, podHints)
 			}
@@ -36,6 +36,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apiserver/pkg/server/healthz"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	draapi "k8s.io/dynamic-resource-allocation/api"
 	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 	"k8s.io/kubernetes/pkg/features"
@@ -95,13 +96,48 @@ type ManagerImpl struct {
 	// List of NUMA Nodes available on the underlying machine
 	numaNodes []int
 
+	// NUMA distance table of the underlying machine, keyed by NUMA node id.
+	// May be empty if cadvisor does not report distances.
+	numaDistances map[int][]uint64
+
 	// Store of Topology Affinities that the Device Manager can query.
 	topologyAffinityStore topologymanager.Store
 
+	// cpuNUMAAffinityLookup reports the NUMA nodes hosting a container's
+	// committed CPUs, wired to the CPU manager by the container manager.
+	// Nil until wired, which disables CPU-proximity scoring.
+	cpuNUMAAffinityLookup func(podUID, containerName string) []int
+
+	// topologyPolicyOptions holds the topology manager's parsed policy
+	// options, wired by the container manager. The zero value scores hints
+	// with the default local distance baseline and no rounding.
+	topologyPolicyOptions topologymanager.PolicyOptions
+
 	// devicesToReuse contains devices that can be reused as they have been allocated to
 	// init containers.
 	devicesToReuse PodReusableDevices
 
+	// deviceSharingFactors records, per resource and device ID, how many
+	// allocations a device can back, mirroring the DRA
+	// AllowMultipleAllocations semantics for device plugin devices. The
+	// device plugin API cannot express sharing yet, so the factors are set
+	// out of band via SetDeviceSharingFactor. A missing entry means the
+	// device is exclusive (factor 1).
+	deviceSharingFactors map[string]map[string]int
+
+	// connectivityMatrices caches, per resource, the NUMA connectivity
+	// matrix published by a backing ResourceSlice, keyed by source NUMA
+	// node ID. When present, hint generation prefers the measured node-pair
+	// bandwidth over the bandwidth model. Matrices are set out of band via
+	// SetNUMATopology.
+	connectivityMatrices map[string]map[int][]draapi.NodeConnectivity
+
+	// bandwidthModel estimates the interconnect bandwidth of candidate
+	// device placements during hint generation. Defaults to the synthetic
+	// hop-degradation model; vendors can plug a measured model via
+	// SetBandwidthModel.
+	bandwidthModel BandwidthModel
+
 	// containerMap provides a mapping from (pod, container) -> containerID
 	// for all containers in a pod. Used to detect pods running across a restart
 	containerMap containermap.ContainerMap
@@ -141,8 +177,12 @@ func newManagerImpl(socketPath string, topology []cadvisorapi.Node, topologyAffi
 	klog.V(2).InfoS("Creating Device Plugin manager", "path", socketPath)
 
 	var numaNodes []int
+	numaDistances := make(map[int][]uint64)
 	for _, node := range topology {
 		numaNodes = append(numaNodes, node.Id)
+		if len(node.Distances) > 0 {
+			numaDistances[node.Id] = node.Distances
+		}
 	}
 
 	manager := &ManagerImpl{
@@ -154,8 +194,12 @@ func newManagerImpl(socketPath string, topology []cadvisorapi.Node, topologyAffi
 		allocatedDevices:      make(map[string]sets.Set[string]),
 		podDevices:            newPodDevices(),
 		numaNodes:             numaNodes,
+		numaDistances:         numaDistances,
 		topologyAffinityStore: topologyAffinityStore,
 		devicesToReuse:        make(PodReusableDevices),
+		deviceSharingFactors:  make(map[string]map[string]int),
+		connectivityMatrices:  make(map[string]map[int][]draapi.NodeConnectivity),
+		bandwidthModel:        defaultBandwidthModel{},
 		update:                make(chan resourceupdates.Update, 100),
 	}
 
@@ -542,10 +586,12 @@ func (m *ManagerImpl) getCheckpoint() (checkpoint.DeviceManagerCheckpoint, error
 }
 
 // UpdateAllocatedDevices frees any Devices that are bound to terminated pods.
-func (m *ManagerImpl) UpdateAllocatedDevices() {
+// It returns the number of stale device entries reclaimed, so callers can
+// detect when allocation state had drifted from the set of active pods.
+func (m *ManagerImpl) UpdateAllocatedDevices() int {
 	activePods := m.activePods()
 	if !m.sourcesReady.AllReady() {
-		return
+		return 0
 	}
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
@@ -554,12 +600,28 @@ func (m *ManagerImpl) UpdateAllocatedDevices() {
 		podsToBeRemoved.Delete(string(pod.UID))
 	}
 	if len(podsToBeRemoved) <= 0 {
-		return
+		return 0
 	}
 	klog.V(3).InfoS("Pods to be removed", "podUIDs", sets.List(podsToBeRemoved))
+	reclaimed := countDeviceEntries(m.podDevices.devices())
 	m.podDevices.delete(sets.List(podsToBeRemoved))
 	// Regenerated allocatedDevices after we update pod allocation information.
 	m.allocatedDevices = m.podDevices.devices()
+	reclaimed -= countDeviceEntries(m.allocatedDevices)
+	if reclaimed > 0 {
+		metrics.DeviceManagerReclaimedDevices.Add(float64(reclaimed))
+	}
+	return reclaimed
+}
+
+// countDeviceEntries returns the total number of device IDs across all
+// resources in the given allocation map.
+func countDeviceEntries(devices map[string]sets.Set[string]) int {
+	count := 0
+	for _, ids := range devices {
+		count += ids.Len()
+	}
+	return count
 }
 
 // Returns list of device Ids we need to allocate with Allocate rpc call.
@@ -17,16 +17,94 @@ limitations under the License.
 package devicemanager
 
 import (
+	"sort"
+
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/component-helpers/resource"
+	draapi "k8s.io/dynamic-resource-allocation/api"
 	"k8s.io/klog/v2"
 	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
 
+	"k8s.io/kubernetes/pkg/features"
 	"k8s.io/kubernetes/pkg/kubelet/cm/topologymanager"
 	"k8s.io/kubernetes/pkg/kubelet/cm/topologymanager/bitmask"
 )
 
+// Synthetic interconnect bandwidth model for device hints: each NUMA hop
+// degrades the base bandwidth by a fixed fraction, down to a floor.
+const (
+	deviceBaseBandwidthGBs    = 80.0
+	deviceBandwidthHopPenalty = 0.4
+	deviceMinBandwidthGBs     = 10.0
+)
+
+// BandwidthModel estimates the interconnect bandwidth, in GB/s, a device
+// allocation for the resource can expect when it spans the given NUMA nodes.
+// Vendors with measured fabric data can plug their own model via
+// SetBandwidthModel instead of patching the manager; a ResourceSlice
+// connectivity matrix cached for the resource still takes precedence over
+// whatever the model returns.
+type BandwidthModel interface {
+	Bandwidth(resource string, nodes []int) float64
+}
+
+// defaultBandwidthModel is the synthetic hop-degradation model: each NUMA hop
+// degrades the base bandwidth by a fixed fraction, down to a floor.
+type defaultBandwidthModel struct{}
+
+func (defaultBandwidthModel) Bandwidth(resource string, nodes []int) float64 {
+	hopCount := len(nodes) - 1
+	bandwidth := deviceBaseBandwidthGBs * (1 - deviceBandwidthHopPenalty*float64(hopCount))
+	if bandwidth < deviceMinBandwidthGBs {
+		bandwidth = deviceMinBandwidthGBs
+	}
+	return bandwidth
+}
+
+// SetBandwidthModel replaces the bandwidth model used during hint generation.
+// A nil model resets the manager to the default synthetic one.
+func (m *ManagerImpl) SetBandwidthModel(model BandwidthModel) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if model == nil {
+		model = defaultBandwidthModel{}
+	}
+	m.bandwidthModel = model
+}
+
+// SetCPUNUMAAffinityLookup wires the function used to look up the NUMA nodes
+// hosting a container's committed CPUs, so hint scoring can prefer devices
+// close to them. A nil lookup disables CPU-proximity scoring.
+func (m *ManagerImpl) SetCPUNUMAAffinityLookup(lookup func(podUID, containerName string) []int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.cpuNUMAAffinityLookup = lookup
+}
+
+// SetTopologyPolicyOptions supplies the topology manager's parsed policy
+// options, so hint scoring uses the configured local distance baseline and
+// rounding instead of the defaults.
+func (m *ManagerImpl) SetTopologyPolicyOptions(opts topologymanager.PolicyOptions) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.topologyPolicyOptions = opts
+}
+
+// deviceReuseScoreBonus is subtracted from a hint's score for every reusable
+// device already resident on the masked NUMA nodes, since reusing a device
+// carries no new allocation cost.
+const deviceReuseScoreBonus = 2.0
+
+// deviceDiversityScorePenalty is added to a hint's score for every other
+// requested device resource type with no devices on the masked NUMA nodes,
+// since satisfying those resources will force a cross-node allocation.
+const deviceDiversityScorePenalty = 5.0
+
 // GetTopologyHints implements the TopologyManager HintProvider Interface which
 // ensures the Device Manager is consulted when Topology Aware Hints for each
 // container are created.
@@ -77,6 +155,11 @@ func (m *ManagerImpl) GetTopologyHints(pod *v1.Pod, container *v1.Container) map
 		deviceHints[resource] = m.generateDeviceTopologyHints(resource, available, reusable, requested)
 	}
 
+	if utilfeature.DefaultFeatureGate.Enabled(features.EnhancedTopologyHints) {
+		m.applyDeviceTypeDiversityPenalties(deviceHints, accumulatedResourceRequests)
+		m.applyCPUProximityPenalties(deviceHints, m.containerCPUNUMANodes(string(pod.UID), container.Name))
+	}
+
 	return deviceHints
 }
 
@@ -127,22 +210,145 @@ func (m *ManagerImpl) GetPodTopologyHints(pod *v1.Pod) map[string][]topologymana
 		deviceHints[resource] = m.generateDeviceTopologyHints(resource, available, sets.Set[string]{}, requested)
 	}
 
+	if utilfeature.DefaultFeatureGate.Enabled(features.EnhancedTopologyHints) {
+		m.applyDeviceTypeDiversityPenalties(deviceHints, accumulatedResourceRequests)
+		m.applyCPUProximityPenalties(deviceHints, m.podCPUNUMANodes(pod))
+	}
+
 	return deviceHints
 }
 
 func (m *ManagerImpl) deviceHasTopologyAlignment(resource string) bool {
 	// If any device has Topology NUMANodes available, we assume they care about alignment.
+	// Ranging over a missing resource key is safe and reports no alignment.
 	for _, device := range m.allDevices[resource] {
-		if device.Topology != nil && len(device.Topology.Nodes) > 0 {
+		if hasUsableTopology(device.Topology) {
 			return true
 		}
 	}
 	return false
 }
 
+// hasUsableTopology returns true when the topology names at least one NUMA
+// node. Devices of an aligned resource whose topology is nil or empty are
+// treated as satisfiable from any NUMA node during hint generation: they
+// count toward every mask and never veto one, so a resource mixing
+// topology-reporting and topology-less devices still produces hints shaped by
+// the reporting ones.
+func hasUsableTopology(topology *pluginapi.TopologyInfo) bool {
+	return topology != nil && len(topology.Nodes) > 0
+}
+
 func (m *ManagerImpl) getAvailableDevices(resource string) sets.Set[string] {
+	// A missing resource key means no healthy devices are known; return an
+	// empty set rather than operating on a nil one.
+	healthy, ok := m.healthyDevices[resource]
+	if !ok {
+		return sets.New[string]()
+	}
 	// Strip all devices in use from the list of healthy ones.
-	return m.healthyDevices[resource].Difference(m.allocatedDevices[resource])
+	return healthy.Difference(m.allocatedDevices[resource])
+}
+
+// SetDeviceSharingFactor marks a device as able to back up to factor
+// allocations, the device plugin equivalent of a DRA device with
+// AllowMultipleAllocations. Hint generation then counts the device with its
+// sharing factor instead of as a one-shot resource. Factors below 2 reset
+// the device to exclusive.
+func (m *ManagerImpl) SetDeviceSharingFactor(resource, device string, factor int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if factor < 2 {
+		delete(m.deviceSharingFactors[resource], device)
+		return
+	}
+	if m.deviceSharingFactors[resource] == nil {
+		m.deviceSharingFactors[resource] = make(map[string]int)
+	}
+	m.deviceSharingFactors[resource][device] = factor
+}
+
+// deviceSharingFactor returns how many allocations the device can back,
+// defaulting to 1 for exclusive devices.
+func (m *ManagerImpl) deviceSharingFactor(resource, device string) int {
+	if factor, ok := m.deviceSharingFactors[resource][device]; ok && factor > 1 {
+		return factor
+	}
+	return 1
+}
+
+// SetNUMATopology caches the per-NUMA-node connectivity a ResourceSlice
+// publishes for the given resource. When the cached matrix covers every node
+// pair of a multi-node mask, hint generation uses the measured bottleneck
+// bandwidth instead of the synthetic hop-degradation model. An empty or
+// matrix-free topology clears the cache for the resource.
+func (m *ManagerImpl) SetNUMATopology(resource string, topology []draapi.NodeTopologyInfo) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	matrix := make(map[int][]draapi.NodeConnectivity)
+	for i := range topology {
+		node := &topology[i]
+		if node.InterconnectInfo == nil || len(node.InterconnectInfo.ConnectivityMatrix) == 0 {
+			continue
+		}
+		matrix[int(node.NodeID)] = node.InterconnectInfo.ConnectivityMatrix
+	}
+	if len(matrix) == 0 {
+		delete(m.connectivityMatrices, resource)
+		return
+	}
+	m.connectivityMatrices[resource] = matrix
+}
+
+// matrixBandwidthForMask returns the bottleneck bandwidth between the NUMA
+// nodes in the mask, according to the connectivity matrix cached for the
+// resource. It reports false when no matrix is cached or the matrix lacks a
+// bandwidth for some node pair, so callers can fall back to the synthetic
+// model.
+func (m *ManagerImpl) matrixBandwidthForMask(resource string, mask bitmask.BitMask) (float64, bool) {
+	matrix, ok := m.connectivityMatrices[resource]
+	if !ok {
+		return 0, false
+	}
+	nodes := mask.GetBits()
+	var bottleneck float64
+	found := false
+	for _, a := range nodes {
+		for _, b := range nodes {
+			if a == b {
+				continue
+			}
+			bandwidth, ok := pairBandwidth(matrix[a], b, resource)
+			if !ok {
+				return 0, false
+			}
+			if !found || bandwidth < bottleneck {
+				bottleneck = bandwidth
+				found = true
+			}
+		}
+	}
+	return bottleneck, found
+}
+
+// pairBandwidth looks up the bandwidth to the target node in a connectivity
+// matrix row. A PerResource override for the resource takes precedence over
+// the pair-wide bandwidth.
+func pairBandwidth(entries []draapi.NodeConnectivity, target int, resource string) (float64, bool) {
+	for i := range entries {
+		if int(entries[i].TargetNodeID) != target {
+			continue
+		}
+		if override, ok := entries[i].PerResource[resource]; ok && override.Bandwidth != nil {
+			return *override.Bandwidth, true
+		}
+		if entries[i].Bandwidth != nil {
+			return *entries[i].Bandwidth, true
+		}
+	}
+	return 0, false
 }
 
 func (m *ManagerImpl) generateDeviceTopologyHints(resource string, available sets.Set[string], reusable sets.Set[string], request int) []topologymanager.TopologyHint {
@@ -152,11 +358,13 @@ func (m *ManagerImpl) generateDeviceTopologyHints(resource string, available set
 	// Iterate through all combinations of NUMA Nodes and build hints from them.
 	hints := []topologymanager.TopologyHint{}
 	bitmask.IterateBitMasks(m.numaNodes, func(mask bitmask.BitMask) {
-		// First, update minAffinitySize for the current request size.
+		// First, update minAffinitySize for the current request size. Each
+		// device counts with its sharing factor, so a shareable device can
+		// satisfy several requests from a single NUMA node.
 		devicesInMask := 0
 		for _, device := range m.allDevices[resource] {
-			if mask.AnySet(m.getNUMANodeIds(device.Topology)) {
-				devicesInMask++
+			if !hasUsableTopology(device.Topology) || mask.AnySet(m.getNUMANodeIds(device.Topology)) {
+				devicesInMask += m.deviceSharingFactor(resource, device.ID)
 			}
 		}
 		if devicesInMask >= request && mask.Count() < minAffinitySize {
@@ -166,8 +374,10 @@ func (m *ManagerImpl) generateDeviceTopologyHints(resource string, available set
 		// Then check to see if all the reusable devices are part of the bitmask.
 		numMatching := 0
 		for d := range reusable {
-			// Skip the device if it doesn't specify any topology info.
-			if m.allDevices[resource][d].Topology == nil {
+			// A device without usable topology info can be reused from any
+			// NUMA node: it satisfies the request without constraining the mask.
+			if !hasUsableTopology(m.allDevices[resource][d].Topology) {
+				numMatching++
 				continue
 			}
 			// Otherwise disregard this mask if its NUMANode isn't part of it.
@@ -178,10 +388,11 @@ func (m *ManagerImpl) generateDeviceTopologyHints(resource string, available set
 		}
 
 		// Finally, check to see if enough available devices remain on the
-		// current NUMA node combination to satisfy the device request.
+		// current NUMA node combination to satisfy the device request,
+		// counting each with its sharing factor.
 		for d := range available {
-			if mask.AnySet(m.getNUMANodeIds(m.allDevices[resource][d].Topology)) {
-				numMatching++
+			if topology := m.allDevices[resource][d].Topology; !hasUsableTopology(topology) || mask.AnySet(m.getNUMANodeIds(topology)) {
+				numMatching += m.deviceSharingFactor(resource, d)
 			}
 		}
 
@@ -199,6 +410,14 @@ func (m *ManagerImpl) generateDeviceTopologyHints(resource string, available set
 		})
 	})
 
+	// When enhanced hints are enabled, compute the enhanced fields and the
+	// per-hint score before marking preferences, so the Preferred pass works
+	// on fully populated hints.
+	enhanced := utilfeature.DefaultFeatureGate.Enabled(features.EnhancedTopologyHints)
+	if enhanced {
+		m.calculateEnhancedTopologyFields(resource, hints, reusable)
+	}
+
 	// Loop back through all hints and update the 'Preferred' field based on
 	// counting the number of bits sets in the affinity mask and comparing it
 	// to the minAffinity. Only those with an equal number of bits set will be
@@ -209,9 +428,228 @@ func (m *ManagerImpl) generateDeviceTopologyHints(resource string, available set
 		}
 	}
 
+	// Several minimal-size masks may be marked preferred; order the hints so
+	// the lowest-score one comes first, giving the hint merger a meaningful
+	// ranking among equally narrow masks.
+	if enhanced {
+		sort.SliceStable(hints, func(i, j int) bool {
+			if hints[i].Preferred != hints[j].Preferred {
+				return hints[i].Preferred
+			}
+			return hints[i].GetScore() < hints[j].GetScore()
+		})
+	}
+
 	return hints
 }
 
+// calculateEnhancedTopologyFields populates the enhanced fields of each hint
+// from the machine topology. The hop count is the number of NUMA node
+// crossings the mask implies, the distance is the average SLIT distance
+// between the nodes in the mask (left unset when cadvisor reports no
+// distances), and the bandwidth comes from the configured bandwidth model —
+// unless a ResourceSlice connectivity matrix is cached for the resource, in
+// which case the measured bottleneck bandwidth between the masked nodes takes
+// precedence. The score is then derived from those fields, discounted for
+// reusable devices already resident on the masked nodes since reusing them
+// incurs no new allocation cost.
+func (m *ManagerImpl) calculateEnhancedTopologyFields(resource string, hints []topologymanager.TopologyHint, reusable sets.Set[string]) {
+	model := m.bandwidthModel
+	if model == nil {
+		// Managers constructed without going through newManagerImpl (tests,
+		// embedders) fall back to the default model.
+		model = defaultBandwidthModel{}
+	}
+	for i := range hints {
+		hopCount := hints[i].NUMANodeAffinity.Count() - 1
+		bandwidth := model.Bandwidth(resource, hints[i].NUMANodeAffinity.GetBits())
+		if hopCount > 0 {
+			if measured, ok := m.matrixBandwidthForMask(resource, hints[i].NUMANodeAffinity); ok {
+				bandwidth = measured
+			}
+		}
+		builder := topologymanager.NewHintBuilder(hints[i].NUMANodeAffinity).
+			Preferred(hints[i].Preferred).
+			Hop(hopCount).
+			Bandwidth(bandwidth)
+		if distance, ok := m.averageDistanceForMask(hints[i].NUMANodeAffinity); ok {
+			builder.Distance(distance)
+		}
+		built, err := builder.Build()
+		if err != nil {
+			// A model or matrix feeding out-of-range values must not poison
+			// the merge; leave the hint basic instead.
+			klog.ErrorS(err, "Discarding invalid enhanced topology fields", "resourceName", resource, "affinity", hints[i].NUMANodeAffinity)
+			continue
+		}
+		hints[i] = built
+		hints[i].UpdateScore(0, m.topologyPolicyOptions)
+
+		reuseCount := 0
+		for d := range reusable {
+			// A device without usable topology gives no locality signal, so
+			// it earns no per-mask reuse bonus.
+			topology := m.allDevices[resource][d].Topology
+			if !hasUsableTopology(topology) {
+				continue
+			}
+			if hints[i].NUMANodeAffinity.AnySet(m.getNUMANodeIds(topology)) {
+				reuseCount++
+			}
+		}
+		if reuseCount > 0 {
+			score := hints[i].GetScore() - deviceReuseScoreBonus*float64(reuseCount)
+			if score < 0 {
+				score = 0
+			}
+			hints[i].Score = &score
+		}
+	}
+}
+
+// applyDeviceTypeDiversityPenalties raises the score of hints whose masks
+// cannot host every requested device resource type locally. For each hint, a
+// penalty is added per other requested resource that has no devices on the
+// masked NUMA nodes, so masks covering all requested device types win over
+// masks that would force a cross-node allocation for one of them.
+func (m *ManagerImpl) applyDeviceTypeDiversityPenalties(deviceHints map[string][]topologymanager.TopologyHint, requests map[string]int) {
+	if len(requests) < 2 {
+		return
+	}
+	for resource, hints := range deviceHints {
+		for i := range hints {
+			if hints[i].NUMANodeAffinity == nil {
+				continue
+			}
+			missing := 0
+			for other := range requests {
+				if other == resource || !m.deviceHasTopologyAlignment(other) {
+					continue
+				}
+				if !m.resourceHostedOnMask(other, hints[i].NUMANodeAffinity) {
+					missing++
+				}
+			}
+			if missing > 0 {
+				score := hints[i].GetScore() + deviceDiversityScorePenalty*float64(missing)
+				hints[i].Score = &score
+			}
+		}
+	}
+}
+
+// containerCPUNUMANodes returns the NUMA nodes hosting the container's
+// committed CPUs, or nil when no lookup is wired.
+func (m *ManagerImpl) containerCPUNUMANodes(podUID, containerName string) []int {
+	if m.cpuNUMAAffinityLookup == nil {
+		return nil
+	}
+	return m.cpuNUMAAffinityLookup(podUID, containerName)
+}
+
+// podCPUNUMANodes returns the union of the NUMA nodes hosting the committed
+// CPUs of every container of the pod, or nil when no lookup is wired.
+func (m *ManagerImpl) podCPUNUMANodes(pod *v1.Pod) []int {
+	if m.cpuNUMAAffinityLookup == nil {
+		return nil
+	}
+	nodes := sets.New[int]()
+	for _, container := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+		nodes.Insert(m.cpuNUMAAffinityLookup(string(pod.UID), container.Name)...)
+	}
+	return sets.List(nodes)
+}
+
+// applyCPUProximityPenalties raises the score of hints whose masked NUMA
+// nodes sit far from the nodes hosting the container's CPUs, so a device on
+// the CPUs' node wins over an equally available one on a remote node. CPU
+// placement is only known once the CPU manager has committed an assignment,
+// e.g. when regenerating hints after a kubelet restart, and a shared pool
+// spanning every node carries no locality signal; in both cases the scores
+// are left untouched.
+func (m *ManagerImpl) applyCPUProximityPenalties(deviceHints map[string][]topologymanager.TopologyHint, cpuNodes []int) {
+	if len(cpuNodes) == 0 || len(cpuNodes) >= len(m.numaNodes) {
+		return
+	}
+	for _, hints := range deviceHints {
+		for i := range hints {
+			if hints[i].NUMANodeAffinity == nil {
+				continue
+			}
+			distance, ok := m.averageDistanceToNodes(hints[i].NUMANodeAffinity, cpuNodes)
+			if !ok {
+				continue
+			}
+			if penalty := float64(distance - m.topologyPolicyOptions.LocalDistance()); penalty > 0 {
+				score := hints[i].GetScore() + penalty
+				hints[i].Score = &score
+			}
+		}
+	}
+}
+
+// resourceHostedOnMask returns true if at least one device of the given
+// resource reports topology affinity with a NUMA node in the mask, or can be
+// allocated from any node because it reports no usable topology.
+func (m *ManagerImpl) resourceHostedOnMask(resource string, mask bitmask.BitMask) bool {
+	for _, device := range m.allDevices[resource] {
+		if !hasUsableTopology(device.Topology) || mask.AnySet(m.getNUMANodeIds(device.Topology)) {
+			return true
+		}
+	}
+	return false
+}
+
+// averageDistanceForMask returns the average NUMA distance between all pairs
+// of nodes in the mask, or false when the distance table has no entries for
+// them.
+func (m *ManagerImpl) averageDistanceForMask(mask bitmask.BitMask) (int, bool) {
+	nodes := mask.GetBits()
+	var sum, count uint64
+	for _, a := range nodes {
+		row := m.numaDistances[a]
+		for _, b := range nodes {
+			if b < len(row) {
+				// A sentinel distance means the span has no direct path;
+				// report it as-is so scoring treats the mask as unreachable
+				// instead of diluting it into the average.
+				if row[b] >= topologymanager.UnreachableNUMADistance {
+					return topologymanager.UnreachableNUMADistance, true
+				}
+				sum += row[b]
+				count++
+			}
+		}
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return int(sum / count), true
+}
+
+// averageDistanceToNodes returns the average NUMA distance from the nodes in
+// the mask to the given nodes, or false when the distance table has no
+// entries for them. A sentinel distance reports the span as unreachable.
+func (m *ManagerImpl) averageDistanceToNodes(mask bitmask.BitMask, nodes []int) (int, bool) {
+	var sum, count uint64
+	for _, a := range mask.GetBits() {
+		row := m.numaDistances[a]
+		for _, b := range nodes {
+			if b < len(row) {
+				if row[b] >= topologymanager.UnreachableNUMADistance {
+					return topologymanager.UnreachableNUMADistance, true
+				}
+				sum += row[b]
+				count++
+			}
+		}
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return int(sum / count), true
+}
+
 func (m *ManagerImpl) getNUMANodeIds(topology *pluginapi.TopologyInfo) []int {
 	if topology == nil {
 		return nil
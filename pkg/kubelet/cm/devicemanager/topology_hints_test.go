@@ -18,17 +18,23 @@ package devicemanager
 
 import (
 	"fmt"
+	"math"
 	"reflect"
-	"sort"
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"k8s.io/component-base/metrics/testutil"
+	draapi "k8s.io/dynamic-resource-allocation/api"
 	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+	"k8s.io/kubernetes/pkg/features"
 	"k8s.io/kubernetes/pkg/kubelet/cm/topologymanager"
 	"k8s.io/kubernetes/pkg/kubelet/cm/topologymanager/bitmask"
+	kubeletmetrics "k8s.io/kubernetes/pkg/kubelet/metrics"
 )
 
 type mockAffinityStore struct {
@@ -43,6 +49,10 @@ func (m *mockAffinityStore) GetPolicy() topologymanager.Policy {
 	return nil
 }
 
+func (m *mockAffinityStore) GetScopeName() string {
+	return ""
+}
+
 func makeNUMADevice(id string, numa int) *pluginapi.Device {
 	return &pluginapi.Device{
 		ID:       id,
@@ -95,12 +105,8 @@ func TestGetTopologyHints(t *testing.T) {
 		hints := m.GetTopologyHints(tc.pod, &tc.pod.Spec.Containers[0])
 
 		for r := range tc.expectedHints {
-			sort.SliceStable(hints[r], func(i, j int) bool {
-				return hints[r][i].LessThan(hints[r][j])
-			})
-			sort.SliceStable(tc.expectedHints[r], func(i, j int) bool {
-				return tc.expectedHints[r][i].LessThan(tc.expectedHints[r][j])
-			})
+			topologymanager.SortHints(hints[r])
+			topologymanager.SortHints(tc.expectedHints[r])
 			if !reflect.DeepEqual(hints[r], tc.expectedHints[r]) {
 				t.Errorf("%v: Expected result to be %#v, got %#v", tc.description, tc.expectedHints[r], hints[r])
 			}
@@ -108,6 +114,123 @@ func TestGetTopologyHints(t *testing.T) {
 	}
 }
 
+func TestGetTopologyHintsMixedTopologyDevices(t *testing.T) {
+	// A resource mixing topology-reporting and topology-less devices: Dev1 is
+	// pinned to node 0, Dev2 reports no topology at all, and Dev3 carries an
+	// empty topology. The topology-less devices count toward every mask, so a
+	// request for two devices is satisfiable on either single node instead of
+	// being rejected for lack of co-located pairs.
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			UID: "fakePod",
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name: "fakeContainer",
+					Resources: v1.ResourceRequirements{
+						Limits: v1.ResourceList{
+							v1.ResourceName("testdevice"): resource.MustParse("2"),
+						},
+					},
+				},
+			},
+		},
+	}
+	devices := []*pluginapi.Device{
+		makeNUMADevice("Dev1", 0),
+		{ID: "Dev2"},
+		{ID: "Dev3", Topology: &pluginapi.TopologyInfo{Nodes: []*pluginapi.NUMANode{}}},
+	}
+
+	m := ManagerImpl{
+		allDevices:       NewResourceDeviceInstances(),
+		healthyDevices:   make(map[string]sets.Set[string]),
+		allocatedDevices: make(map[string]sets.Set[string]),
+		podDevices:       newPodDevices(),
+		sourcesReady:     &sourcesReadyStub{},
+		activePods:       func() []*v1.Pod { return []*v1.Pod{pod} },
+		numaNodes:        []int{0, 1},
+	}
+	m.allDevices["testdevice"] = make(DeviceInstances)
+	m.healthyDevices["testdevice"] = sets.New[string]()
+	for _, d := range devices {
+		m.allDevices["testdevice"][d.ID] = d
+		m.healthyDevices["testdevice"].Insert(d.ID)
+	}
+
+	hints := m.GetTopologyHints(pod, &pod.Spec.Containers[0])
+
+	expected := []topologymanager.TopologyHint{
+		{
+			NUMANodeAffinity: makeSocketMask(0),
+			Preferred:        true,
+		},
+		{
+			NUMANodeAffinity: makeSocketMask(1),
+			Preferred:        true,
+		},
+		{
+			NUMANodeAffinity: makeSocketMask(0, 1),
+			Preferred:        false,
+		},
+	}
+	topologymanager.SortHints(hints["testdevice"])
+	topologymanager.SortHints(expected)
+	if !reflect.DeepEqual(hints["testdevice"], expected) {
+		t.Errorf("Expected result to be %#v, got %#v", expected, hints["testdevice"])
+	}
+}
+
+func TestGetTopologyHintsEnhancedScoreOrdering(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.EnhancedTopologyHints, true)
+
+	pod := makePod(v1.ResourceList{"testdevice": resource.MustParse("1")})
+	m := ManagerImpl{
+		allDevices:       NewResourceDeviceInstances(),
+		healthyDevices:   make(map[string]sets.Set[string]),
+		allocatedDevices: make(map[string]sets.Set[string]),
+		podDevices:       newPodDevices(),
+		sourcesReady:     &sourcesReadyStub{},
+		activePods:       func() []*v1.Pod { return []*v1.Pod{pod} },
+		numaNodes:        []int{0, 1},
+		// Node 1 is farther out than node 0, as reported by firmware.
+		numaDistances: map[int][]uint64{
+			0: {10, 20},
+			1: {20, 17},
+		},
+	}
+
+	m.allDevices["testdevice"] = make(DeviceInstances)
+	m.healthyDevices["testdevice"] = sets.New[string]()
+	for _, d := range []*pluginapi.Device{makeNUMADevice("Dev1", 0), makeNUMADevice("Dev2", 1)} {
+		m.allDevices["testdevice"][d.ID] = d
+		m.healthyDevices["testdevice"].Insert(d.ID)
+	}
+
+	hints := m.GetTopologyHints(pod, &pod.Spec.Containers[0])["testdevice"]
+	if len(hints) != 3 {
+		t.Fatalf("Expected 3 hints, got %#v", hints)
+	}
+
+	// Both single-node masks are minimal and therefore preferred, but node 0
+	// has the lower distance, so its hint must be ordered first.
+	if !hints[0].NUMANodeAffinity.IsEqual(makeSocketMask(0)) || !hints[0].Preferred {
+		t.Errorf("Expected preferred hint for NUMA node 0 first, got %#v", hints[0])
+	}
+	if !hints[1].NUMANodeAffinity.IsEqual(makeSocketMask(1)) || !hints[1].Preferred {
+		t.Errorf("Expected preferred hint for NUMA node 1 second, got %#v", hints[1])
+	}
+	if hints[0].GetScore() >= hints[1].GetScore() {
+		t.Errorf("Expected hint for node 0 to score better than node 1, got %v and %v", hints[0].GetScore(), hints[1].GetScore())
+	}
+	for i := range hints {
+		if hints[i].HopCount == nil || hints[i].Bandwidth == nil || hints[i].Score == nil {
+			t.Errorf("Expected enhanced fields to be populated, got %#v", hints[i])
+		}
+	}
+}
+
 func TestTopologyAlignedAllocation(t *testing.T) {
 	tcases := []struct {
 		description                 string
@@ -960,12 +1083,8 @@ func TestGetPodTopologyHints(t *testing.T) {
 		hints := m.GetPodTopologyHints(tc.pod)
 
 		for r := range tc.expectedHints {
-			sort.SliceStable(hints[r], func(i, j int) bool {
-				return hints[r][i].LessThan(hints[r][j])
-			})
-			sort.SliceStable(tc.expectedHints[r], func(i, j int) bool {
-				return tc.expectedHints[r][i].LessThan(tc.expectedHints[r][j])
-			})
+			topologymanager.SortHints(hints[r])
+			topologymanager.SortHints(tc.expectedHints[r])
 			if !reflect.DeepEqual(hints[r], tc.expectedHints[r]) {
 				t.Errorf("%v: Expected result to be %v, got %v", tc.description, tc.expectedHints[r], hints[r])
 			}
@@ -1039,8 +1158,14 @@ func getCommonTestCases() []topologyHintTestCase {
 					makeNUMADevice("Dev2", 1),
 				},
 			},
+			// Dev1 reports no topology and can be allocated from any NUMA
+			// node, so every mask can satisfy the request.
 			expectedHints: map[string][]topologymanager.TopologyHint{
 				"testdevice": {
+					{
+						NUMANodeAffinity: makeSocketMask(0),
+						Preferred:        true,
+					},
 					{
 						NUMANodeAffinity: makeSocketMask(1),
 						Preferred:        true,
@@ -1686,3 +1811,512 @@ func getPodScopeTestCases() []topologyHintTestCase {
 		},
 	}
 }
+
+func TestGetTopologyHintsEnhancedReusableDevices(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.EnhancedTopologyHints, true)
+
+	makeManager := func(pod *v1.Pod) *ManagerImpl {
+		m := &ManagerImpl{
+			allDevices:       NewResourceDeviceInstances(),
+			healthyDevices:   make(map[string]sets.Set[string]),
+			allocatedDevices: make(map[string]sets.Set[string]),
+			devicesToReuse:   make(PodReusableDevices),
+			podDevices:       newPodDevices(),
+			sourcesReady:     &sourcesReadyStub{},
+			activePods:       func() []*v1.Pod { return []*v1.Pod{pod} },
+			numaNodes:        []int{0, 1},
+			// A self-distance above the local baseline keeps the node 0
+			// score positive, so the reuse discount stays visible.
+			numaDistances: map[int][]uint64{
+				0: {15, 20},
+				1: {20, 15},
+			},
+		}
+		m.allDevices["testdevice"] = make(DeviceInstances)
+		m.healthyDevices["testdevice"] = sets.New[string]()
+		for _, d := range []*pluginapi.Device{makeNUMADevice("Dev1", 0), makeNUMADevice("Dev2", 1)} {
+			m.allDevices["testdevice"][d.ID] = d
+			m.healthyDevices["testdevice"].Insert(d.ID)
+		}
+		return m
+	}
+
+	findHint := func(hints []topologymanager.TopologyHint, mask bitmask.BitMask) *topologymanager.TopologyHint {
+		for i := range hints {
+			if hints[i].NUMANodeAffinity.IsEqual(mask) {
+				return &hints[i]
+			}
+		}
+		return nil
+	}
+
+	pod := makePod(v1.ResourceList{"testdevice": resource.MustParse("1")})
+
+	plain := makeManager(pod)
+	plainHints := plain.GetTopologyHints(pod, &pod.Spec.Containers[0])["testdevice"]
+	plainHint := findHint(plainHints, makeSocketMask(0))
+	if plainHint == nil {
+		t.Fatalf("Expected a hint for NUMA node 0, got %#v", plainHints)
+	}
+
+	reusing := makeManager(pod)
+	reusing.allocatedDevices["testdevice"] = sets.New[string]("Dev1")
+	reusing.devicesToReuse[string(pod.UID)] = map[string]sets.Set[string]{
+		"testdevice": sets.New[string]("Dev1"),
+	}
+	reusingHints := reusing.GetTopologyHints(pod, &pod.Spec.Containers[0])["testdevice"]
+	reusingHint := findHint(reusingHints, makeSocketMask(0))
+	if reusingHint == nil {
+		t.Fatalf("Expected a hint for NUMA node 0, got %#v", reusingHints)
+	}
+
+	// Reusing Dev1 on node 0 carries no new allocation cost, so the node 0
+	// hint must score better than the same hint without reuse.
+	if reusingHint.GetScore() >= plainHint.GetScore() {
+		t.Errorf("Expected node 0 to score better with a reusable device, got %v (reuse) and %v (no reuse)", reusingHint.GetScore(), plainHint.GetScore())
+	}
+}
+
+func TestGetTopologyHintsShareableDevices(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.EnhancedTopologyHints, true)
+
+	pod := makePod(v1.ResourceList{"testdevice": resource.MustParse("2")})
+
+	makeManager := func() *ManagerImpl {
+		m := &ManagerImpl{
+			allDevices:           NewResourceDeviceInstances(),
+			healthyDevices:       make(map[string]sets.Set[string]),
+			allocatedDevices:     make(map[string]sets.Set[string]),
+			devicesToReuse:       make(PodReusableDevices),
+			deviceSharingFactors: make(map[string]map[string]int),
+			podDevices:           newPodDevices(),
+			sourcesReady:         &sourcesReadyStub{},
+			activePods:           func() []*v1.Pod { return []*v1.Pod{pod} },
+			numaNodes:            []int{0, 1},
+		}
+		m.allDevices["testdevice"] = make(DeviceInstances)
+		m.healthyDevices["testdevice"] = sets.New[string]()
+		for _, d := range []*pluginapi.Device{makeNUMADevice("Dev1", 0), makeNUMADevice("Dev2", 1)} {
+			m.allDevices["testdevice"][d.ID] = d
+			m.healthyDevices["testdevice"].Insert(d.ID)
+		}
+		return m
+	}
+
+	bestScore := func(hints []topologymanager.TopologyHint) float64 {
+		if len(hints) == 0 {
+			t.Fatalf("Expected at least one hint")
+		}
+		best := hints[0].GetScore()
+		for _, hint := range hints[1:] {
+			if hint.GetScore() < best {
+				best = hint.GetScore()
+			}
+		}
+		return best
+	}
+
+	// With two exclusive devices on different nodes, a request for two can
+	// only be satisfied by spanning both nodes.
+	exclusive := makeManager()
+	exclusiveHints := exclusive.GetTopologyHints(pod, &pod.Spec.Containers[0])["testdevice"]
+	for _, hint := range exclusiveHints {
+		if hint.NUMANodeAffinity.Count() < 2 {
+			t.Errorf("Expected only multi-node hints for exclusive devices, got %v", hint)
+		}
+	}
+
+	// Marking Dev1 as backing two allocations makes a single-node placement
+	// feasible, which must score better than the exclusive spread.
+	shareable := makeManager()
+	shareable.SetDeviceSharingFactor("testdevice", "Dev1", 2)
+	shareableHints := shareable.GetTopologyHints(pod, &pod.Spec.Containers[0])["testdevice"]
+	node0Hint := findTestHint(t, shareableHints, makeSocketMask(0))
+	if !node0Hint.Preferred {
+		t.Errorf("Expected the single-node hint to be preferred for a shareable device, got %v", node0Hint)
+	}
+	if bestScore(shareableHints) >= bestScore(exclusiveHints) {
+		t.Errorf("Expected the shareable device placement to score better, got %v (shareable) and %v (exclusive)", bestScore(shareableHints), bestScore(exclusiveHints))
+	}
+}
+
+func findTestHint(t *testing.T, hints []topologymanager.TopologyHint, mask bitmask.BitMask) *topologymanager.TopologyHint {
+	t.Helper()
+	for i := range hints {
+		if hints[i].NUMANodeAffinity.IsEqual(mask) {
+			return &hints[i]
+		}
+	}
+	t.Fatalf("Expected a hint for mask %v, got %#v", mask, hints)
+	return nil
+}
+
+func TestGetTopologyHintsUninitializedMaps(t *testing.T) {
+	pod := makePod(v1.ResourceList{"testdevice": resource.MustParse("1")})
+
+	// healthyDevices and allocatedDevices are deliberately left nil to
+	// confirm hint generation treats missing resource keys as empty sets
+	// instead of panicking.
+	m := &ManagerImpl{
+		allDevices:   NewResourceDeviceInstances(),
+		podDevices:   newPodDevices(),
+		sourcesReady: &sourcesReadyStub{},
+		activePods:   func() []*v1.Pod { return []*v1.Pod{pod} },
+		numaNodes:    []int{0},
+	}
+	m.allDevices["testdevice"] = make(DeviceInstances)
+	m.allDevices["testdevice"]["Dev1"] = makeNUMADevice("Dev1", 0)
+
+	hints := m.GetTopologyHints(pod, &pod.Spec.Containers[0])["testdevice"]
+	if len(hints) != 0 {
+		t.Errorf("Expected empty hints with no healthy devices, got %v", hints)
+	}
+}
+
+func TestGetTopologyHintsConnectivityMatrixBandwidth(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.EnhancedTopologyHints, true)
+
+	// Two devices on different nodes force the request to span both, so the
+	// cross-node hint exercises the bandwidth estimate.
+	pod := makePod(v1.ResourceList{"testdevice": resource.MustParse("2")})
+
+	makeManager := func() *ManagerImpl {
+		m := &ManagerImpl{
+			allDevices:           NewResourceDeviceInstances(),
+			healthyDevices:       make(map[string]sets.Set[string]),
+			allocatedDevices:     make(map[string]sets.Set[string]),
+			devicesToReuse:       make(PodReusableDevices),
+			connectivityMatrices: make(map[string]map[int][]draapi.NodeConnectivity),
+			podDevices:           newPodDevices(),
+			sourcesReady:         &sourcesReadyStub{},
+			activePods:           func() []*v1.Pod { return []*v1.Pod{pod} },
+			numaNodes:            []int{0, 1},
+		}
+		m.allDevices["testdevice"] = make(DeviceInstances)
+		m.healthyDevices["testdevice"] = sets.New[string]()
+		for _, d := range []*pluginapi.Device{makeNUMADevice("Dev1", 0), makeNUMADevice("Dev2", 1)} {
+			m.allDevices["testdevice"][d.ID] = d
+			m.healthyDevices["testdevice"].Insert(d.ID)
+		}
+		return m
+	}
+
+	synthetic := makeManager()
+	syntheticHints := synthetic.GetTopologyHints(pod, &pod.Spec.Containers[0])["testdevice"]
+	syntheticHint := findTestHint(t, syntheticHints, makeSocketMask(0, 1))
+	expectedSynthetic := deviceBaseBandwidthGBs * (1 - deviceBandwidthHopPenalty)
+	if syntheticHint.GetBandwidth() != expectedSynthetic {
+		t.Fatalf("Expected the synthetic bandwidth %v without a matrix, got %v", expectedSynthetic, syntheticHint.GetBandwidth())
+	}
+
+	measuredBandwidth := 25.0
+	measured := makeManager()
+	measured.SetNUMATopology("testdevice", []draapi.NodeTopologyInfo{
+		{
+			NodeID: 0,
+			InterconnectInfo: &draapi.InterconnectInfo{
+				ConnectivityMatrix: []draapi.NodeConnectivity{
+					{TargetNodeID: 1, Bandwidth: &measuredBandwidth},
+				},
+			},
+		},
+		{
+			NodeID: 1,
+			InterconnectInfo: &draapi.InterconnectInfo{
+				ConnectivityMatrix: []draapi.NodeConnectivity{
+					{TargetNodeID: 0, Bandwidth: &measuredBandwidth},
+				},
+			},
+		},
+	})
+	measuredHints := measured.GetTopologyHints(pod, &pod.Spec.Containers[0])["testdevice"]
+	measuredHint := findTestHint(t, measuredHints, makeSocketMask(0, 1))
+	if measuredHint.GetBandwidth() != measuredBandwidth {
+		t.Errorf("Expected the matrix bandwidth %v to override the synthetic estimate, got %v", measuredBandwidth, measuredHint.GetBandwidth())
+	}
+
+	// A matrix missing one direction of the pair cannot price the span, so
+	// the synthetic model still applies.
+	partial := makeManager()
+	partial.SetNUMATopology("testdevice", []draapi.NodeTopologyInfo{
+		{
+			NodeID: 0,
+			InterconnectInfo: &draapi.InterconnectInfo{
+				ConnectivityMatrix: []draapi.NodeConnectivity{
+					{TargetNodeID: 1, Bandwidth: &measuredBandwidth},
+				},
+			},
+		},
+	})
+	partialHints := partial.GetTopologyHints(pod, &pod.Spec.Containers[0])["testdevice"]
+	partialHint := findTestHint(t, partialHints, makeSocketMask(0, 1))
+	if partialHint.GetBandwidth() != expectedSynthetic {
+		t.Errorf("Expected an incomplete matrix to fall back to the synthetic bandwidth %v, got %v", expectedSynthetic, partialHint.GetBandwidth())
+	}
+}
+
+// constantBandwidthModel reports the same bandwidth for every mask,
+// regardless of resource or hop count.
+type constantBandwidthModel float64
+
+func (b constantBandwidthModel) Bandwidth(resource string, nodes []int) float64 {
+	return float64(b)
+}
+
+func TestGetTopologyHintsCustomBandwidthModel(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.EnhancedTopologyHints, true)
+
+	pod := makePod(v1.ResourceList{"testdevice": resource.MustParse("2")})
+
+	m := &ManagerImpl{
+		allDevices:           NewResourceDeviceInstances(),
+		healthyDevices:       make(map[string]sets.Set[string]),
+		allocatedDevices:     make(map[string]sets.Set[string]),
+		devicesToReuse:       make(PodReusableDevices),
+		connectivityMatrices: make(map[string]map[int][]draapi.NodeConnectivity),
+		podDevices:           newPodDevices(),
+		sourcesReady:         &sourcesReadyStub{},
+		activePods:           func() []*v1.Pod { return []*v1.Pod{pod} },
+		numaNodes:            []int{0, 1},
+	}
+	m.allDevices["testdevice"] = make(DeviceInstances)
+	m.healthyDevices["testdevice"] = sets.New[string]()
+	for _, d := range []*pluginapi.Device{makeNUMADevice("Dev1", 0), makeNUMADevice("Dev2", 1)} {
+		m.allDevices["testdevice"][d.ID] = d
+		m.healthyDevices["testdevice"].Insert(d.ID)
+	}
+
+	m.SetBandwidthModel(constantBandwidthModel(42))
+	hints := m.GetTopologyHints(pod, &pod.Spec.Containers[0])["testdevice"]
+	hint := findTestHint(t, hints, makeSocketMask(0, 1))
+	if hint.GetBandwidth() != 42 {
+		t.Fatalf("Expected the injected model's bandwidth 42, got %v", hint.GetBandwidth())
+	}
+
+	// A nil model resets the manager to the synthetic default.
+	m.SetBandwidthModel(nil)
+	hints = m.GetTopologyHints(pod, &pod.Spec.Containers[0])["testdevice"]
+	hint = findTestHint(t, hints, makeSocketMask(0, 1))
+	expected := deviceBaseBandwidthGBs * (1 - deviceBandwidthHopPenalty)
+	if hint.GetBandwidth() != expected {
+		t.Errorf("Expected the default synthetic bandwidth %v after reset, got %v", expected, hint.GetBandwidth())
+	}
+}
+
+func TestGetTopologyHintsCPUProximity(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.EnhancedTopologyHints, true)
+
+	pod := makePod(v1.ResourceList{"testdevice": resource.MustParse("1")})
+
+	m := &ManagerImpl{
+		allDevices:       NewResourceDeviceInstances(),
+		healthyDevices:   make(map[string]sets.Set[string]),
+		allocatedDevices: make(map[string]sets.Set[string]),
+		devicesToReuse:   make(PodReusableDevices),
+		podDevices:       newPodDevices(),
+		sourcesReady:     &sourcesReadyStub{},
+		activePods:       func() []*v1.Pod { return []*v1.Pod{pod} },
+		numaNodes:        []int{0, 1},
+		numaDistances: map[int][]uint64{
+			0: {10, 20},
+			1: {20, 10},
+		},
+	}
+	m.allDevices["testdevice"] = make(DeviceInstances)
+	m.healthyDevices["testdevice"] = sets.New[string]()
+	for _, d := range []*pluginapi.Device{makeNUMADevice("Dev1", 0), makeNUMADevice("Dev2", 1)} {
+		m.allDevices["testdevice"][d.ID] = d
+		m.healthyDevices["testdevice"].Insert(d.ID)
+	}
+
+	// Without a lookup wired, equally available devices on either node score
+	// the same.
+	hints := m.GetTopologyHints(pod, &pod.Spec.Containers[0])["testdevice"]
+	local := findTestHint(t, hints, makeSocketMask(0))
+	remote := findTestHint(t, hints, makeSocketMask(1))
+	if local.GetScore() != remote.GetScore() {
+		t.Fatalf("Expected equal scores without CPU placement, got %v and %v", local.GetScore(), remote.GetScore())
+	}
+
+	// The container's CPUs sit on node 0, so the device on node 0 must score
+	// better than the equally available one on node 1.
+	m.SetCPUNUMAAffinityLookup(func(podUID, containerName string) []int {
+		return []int{0}
+	})
+	hints = m.GetTopologyHints(pod, &pod.Spec.Containers[0])["testdevice"]
+	local = findTestHint(t, hints, makeSocketMask(0))
+	remote = findTestHint(t, hints, makeSocketMask(1))
+	if local.GetScore() >= remote.GetScore() {
+		t.Errorf("Expected the device on the CPUs' node to score better, got local %v and remote %v", local.GetScore(), remote.GetScore())
+	}
+
+	// CPUs spanning every NUMA node carry no locality signal.
+	m.SetCPUNUMAAffinityLookup(func(podUID, containerName string) []int {
+		return []int{0, 1}
+	})
+	hints = m.GetTopologyHints(pod, &pod.Spec.Containers[0])["testdevice"]
+	local = findTestHint(t, hints, makeSocketMask(0))
+	remote = findTestHint(t, hints, makeSocketMask(1))
+	if local.GetScore() != remote.GetScore() {
+		t.Errorf("Expected equal scores for a shared pool spanning all nodes, got %v and %v", local.GetScore(), remote.GetScore())
+	}
+}
+
+func TestGetTopologyHintsReclaimsStrandedDevices(t *testing.T) {
+	// The metric is normally created during registration; force creation so
+	// its value can be read in this test.
+	kubeletmetrics.DeviceManagerReclaimedDevices.Create(nil)
+
+	reclaimedMetricValue := func() float64 {
+		value, err := testutil.GetCounterMetricValue(kubeletmetrics.DeviceManagerReclaimedDevices)
+		if err != nil {
+			t.Fatalf("Could not read reclaimed devices metric: %v", err)
+		}
+		return value
+	}
+
+	pod := makePod(v1.ResourceList{"testdevice": resource.MustParse("1")})
+
+	m := &ManagerImpl{
+		allDevices:       NewResourceDeviceInstances(),
+		healthyDevices:   make(map[string]sets.Set[string]),
+		allocatedDevices: make(map[string]sets.Set[string]),
+		podDevices:       newPodDevices(),
+		sourcesReady:     &sourcesReadyStub{},
+		activePods:       func() []*v1.Pod { return []*v1.Pod{pod} },
+		numaNodes:        []int{0, 1},
+	}
+	m.allDevices["testdevice"] = make(DeviceInstances)
+	m.healthyDevices["testdevice"] = sets.New[string]()
+	for _, d := range []*pluginapi.Device{makeNUMADevice("Dev1", 0), makeNUMADevice("Dev2", 1)} {
+		m.allDevices["testdevice"][d.ID] = d
+		m.healthyDevices["testdevice"].Insert(d.ID)
+	}
+
+	// Strand Dev1 on a pod that is no longer active.
+	m.podDevices.insert("deletedPod", "container", "testdevice", constructDevices([]string{"Dev1"}), newContainerAllocateResponse())
+	m.allocatedDevices["testdevice"] = sets.New[string]("Dev1")
+
+	before := reclaimedMetricValue()
+	m.GetTopologyHints(pod, &pod.Spec.Containers[0])
+	if delta := reclaimedMetricValue() - before; delta != 1 {
+		t.Errorf("Expected the reclaimed devices metric to increment by 1, got %v", delta)
+	}
+	if m.allocatedDevices["testdevice"].Has("Dev1") {
+		t.Errorf("Expected Dev1 to be reclaimed from the stranded pod")
+	}
+
+	// A second reconcile with nothing stranded reports nothing reclaimed.
+	if reclaimed := m.UpdateAllocatedDevices(); reclaimed != 0 {
+		t.Errorf("Expected no devices to be reclaimed on a clean state, got %d", reclaimed)
+	}
+}
+
+func TestGetTopologyHintsDeviceTypeDiversity(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.EnhancedTopologyHints, true)
+
+	pod := makePod(v1.ResourceList{
+		"example.com/gpu": resource.MustParse("1"),
+		"example.com/nic": resource.MustParse("1"),
+	})
+
+	// Node 0 hosts a GPU and a NIC; node 1 hosts only a GPU.
+	m := &ManagerImpl{
+		allDevices:       NewResourceDeviceInstances(),
+		healthyDevices:   make(map[string]sets.Set[string]),
+		allocatedDevices: make(map[string]sets.Set[string]),
+		devicesToReuse:   make(PodReusableDevices),
+		podDevices:       newPodDevices(),
+		sourcesReady:     &sourcesReadyStub{},
+		activePods:       func() []*v1.Pod { return []*v1.Pod{pod} },
+		numaNodes:        []int{0, 1},
+	}
+	devices := map[string][]*pluginapi.Device{
+		"example.com/gpu": {makeNUMADevice("Gpu0", 0), makeNUMADevice("Gpu1", 1)},
+		"example.com/nic": {makeNUMADevice("Nic0", 0)},
+	}
+	for resourceName, devs := range devices {
+		m.allDevices[resourceName] = make(DeviceInstances)
+		m.healthyDevices[resourceName] = sets.New[string]()
+		for _, d := range devs {
+			m.allDevices[resourceName][d.ID] = d
+			m.healthyDevices[resourceName].Insert(d.ID)
+		}
+	}
+
+	gpuHints := m.GetTopologyHints(pod, &pod.Spec.Containers[0])["example.com/gpu"]
+
+	findHint := func(hints []topologymanager.TopologyHint, mask bitmask.BitMask) *topologymanager.TopologyHint {
+		for i := range hints {
+			if hints[i].NUMANodeAffinity.IsEqual(mask) {
+				return &hints[i]
+			}
+		}
+		return nil
+	}
+	node0Hint := findHint(gpuHints, makeSocketMask(0))
+	node1Hint := findHint(gpuHints, makeSocketMask(1))
+	if node0Hint == nil || node1Hint == nil {
+		t.Fatalf("Expected GPU hints for both NUMA nodes, got %#v", gpuHints)
+	}
+
+	// Node 1 has no NIC, so its GPU hint must carry the diversity penalty and
+	// score worse than node 0, which can host both requested device types.
+	if node0Hint.GetScore() >= node1Hint.GetScore() {
+		t.Errorf("Expected node 0 to score better than node 1 for a GPU+NIC pod, got %v (node 0) and %v (node 1)", node0Hint.GetScore(), node1Hint.GetScore())
+	}
+}
+
+func TestGetTopologyHintsFiniteScoresWithStaleTopology(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.EnhancedTopologyHints, true)
+
+	pod := makePod(v1.ResourceList{
+		"example.com/gpu": resource.MustParse("1"),
+		"example.com/nic": resource.MustParse("1"),
+	})
+
+	// Stale topology: the NIC devices report NUMA node 2, which the manager no
+	// longer knows about, so no mask over the live nodes matches any of them.
+	// Scoring a mask without matching devices must never divide through a zero
+	// device count or otherwise produce a NaN or infinite score.
+	m := &ManagerImpl{
+		allDevices:       NewResourceDeviceInstances(),
+		healthyDevices:   make(map[string]sets.Set[string]),
+		allocatedDevices: make(map[string]sets.Set[string]),
+		devicesToReuse:   make(PodReusableDevices),
+		podDevices:       newPodDevices(),
+		sourcesReady:     &sourcesReadyStub{},
+		activePods:       func() []*v1.Pod { return []*v1.Pod{pod} },
+		numaNodes:        []int{0, 1},
+	}
+	devices := map[string][]*pluginapi.Device{
+		"example.com/gpu": {makeNUMADevice("Gpu0", 0), makeNUMADevice("Gpu1", 1)},
+		"example.com/nic": {makeNUMADevice("Nic0", 2)},
+	}
+	for resourceName, devs := range devices {
+		m.allDevices[resourceName] = make(DeviceInstances)
+		m.healthyDevices[resourceName] = sets.New[string]()
+		for _, d := range devs {
+			m.allDevices[resourceName][d.ID] = d
+			m.healthyDevices[resourceName].Insert(d.ID)
+		}
+	}
+
+	hints := m.GetTopologyHints(pod, &pod.Spec.Containers[0])
+
+	// The stale NIC yields no feasible hints, while every GPU hint carries the
+	// diversity penalty for it; all committed scores must stay finite.
+	if len(hints["example.com/nic"]) != 0 {
+		t.Errorf("Expected no hints for a resource with only off-node devices, got %#v", hints["example.com/nic"])
+	}
+	gpuHints := hints["example.com/gpu"]
+	if len(gpuHints) == 0 {
+		t.Fatalf("Expected GPU hints to be generated, got none")
+	}
+	for _, hint := range gpuHints {
+		score := hint.GetScore()
+		if math.IsNaN(score) || math.IsInf(score, 0) {
+			t.Errorf("Expected a finite score for hint %v, got %v", hint, score)
+		}
+	}
+}
@@ -87,8 +87,19 @@ type Manager interface {
 	// and is consulted to make Topology aware resource alignments per Pod
 	GetPodTopologyHints(pod *v1.Pod) map[string][]topologymanager.TopologyHint
 
-	// UpdateAllocatedDevices frees any Devices that are bound to terminated pods.
-	UpdateAllocatedDevices()
+	// UpdateAllocatedDevices frees any Devices that are bound to terminated pods
+	// and returns the number of stale device entries reclaimed.
+	UpdateAllocatedDevices() int
+
+	// SetCPUNUMAAffinityLookup wires the function used to look up the NUMA
+	// nodes hosting a container's committed CPUs, so enhanced hint scoring
+	// can prefer devices close to them.
+	SetCPUNUMAAffinityLookup(lookup func(podUID, containerName string) []int)
+
+	// SetTopologyPolicyOptions supplies the topology manager's parsed policy
+	// options, so enhanced hint scoring uses the configured local distance
+	// baseline and rounding instead of the defaults.
+	SetTopologyPolicyOptions(opts topologymanager.PolicyOptions)
 
 	// Updates returns a channel that receives an Update when the device changed its status.
 	Updates() <-chan resourceupdates.Update
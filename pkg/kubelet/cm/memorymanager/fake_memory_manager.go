@@ -83,6 +83,24 @@ func (m *fakeManager) GetPodTopologyHints(pod *v1.Pod) map[string][]topologymana
 	return map[string][]topologymanager.TopologyHint{}
 }
 
+func (m *fakeManager) SetInterconnectInfoSource(source InterconnectInfoSource) {
+	ctx := context.TODO()
+	logger := klog.FromContext(ctx)
+	logger.Info("Set interconnect info source")
+}
+
+func (m *fakeManager) SetFreeFractionThreshold(threshold float64) {
+	ctx := context.TODO()
+	logger := klog.FromContext(ctx)
+	logger.Info("Set free fraction threshold", "threshold", threshold)
+}
+
+func (m *fakeManager) SetTopologyPolicyOptions(opts topologymanager.PolicyOptions) {
+	ctx := context.TODO()
+	logger := klog.FromContext(ctx)
+	logger.Info("Set topology policy options")
+}
+
 func (m *fakeManager) State() state.Reader {
 	return m.state
 }
@@ -92,6 +92,24 @@ type Manager interface {
 
 	// GetMemory returns the memory allocated by a container from NUMA nodes
 	GetMemory(ctx context.Context, podUID, containerName string) []state.Block
+
+	// SetInterconnectInfoSource supplies an optional source of measured
+	// per-NUMA-node interconnect characteristics used when populating
+	// enhanced topology hints. Without one, synthetic estimates are used.
+	// Only the static policy consumes it.
+	SetInterconnectInfoSource(source InterconnectInfoSource)
+
+	// SetFreeFractionThreshold sets the free/allocatable ratio below which
+	// a NUMA node is considered under memory pressure and penalized in
+	// enhanced hint scoring, from the memory-pressure-free-fraction
+	// topology manager policy option. Only the static policy consumes it.
+	SetFreeFractionThreshold(threshold float64)
+
+	// SetTopologyPolicyOptions supplies the topology manager's parsed policy
+	// options, so enhanced hint scoring uses the configured local distance
+	// baseline and rounding instead of the defaults. Only the static policy
+	// consumes them.
+	SetTopologyPolicyOptions(opts topologymanager.PolicyOptions)
 }
 
 type manager struct {
@@ -478,3 +496,37 @@ func (m *manager) GetAllocatableMemory(ctx context.Context) []state.Block {
 func (m *manager) GetMemory(ctx context.Context, podUID, containerName string) []state.Block {
 	return m.state.GetMemoryBlocks(podUID, containerName)
 }
+
+// SetInterconnectInfoSource supplies measured per-NUMA-node interconnect
+// characteristics for enhanced hint scoring. Only the static policy
+// consumes it.
+func (m *manager) SetInterconnectInfoSource(source InterconnectInfoSource) {
+	m.Lock()
+	defer m.Unlock()
+
+	if policy, ok := m.policy.(*staticPolicy); ok {
+		policy.interconnectInfo = source
+	}
+}
+
+// SetFreeFractionThreshold sets the memory pressure threshold used in
+// enhanced hint scoring. Only the static policy consumes it.
+func (m *manager) SetFreeFractionThreshold(threshold float64) {
+	m.Lock()
+	defer m.Unlock()
+
+	if policy, ok := m.policy.(*staticPolicy); ok {
+		policy.freeFractionThreshold = threshold
+	}
+}
+
+// SetTopologyPolicyOptions supplies the topology manager's parsed policy
+// options for enhanced hint scoring. Only the static policy consumes them.
+func (m *manager) SetTopologyPolicyOptions(opts topologymanager.PolicyOptions) {
+	m.Lock()
+	defer m.Unlock()
+
+	if policy, ok := m.policy.(*staticPolicy); ok {
+		policy.topologyOpts = opts
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"sync"
 
 	"github.com/go-logr/logr"
 	cadvisorapi "github.com/google/cadvisor/info/v1"
@@ -41,9 +42,46 @@ import (
 
 const PolicyTypeStatic policyType = "Static"
 
+// MinMemoryPerNodeAnnotation is a pod annotation expressing the minimum
+// amount of memory every NUMA node spanned by the pod's memory allocation
+// must provide. Workloads that need NUMA-balanced memory (e.g. NUMA-aware
+// JVM heaps) can use it to prevent lopsided spreads where one of the spanned
+// nodes contributes only a sliver of the request.
+const MinMemoryPerNodeAnnotation = "topology.kubernetes.io/min-memory-per-node"
+
+const (
+	// memoryHopDistanceStep is the synthetic per-hop distance increment used
+	// when populating enhanced hints.
+	memoryHopDistanceStep = 15
+	// defaultFreeFractionThreshold disables the memory-pressure score
+	// penalty; a node is only penalized when its free/allocatable ratio
+	// falls below the threshold.
+	defaultFreeFractionThreshold = 0.0
+	// memoryPressurePenaltyFactor scales the score penalty per unit of
+	// free-fraction shortfall below the threshold.
+	memoryPressurePenaltyFactor = 100.0
+	// systemReservedPenaltyFactor scales the score penalty per unit of the
+	// fraction of a node's memory reserved for system components.
+	systemReservedPenaltyFactor = 10.0
+)
+
 type systemReservedMemory map[int]map[v1.ResourceName]uint64
 type reusableMemory map[string]map[string]map[v1.ResourceName]uint64
 
+// NodeInterconnectInfo carries measured memory-fabric characteristics of a
+// single NUMA node, e.g. sourced from a ResourceSlice or extended machine
+// info.
+type NodeInterconnectInfo struct {
+	// Distance is the measured access distance of the node, in SLIT units.
+	Distance int
+	// Bandwidth is the measured memory bandwidth of the node in GB/s.
+	Bandwidth float64
+}
+
+// InterconnectInfoSource returns the measured interconnect info of a NUMA
+// node, or nil when none is known for it.
+type InterconnectInfoSource func(nodeID int) *NodeInterconnectInfo
+
 // staticPolicy is implementation of the policy interface for the static policy
 type staticPolicy struct {
 	// machineInfo contains machine memory related information
@@ -57,6 +95,20 @@ type staticPolicy struct {
 	// Note that the restartable init container memory is not included here,
 	// because it is not reusable.
 	initContainersReusableMemory reusableMemory
+	// freeFractionThreshold is the free/allocatable ratio below which a NUMA
+	// node is considered under memory pressure and penalized in enhanced
+	// hint scoring, configured through the memory-pressure-free-fraction
+	// topology manager policy option. The default of 0 disables the penalty.
+	freeFractionThreshold float64
+	// interconnectInfo optionally supplies measured per-node interconnect
+	// characteristics. When set, measured distance and bandwidth replace the
+	// synthetic estimates in enhanced hints for the nodes it covers; nil
+	// keeps the synthetic model.
+	interconnectInfo InterconnectInfoSource
+	// topologyOpts holds the topology manager's parsed policy options, wired
+	// by the container manager. The zero value scores hints with the default
+	// local distance baseline and no rounding.
+	topologyOpts topologymanager.PolicyOptions
 }
 
 var _ Policy = &staticPolicy{}
@@ -81,6 +133,7 @@ func NewPolicyStatic(ctx context.Context, machineInfo *cadvisorapi.MachineInfo,
 		systemReserved:               reserved,
 		affinity:                     affinity,
 		initContainersReusableMemory: reusableMemory{},
+		freeFractionThreshold:        defaultFreeFractionThreshold,
 	}, nil
 }
 
@@ -313,7 +366,7 @@ func (p *staticPolicy) RemoveContainer(ctx context.Context, s state.State, podUI
 	s.SetMachineState(machineState)
 }
 
-func regenerateHints(logger logr.Logger, pod *v1.Pod, ctn *v1.Container, ctnBlocks []state.Block, reqRsrc map[v1.ResourceName]uint64) map[string][]topologymanager.TopologyHint {
+func (p *staticPolicy) regenerateHints(logger logr.Logger, pod *v1.Pod, ctn *v1.Container, ctnBlocks []state.Block, reqRsrc map[v1.ResourceName]uint64) map[string][]topologymanager.TopologyHint {
 	hints := map[string][]topologymanager.TopologyHint{}
 	for resourceName := range reqRsrc {
 		hints[string(resourceName)] = []topologymanager.TopologyHint{}
@@ -342,10 +395,22 @@ func regenerateHints(logger logr.Logger, pod *v1.Pod, ctn *v1.Container, ctnBloc
 		}
 
 		logger.Info("Regenerating TopologyHints, resource was already allocated to pod", "resourceName", b.Type, "podUID", pod.UID, "containerName", ctn.Name)
-		hints[string(b.Type)] = append(hints[string(b.Type)], topologymanager.TopologyHint{
-			NUMANodeAffinity: containerNUMAAffinity,
-			Preferred:        true,
-		})
+		// The memory is already placed on these nodes, so re-pinning is
+		// free: give the regenerated hint the best possible score
+		// regardless of the mask's width, keeping re-admission after a
+		// kubelet restart stable. The builder drops the enhanced fields
+		// when the feature gate is off.
+		hint, err := topologymanager.NewHintBuilder(containerNUMAAffinity).
+			Preferred(true).
+			Hop(0).
+			Distance(p.topologyOpts.LocalDistance()).
+			Score(0).
+			Build()
+		if err != nil {
+			logger.Error(err, "Failed to build regenerated topology hint", "containerName", ctn.Name, "type", b.Type)
+			return nil
+		}
+		hints[string(b.Type)] = append(hints[string(b.Type)], hint)
 	}
 	return hints
 }
@@ -429,7 +494,7 @@ func (p *staticPolicy) GetPodTopologyHints(ctx context.Context, s state.State, p
 		// memory allocated for the container. This might happen after a
 		// kubelet restart, for example.
 		if containerBlocks != nil {
-			return regenerateHints(logger, pod, &ctn, containerBlocks, reqRsrcs)
+			return p.regenerateHints(logger, pod, &ctn, containerBlocks, reqRsrcs)
 		}
 	}
 
@@ -463,7 +528,7 @@ func (p *staticPolicy) GetTopologyHints(ctx context.Context, s state.State, pod
 	// memory allocated for the container. This might happen after a
 	// kubelet restart, for example.
 	if containerBlocks != nil {
-		return regenerateHints(logger, pod, container, containerBlocks, requestedResources)
+		return p.regenerateHints(logger, pod, container, containerBlocks, requestedResources)
 	}
 
 	return p.calculateHints(s.GetMachineState(), pod, requestedResources)
@@ -494,7 +559,21 @@ func (p *staticPolicy) calculateHints(machineState state.NUMANodeMap, pod *v1.Po
 	// Initialize minAffinitySize to include all NUMA Cells.
 	minAffinitySize := len(numaNodes)
 
+	minMemoryPerNode, err := getRequestedMinMemoryPerNode(pod)
+	if err != nil {
+		klog.ErrorS(err, "Ignoring invalid minimum memory per NUMA node annotation", "pod", klog.KObj(pod), "annotation", MinMemoryPerNodeAnnotation)
+		minMemoryPerNode = 0
+	}
+
 	hints := map[string][]topologymanager.TopologyHint{}
+	if minMemoryPerNode > 0 {
+		// Initialize the hints for every requested resource, so that an
+		// unsatisfiable per-node minimum surfaces as an empty hint list
+		// (rejecting the pod) rather than as "no preference".
+		for resourceName := range requestedResources {
+			hints[string(resourceName)] = []topologymanager.TopologyHint{}
+		}
+	}
 	bitmask.IterateBitMasks(numaNodes, func(mask bitmask.BitMask) {
 		maskBits := mask.GetBits()
 		singleNUMAHint := len(maskBits) == 1
@@ -556,15 +635,29 @@ func (p *staticPolicy) calculateHints(machineState state.NUMANodeMap, pod *v1.Po
 			}
 		}
 
+		// verify that every node spanned by the mask can provide the
+		// requested per-node minimum of free memory
+		if minMemoryPerNode > 0 {
+			for _, nodeID := range maskBits {
+				if machineState[nodeID].MemoryMap[v1.ResourceMemory].Free < minMemoryPerNode {
+					return
+				}
+			}
+		}
+
 		// add the node mask as topology hint for all memory types
 		for resourceName := range requestedResources {
 			if _, ok := hints[string(resourceName)]; !ok {
 				hints[string(resourceName)] = []topologymanager.TopologyHint{}
 			}
-			hints[string(resourceName)] = append(hints[string(resourceName)], topologymanager.TopologyHint{
+			hint := topologymanager.TopologyHint{
 				NUMANodeAffinity: mask,
 				Preferred:        false,
-			})
+			}
+			if utilfeature.DefaultFeatureGate.Enabled(features.EnhancedTopologyHints) {
+				p.calculateEnhancedTopologyFields(&hint, machineState, maskBits, resourceName)
+			}
+			hints[string(resourceName)] = append(hints[string(resourceName)], hint)
 		}
 	})
 
@@ -572,13 +665,121 @@ func (p *staticPolicy) calculateHints(machineState state.NUMANodeMap, pod *v1.Po
 	// behaviour to prefer the minimal amount of NUMA nodes will be used
 	for resourceName := range requestedResources {
 		for i, hint := range hints[string(resourceName)] {
-			hints[string(resourceName)][i].Preferred = p.isHintPreferred(hint.NUMANodeAffinity.GetBits(), minAffinitySize)
+			hints[string(resourceName)][i].Preferred = p.isHintPreferred(hint.NodeIDs(), minAffinitySize)
 		}
 	}
 
+	if minMemoryPerNode > 0 && len(hints[string(v1.ResourceMemory)]) == 0 {
+		klog.InfoS("No NUMA node mask can provide the requested minimum memory on every spanned node", "pod", klog.KObj(pod), "minMemoryPerNode", minMemoryPerNode)
+	}
+
 	return hints
 }
 
+// getRequestedMinMemoryPerNode returns the minimum amount of memory every
+// NUMA node spanned by the pod's memory allocation must provide, as requested
+// via the MinMemoryPerNodeAnnotation annotation. It returns 0 when the
+// annotation is absent.
+func getRequestedMinMemoryPerNode(pod *v1.Pod) (uint64, error) {
+	value, ok := pod.Annotations[MinMemoryPerNodeAnnotation]
+	if !ok {
+		return 0, nil
+	}
+
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse annotation %q: %w", MinMemoryPerNodeAnnotation, err)
+	}
+
+	minMemoryPerNode, succeed := quantity.AsInt64()
+	if !succeed || minMemoryPerNode < 0 {
+		return 0, fmt.Errorf("annotation %q must be a non-negative quantity representable as int64, got %q", MinMemoryPerNodeAnnotation, value)
+	}
+
+	return uint64(minMemoryPerNode), nil
+}
+
+// logZeroAllocatableOnce limits the misreported-capacity log line to once per
+// kubelet run, since a misreporting cadvisor would otherwise repeat it on
+// every admission.
+var logZeroAllocatableOnce sync.Once
+
+// calculateEnhancedTopologyFields populates the enhanced fields of a hint
+// spanning the given NUMA nodes. The hop count is the number of node
+// crossings the mask implies and the distance is a synthetic estimate
+// derived from it. On top of the base topology score, every spanned node
+// whose free/allocatable ratio for the resource falls below the configured
+// threshold adds a memory-pressure penalty proportional to its shortfall, so
+// masks touching nodes at risk of reclaim stalls score worse than
+// equally-distant ones with ample free memory. Every spanned node also adds
+// a penalty proportional to the fraction of its memory reserved for system
+// components: the allocatable-based free fraction cannot see reservations,
+// yet a heavily reserved node has less absolute headroom and more system
+// activity competing for its memory controller. Hugepage resources use the
+// same per-node MemoryTable accounting as regular memory, just keyed by
+// their own resource name.
+func (p *staticPolicy) calculateEnhancedTopologyFields(hint *topologymanager.TopologyHint, machineState state.NUMANodeMap, maskBits []int, resourceName v1.ResourceName) {
+	hopCount := len(maskBits) - 1
+	// The memory manager has no distance table of its own, so it estimates
+	// the distance of a mask from the topology manager's local distance
+	// baseline and the number of NUMA hops.
+	distance := p.topologyOpts.LocalDistance() + hopCount*memoryHopDistanceStep
+	hint.HopCount = &hopCount
+	hint.Distance = &distance
+
+	// Measured interconnect data, when available for the spanned nodes,
+	// replaces the synthetic distance estimate. The slowest spanned node
+	// bounds the fabric the allocation sees, so the worst measured distance
+	// and the smallest measured bandwidth win.
+	if p.interconnectInfo != nil {
+		var measuredDistance *int
+		var measuredBandwidth *float64
+		for _, nodeID := range maskBits {
+			info := p.interconnectInfo(nodeID)
+			if info == nil {
+				continue
+			}
+			if measuredDistance == nil || info.Distance > *measuredDistance {
+				measuredDistance = &info.Distance
+			}
+			if measuredBandwidth == nil || info.Bandwidth < *measuredBandwidth {
+				measuredBandwidth = &info.Bandwidth
+			}
+		}
+		if measuredDistance != nil {
+			hint.Distance = measuredDistance
+		}
+		if measuredBandwidth != nil {
+			hint.Bandwidth = measuredBandwidth
+		}
+	}
+
+	score := topologymanager.CalculateTopologyScore(hint.GetHopCount(), hint.GetDistance(), hint.GetBandwidth(), 0, p.topologyOpts)
+	for _, nodeID := range maskBits {
+		memoryTable := machineState[nodeID].MemoryMap[resourceName]
+		if memoryTable == nil {
+			continue
+		}
+		if memoryTable.TotalMemSize > 0 && memoryTable.SystemReserved > 0 {
+			score += float64(memoryTable.SystemReserved) / float64(memoryTable.TotalMemSize) * systemReservedPenaltyFactor
+		}
+		if memoryTable.Allocatable == 0 {
+			// A zero allocatable capacity means cadvisor misreported the
+			// node's MachineInfo; skip the pressure term rather than divide
+			// by zero.
+			logZeroAllocatableOnce.Do(func() {
+				klog.InfoS("NUMA node reports no allocatable capacity, skipping the memory pressure term for it", "node", nodeID, "resourceName", resourceName)
+			})
+			continue
+		}
+		freeFraction := float64(memoryTable.Free) / float64(memoryTable.Allocatable)
+		if freeFraction < p.freeFractionThreshold {
+			score += (p.freeFractionThreshold - freeFraction) * memoryPressurePenaltyFactor
+		}
+	}
+	hint.Score = &score
+}
+
 func (p *staticPolicy) isHintPreferred(maskBits []int, minAffinitySize int) bool {
 	return len(maskBits) == minAffinitySize
 }
@@ -852,7 +1053,7 @@ func (p *staticPolicy) extendTopologyManagerHint(machineState state.NUMANodeMap,
 	var filteredHints []topologymanager.TopologyHint
 	// hints for all memory types should be the same, so we will check hints only for regular memory type
 	for _, hint := range hints[string(v1.ResourceMemory)] {
-		affinityBits := hint.NUMANodeAffinity.GetBits()
+		affinityBits := hint.NodeIDs()
 		// filter all hints that does not include currentHint
 		if isHintInGroup(mask.GetBits(), affinityBits) {
 			filteredHints = append(filteredHints, hint)
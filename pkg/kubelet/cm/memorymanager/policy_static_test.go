@@ -18,6 +18,7 @@ package memorymanager
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"testing"
 
@@ -4211,8 +4212,674 @@ func Test_isAffinityViolatingNUMAAllocations(t *testing.T) {
 	for _, tc := range testsCases {
 		t.Run(tc.description, func(t *testing.T) {
 			if isAffinityViolatingNUMAAllocations(tc.machineState, tc.topologyHint.NUMANodeAffinity) != tc.isViolationExpected {
-				t.Errorf("isAffinityViolatingNUMAAllocations with affinity %v expected to return %t, got %t", tc.topologyHint.NUMANodeAffinity.GetBits(), tc.isViolationExpected, !tc.isViolationExpected)
+				t.Errorf("isAffinityViolatingNUMAAllocations with affinity %v expected to return %t, got %t", tc.topologyHint.NodeIDs(), tc.isViolationExpected, !tc.isViolationExpected)
 			}
 		})
 	}
 }
+
+func TestStaticPolicyGetTopologyHintsWithMinMemoryPerNode(t *testing.T) {
+	tCtx := ktesting.Init(t)
+
+	requirements := &v1.ResourceRequirements{
+		Limits: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("1000Mi"),
+			v1.ResourceMemory: resource.MustParse("2Gi"),
+		},
+		Requests: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("1000Mi"),
+			v1.ResourceMemory: resource.MustParse("2Gi"),
+		},
+	}
+	machineState := state.NUMANodeMap{
+		0: &state.NUMANodeState{
+			MemoryMap: map[v1.ResourceName]*state.MemoryTable{
+				v1.ResourceMemory: {
+					Allocatable:    1536 * mb,
+					Free:           1536 * mb,
+					Reserved:       0,
+					SystemReserved: 512 * mb,
+					TotalMemSize:   2 * gb,
+				},
+			},
+			Cells: []int{0},
+		},
+		1: &state.NUMANodeState{
+			MemoryMap: map[v1.ResourceName]*state.MemoryTable{
+				v1.ResourceMemory: {
+					Allocatable:    1536 * mb,
+					Free:           1536 * mb,
+					Reserved:       0,
+					SystemReserved: 512 * mb,
+					TotalMemSize:   2 * gb,
+				},
+			},
+			Cells: []int{1},
+		},
+	}
+	systemReserved := systemReservedMemory{
+		0: map[v1.ResourceName]uint64{
+			v1.ResourceMemory: 512 * mb,
+		},
+		1: map[v1.ResourceName]uint64{
+			v1.ResourceMemory: 512 * mb,
+		},
+	}
+
+	testCases := []struct {
+		description           string
+		minMemoryPerNode      string
+		expectedTopologyHints map[string][]topologymanager.TopologyHint
+	}{
+		{
+			description:      "should provide a multi NUMA node hint when every spanned node can provide the minimum",
+			minMemoryPerNode: "512Mi",
+			expectedTopologyHints: map[string][]topologymanager.TopologyHint{
+				string(v1.ResourceMemory): {
+					{
+						NUMANodeAffinity: newNUMAAffinity(0, 1),
+						Preferred:        true,
+					},
+				},
+			},
+		},
+		{
+			description:      "should provide no hints when no mask can provide the minimum on every spanned node",
+			minMemoryPerNode: "2Gi",
+			expectedTopologyHints: map[string][]topologymanager.TopologyHint{
+				string(v1.ResourceMemory): {},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			pod := getPod("pod1", "container1", requirements)
+			pod.Annotations = map[string]string{
+				MinMemoryPerNodeAnnotation: testCase.minMemoryPerNode,
+			}
+
+			p, s, err := initTests(t, &testStaticPolicy{
+				machineState:   machineState,
+				systemReserved: systemReserved,
+			}, nil, nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			topologyHints := p.GetTopologyHints(tCtx, s, pod, &pod.Spec.Containers[0])
+			if !reflect.DeepEqual(topologyHints, testCase.expectedTopologyHints) {
+				t.Fatalf("The actual topology hints: '%+v' are different from the expected one: '%+v'", topologyHints, testCase.expectedTopologyHints)
+			}
+		})
+	}
+}
+
+func TestStaticPolicyGetTopologyHintsWithMemoryPressure(t *testing.T) {
+	tCtx := ktesting.Init(t)
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.EnhancedTopologyHints, true)
+
+	requirements := &v1.ResourceRequirements{
+		Limits: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("1000Mi"),
+			v1.ResourceMemory: resource.MustParse("1Gi"),
+		},
+		Requests: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("1000Mi"),
+			v1.ResourceMemory: resource.MustParse("1Gi"),
+		},
+	}
+	// node 0 is under memory pressure, node 1 has ample free memory; both can
+	// still fit the request on their own.
+	machineState := state.NUMANodeMap{
+		0: &state.NUMANodeState{
+			MemoryMap: map[v1.ResourceName]*state.MemoryTable{
+				v1.ResourceMemory: {
+					Allocatable:    1536 * mb,
+					Free:           1100 * mb,
+					Reserved:       436 * mb,
+					SystemReserved: 512 * mb,
+					TotalMemSize:   2 * gb,
+				},
+			},
+			Cells: []int{0},
+		},
+		1: &state.NUMANodeState{
+			MemoryMap: map[v1.ResourceName]*state.MemoryTable{
+				v1.ResourceMemory: {
+					Allocatable:    1536 * mb,
+					Free:           1536 * mb,
+					Reserved:       0,
+					SystemReserved: 512 * mb,
+					TotalMemSize:   2 * gb,
+				},
+			},
+			Cells: []int{1},
+		},
+	}
+	systemReserved := systemReservedMemory{
+		0: map[v1.ResourceName]uint64{
+			v1.ResourceMemory: 512 * mb,
+		},
+		1: map[v1.ResourceName]uint64{
+			v1.ResourceMemory: 512 * mb,
+		},
+	}
+
+	testCases := []struct {
+		description           string
+		freeFractionThreshold float64
+		expectPressurePenalty bool
+	}{
+		{
+			description:           "default threshold applies no pressure penalty",
+			freeFractionThreshold: defaultFreeFractionThreshold,
+			expectPressurePenalty: false,
+		},
+		{
+			description:           "node below the free-fraction threshold scores worse",
+			freeFractionThreshold: 0.9,
+			expectPressurePenalty: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.description, func(t *testing.T) {
+			pod := getPod("pod1", "container1", requirements)
+
+			p, s, err := initTests(t, &testStaticPolicy{
+				machineState:   machineState,
+				systemReserved: systemReserved,
+			}, nil, nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			p.(*staticPolicy).freeFractionThreshold = testCase.freeFractionThreshold
+
+			topologyHints := p.GetTopologyHints(tCtx, s, pod, &pod.Spec.Containers[0])
+
+			var scoreNode0, scoreNode1 *float64
+			for _, hint := range topologyHints[string(v1.ResourceMemory)] {
+				if hint.Score == nil {
+					t.Fatalf("Expected enhanced hint to carry a score, got %+v", hint)
+				}
+				switch {
+				case hint.NUMANodeAffinity.IsEqual(newNUMAAffinity(0)):
+					scoreNode0 = hint.Score
+				case hint.NUMANodeAffinity.IsEqual(newNUMAAffinity(1)):
+					scoreNode1 = hint.Score
+				}
+			}
+			if scoreNode0 == nil || scoreNode1 == nil {
+				t.Fatalf("Expected single NUMA node hints for both nodes, got %+v", topologyHints)
+			}
+
+			if testCase.expectPressurePenalty && *scoreNode0 <= *scoreNode1 {
+				t.Errorf("Expected pressured node 0 to score worse than node 1, got %v and %v", *scoreNode0, *scoreNode1)
+			}
+			if !testCase.expectPressurePenalty && *scoreNode0 != *scoreNode1 {
+				t.Errorf("Expected equal scores without pressure penalty, got %v and %v", *scoreNode0, *scoreNode1)
+			}
+		})
+	}
+}
+
+func TestStaticPolicyGetTopologyHintsWithSystemReserved(t *testing.T) {
+	tCtx := ktesting.Init(t)
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.EnhancedTopologyHints, true)
+
+	requirements := &v1.ResourceRequirements{
+		Limits: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("1000Mi"),
+			v1.ResourceMemory: resource.MustParse("1Gi"),
+		},
+		Requests: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("1000Mi"),
+			v1.ResourceMemory: resource.MustParse("1Gi"),
+		},
+	}
+	// Both nodes are entirely free, so the pressure term does not fire; node 0
+	// reserves half its memory for system components while node 1 reserves a
+	// quarter. The larger reservation must worsen node 0's score for the same
+	// request.
+	machineState := state.NUMANodeMap{
+		0: &state.NUMANodeState{
+			MemoryMap: map[v1.ResourceName]*state.MemoryTable{
+				v1.ResourceMemory: {
+					Allocatable:    1 * gb,
+					Free:           1 * gb,
+					Reserved:       0,
+					SystemReserved: 1 * gb,
+					TotalMemSize:   2 * gb,
+				},
+			},
+			Cells: []int{0},
+		},
+		1: &state.NUMANodeState{
+			MemoryMap: map[v1.ResourceName]*state.MemoryTable{
+				v1.ResourceMemory: {
+					Allocatable:    1536 * mb,
+					Free:           1536 * mb,
+					Reserved:       0,
+					SystemReserved: 512 * mb,
+					TotalMemSize:   2 * gb,
+				},
+			},
+			Cells: []int{1},
+		},
+	}
+	systemReserved := systemReservedMemory{
+		0: map[v1.ResourceName]uint64{
+			v1.ResourceMemory: 1 * gb,
+		},
+		1: map[v1.ResourceName]uint64{
+			v1.ResourceMemory: 512 * mb,
+		},
+	}
+
+	pod := getPod("pod1", "container1", requirements)
+
+	p, s, err := initTests(t, &testStaticPolicy{
+		machineState:   machineState,
+		systemReserved: systemReserved,
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	topologyHints := p.GetTopologyHints(tCtx, s, pod, &pod.Spec.Containers[0])
+
+	var scoreNode0, scoreNode1 *float64
+	for _, hint := range topologyHints[string(v1.ResourceMemory)] {
+		if hint.Score == nil {
+			t.Fatalf("Expected enhanced hint to carry a score, got %+v", hint)
+		}
+		switch {
+		case hint.NUMANodeAffinity.IsEqual(newNUMAAffinity(0)):
+			scoreNode0 = hint.Score
+		case hint.NUMANodeAffinity.IsEqual(newNUMAAffinity(1)):
+			scoreNode1 = hint.Score
+		}
+	}
+	if scoreNode0 == nil || scoreNode1 == nil {
+		t.Fatalf("Expected single NUMA node hints for both nodes, got %+v", topologyHints)
+	}
+	if *scoreNode0 <= *scoreNode1 {
+		t.Errorf("Expected the heavily reserved node 0 to score worse than node 1, got %v and %v", *scoreNode0, *scoreNode1)
+	}
+}
+
+func TestStaticPolicyGetTopologyHintsWithZeroAllocatable(t *testing.T) {
+	tCtx := ktesting.Init(t)
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.EnhancedTopologyHints, true)
+
+	requirements := &v1.ResourceRequirements{
+		Limits: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("1000Mi"),
+			v1.ResourceMemory: resource.MustParse("1Gi"),
+		},
+		Requests: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("1000Mi"),
+			v1.ResourceMemory: resource.MustParse("1Gi"),
+		},
+	}
+	// Node 1 reports no allocatable memory at all, as a misreporting cadvisor
+	// would; the request still fits on node 0. The capacity-ratio term must be
+	// skipped for node 1 rather than divide by its zero allocatable.
+	machineState := state.NUMANodeMap{
+		0: &state.NUMANodeState{
+			MemoryMap: map[v1.ResourceName]*state.MemoryTable{
+				v1.ResourceMemory: {
+					Allocatable:    1536 * mb,
+					Free:           1536 * mb,
+					Reserved:       0,
+					SystemReserved: 512 * mb,
+					TotalMemSize:   2 * gb,
+				},
+			},
+			Cells: []int{0},
+		},
+		1: &state.NUMANodeState{
+			MemoryMap: map[v1.ResourceName]*state.MemoryTable{
+				v1.ResourceMemory: {
+					Allocatable:    0,
+					Free:           0,
+					Reserved:       0,
+					SystemReserved: 0,
+					TotalMemSize:   0,
+				},
+			},
+			Cells: []int{1},
+		},
+	}
+	systemReserved := systemReservedMemory{
+		0: map[v1.ResourceName]uint64{
+			v1.ResourceMemory: 512 * mb,
+		},
+		1: map[v1.ResourceName]uint64{
+			v1.ResourceMemory: 0,
+		},
+	}
+
+	pod := getPod("pod1", "container1", requirements)
+
+	p, s, err := initTests(t, &testStaticPolicy{
+		machineState:   machineState,
+		systemReserved: systemReserved,
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Penalize any node below a full free fraction, so the pressure term would
+	// run for node 1 if its zero allocatable were not skipped.
+	p.(*staticPolicy).freeFractionThreshold = 1.0
+
+	topologyHints := p.GetTopologyHints(tCtx, s, pod, &pod.Spec.Containers[0])
+
+	memoryHints := topologyHints[string(v1.ResourceMemory)]
+	if len(memoryHints) == 0 {
+		t.Fatalf("Expected memory hints to be generated, got %+v", topologyHints)
+	}
+	for _, hint := range memoryHints {
+		if hint.Score == nil {
+			t.Fatalf("Expected enhanced hint to carry a score, got %+v", hint)
+		}
+		if math.IsNaN(*hint.Score) || math.IsInf(*hint.Score, 0) {
+			t.Errorf("Expected a finite score for hint %+v, got %v", hint, *hint.Score)
+		}
+	}
+}
+
+func TestStaticPolicyGetTopologyHintsWithHugePages(t *testing.T) {
+	tCtx := ktesting.Init(t)
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.EnhancedTopologyHints, true)
+
+	// The hugepage request only fits across both nodes, so the single hint
+	// must span them and carry the enhanced fields the same way a regular
+	// memory hint does.
+	requirements := &v1.ResourceRequirements{
+		Limits: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("1000Mi"),
+			v1.ResourceMemory: resource.MustParse("512Mi"),
+			hugepages1Gi:      resource.MustParse("2Gi"),
+		},
+		Requests: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("1000Mi"),
+			v1.ResourceMemory: resource.MustParse("512Mi"),
+			hugepages1Gi:      resource.MustParse("2Gi"),
+		},
+	}
+	machineState := state.NUMANodeMap{
+		0: &state.NUMANodeState{
+			MemoryMap: map[v1.ResourceName]*state.MemoryTable{
+				v1.ResourceMemory: {
+					Allocatable:    1536 * mb,
+					Free:           1536 * mb,
+					Reserved:       0,
+					SystemReserved: 512 * mb,
+					TotalMemSize:   2 * gb,
+				},
+				hugepages1Gi: {
+					Allocatable:    gb,
+					Free:           gb,
+					Reserved:       0,
+					SystemReserved: 0,
+					TotalMemSize:   gb,
+				},
+			},
+			Cells: []int{0},
+		},
+		1: &state.NUMANodeState{
+			MemoryMap: map[v1.ResourceName]*state.MemoryTable{
+				v1.ResourceMemory: {
+					Allocatable:    1536 * mb,
+					Free:           1536 * mb,
+					Reserved:       0,
+					SystemReserved: 512 * mb,
+					TotalMemSize:   2 * gb,
+				},
+				hugepages1Gi: {
+					Allocatable:    gb,
+					Free:           gb,
+					Reserved:       0,
+					SystemReserved: 0,
+					TotalMemSize:   gb,
+				},
+			},
+			Cells: []int{1},
+		},
+	}
+	systemReserved := systemReservedMemory{
+		0: map[v1.ResourceName]uint64{
+			v1.ResourceMemory: 512 * mb,
+		},
+		1: map[v1.ResourceName]uint64{
+			v1.ResourceMemory: 512 * mb,
+		},
+	}
+
+	pod := getPod("pod1", "container1", requirements)
+
+	p, s, err := initTests(t, &testStaticPolicy{
+		machineState:   machineState,
+		systemReserved: systemReserved,
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	topologyHints := p.GetTopologyHints(tCtx, s, pod, &pod.Spec.Containers[0])
+
+	hugepageHints := topologyHints[string(hugepages1Gi)]
+	if len(hugepageHints) != 1 {
+		t.Fatalf("Expected a single hugepage hint spanning both nodes, got %+v", hugepageHints)
+	}
+	hugepageHint := hugepageHints[0]
+	if !hugepageHint.NUMANodeAffinity.IsEqual(newNUMAAffinity(0, 1)) {
+		t.Errorf("Expected the hugepage hint to span both nodes, got %v", hugepageHint.NUMANodeAffinity)
+	}
+	if hugepageHint.HopCount == nil || *hugepageHint.HopCount != 1 {
+		t.Errorf("Expected the hugepage hint to carry a hop count of 1, got %+v", hugepageHint.HopCount)
+	}
+	expectedDistance := topologymanager.DefaultLocalDistance + memoryHopDistanceStep
+	if hugepageHint.Distance == nil || *hugepageHint.Distance != expectedDistance {
+		t.Errorf("Expected the hugepage hint to carry a distance of %d, got %+v", expectedDistance, hugepageHint.Distance)
+	}
+
+	memoryHints := topologyHints[string(v1.ResourceMemory)]
+	if len(memoryHints) != 1 {
+		t.Fatalf("Expected a single memory hint spanning both nodes, got %+v", memoryHints)
+	}
+	// The scores differ because regular memory carries a system reservation
+	// on these nodes and the hugepages do not; the topology fields must match.
+	if !hugepageHint.EqualIgnoringScore(memoryHints[0]) {
+		t.Errorf("Expected the hugepage hint to match the memory hint, got %+v and %+v", hugepageHint, memoryHints[0])
+	}
+}
+
+func TestStaticPolicyGetTopologyHintsWithInterconnectInfo(t *testing.T) {
+	tCtx := ktesting.Init(t)
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.EnhancedTopologyHints, true)
+
+	requirements := &v1.ResourceRequirements{
+		Limits: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("1000Mi"),
+			v1.ResourceMemory: resource.MustParse("1Gi"),
+		},
+		Requests: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("1000Mi"),
+			v1.ResourceMemory: resource.MustParse("1Gi"),
+		},
+	}
+	machineState := state.NUMANodeMap{
+		0: &state.NUMANodeState{
+			MemoryMap: map[v1.ResourceName]*state.MemoryTable{
+				v1.ResourceMemory: {
+					Allocatable:    1536 * mb,
+					Free:           1536 * mb,
+					Reserved:       0,
+					SystemReserved: 512 * mb,
+					TotalMemSize:   2 * gb,
+				},
+			},
+			Cells: []int{0},
+		},
+		1: &state.NUMANodeState{
+			MemoryMap: map[v1.ResourceName]*state.MemoryTable{
+				v1.ResourceMemory: {
+					Allocatable:    1536 * mb,
+					Free:           1536 * mb,
+					Reserved:       0,
+					SystemReserved: 512 * mb,
+					TotalMemSize:   2 * gb,
+				},
+			},
+			Cells: []int{1},
+		},
+	}
+	systemReserved := systemReservedMemory{
+		0: map[v1.ResourceName]uint64{
+			v1.ResourceMemory: 512 * mb,
+		},
+		1: map[v1.ResourceName]uint64{
+			v1.ResourceMemory: 512 * mb,
+		},
+	}
+
+	pod := getPod("pod1", "container1", requirements)
+
+	p, s, err := initTests(t, &testStaticPolicy{
+		machineState:   machineState,
+		systemReserved: systemReserved,
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// Only node 0 has measured fabric data; node 1 keeps the synthetic model.
+	p.(*staticPolicy).interconnectInfo = func(nodeID int) *NodeInterconnectInfo {
+		if nodeID == 0 {
+			return &NodeInterconnectInfo{Distance: 12, Bandwidth: 200}
+		}
+		return nil
+	}
+
+	topologyHints := p.GetTopologyHints(tCtx, s, pod, &pod.Spec.Containers[0])
+
+	var hintNode0, hintNode1 *topologymanager.TopologyHint
+	for i, hint := range topologyHints[string(v1.ResourceMemory)] {
+		switch {
+		case hint.NUMANodeAffinity.IsEqual(newNUMAAffinity(0)):
+			hintNode0 = &topologyHints[string(v1.ResourceMemory)][i]
+		case hint.NUMANodeAffinity.IsEqual(newNUMAAffinity(1)):
+			hintNode1 = &topologyHints[string(v1.ResourceMemory)][i]
+		}
+	}
+	if hintNode0 == nil || hintNode1 == nil {
+		t.Fatalf("Expected single NUMA node hints for both nodes, got %+v", topologyHints)
+	}
+
+	if hintNode0.GetDistance() != 12 || hintNode0.GetBandwidth() != 200 {
+		t.Errorf("Expected the measured distance 12 and bandwidth 200 for node 0, got %d and %v", hintNode0.GetDistance(), hintNode0.GetBandwidth())
+	}
+	if hintNode1.GetDistance() != topologymanager.DefaultLocalDistance || hintNode1.Bandwidth != nil {
+		t.Errorf("Expected the synthetic model for node 1, got distance %d and bandwidth %v", hintNode1.GetDistance(), hintNode1.Bandwidth)
+	}
+	if hintNode0.GetScore() == hintNode1.GetScore() {
+		t.Errorf("Expected the measured data to change the score, both are %v", hintNode0.GetScore())
+	}
+}
+
+func TestStaticPolicyRegenerateHintsScoreBest(t *testing.T) {
+	tCtx := ktesting.Init(t)
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.EnhancedTopologyHints, true)
+
+	requirements := &v1.ResourceRequirements{
+		Limits: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("1000Mi"),
+			v1.ResourceMemory: resource.MustParse("2Gi"),
+		},
+		Requests: v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("1000Mi"),
+			v1.ResourceMemory: resource.MustParse("2Gi"),
+		},
+	}
+	// The memory was placed across both nodes before the restart.
+	assignments := state.ContainerMemoryAssignments{
+		"pod1": map[string][]state.Block{
+			"container1": {
+				{
+					NUMAAffinity: []int{0, 1},
+					Type:         v1.ResourceMemory,
+					Size:         2 * gb,
+				},
+			},
+		},
+	}
+	machineState := state.NUMANodeMap{
+		0: &state.NUMANodeState{
+			MemoryMap: map[v1.ResourceName]*state.MemoryTable{
+				v1.ResourceMemory: {
+					Allocatable:    1536 * mb,
+					Free:           512 * mb,
+					Reserved:       gb,
+					SystemReserved: 512 * mb,
+					TotalMemSize:   2 * gb,
+				},
+			},
+			Cells:               []int{0, 1},
+			NumberOfAssignments: 1,
+		},
+		1: &state.NUMANodeState{
+			MemoryMap: map[v1.ResourceName]*state.MemoryTable{
+				v1.ResourceMemory: {
+					Allocatable:    1536 * mb,
+					Free:           512 * mb,
+					Reserved:       gb,
+					SystemReserved: 512 * mb,
+					TotalMemSize:   2 * gb,
+				},
+			},
+			Cells:               []int{0, 1},
+			NumberOfAssignments: 1,
+		},
+	}
+	systemReserved := systemReservedMemory{
+		0: map[v1.ResourceName]uint64{
+			v1.ResourceMemory: 512 * mb,
+		},
+		1: map[v1.ResourceName]uint64{
+			v1.ResourceMemory: 512 * mb,
+		},
+	}
+
+	pod := getPod("pod1", "container1", requirements)
+
+	p, s, err := initTests(t, &testStaticPolicy{
+		assignments:    assignments,
+		machineState:   machineState,
+		systemReserved: systemReserved,
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	topologyHints := p.GetTopologyHints(tCtx, s, pod, &pod.Spec.Containers[0])
+
+	memoryHints := topologyHints[string(v1.ResourceMemory)]
+	if len(memoryHints) != 1 {
+		t.Fatalf("Expected a single regenerated hint, got %+v", topologyHints)
+	}
+	hint := memoryHints[0]
+	if !hint.NUMANodeAffinity.IsEqual(newNUMAAffinity(0, 1)) || !hint.Preferred {
+		t.Fatalf("Expected a preferred hint for the already-placed nodes, got %+v", hint)
+	}
+	if hint.GetScore() != 0 || hint.GetHopCount() != 0 {
+		t.Errorf("Expected the regenerated hint to score best (0), got score %v and hop count %d", hint.GetScore(), hint.GetHopCount())
+	}
+	// A freshly calculated two-node mask would pay for the hop, so the
+	// regenerated hint must score strictly better.
+	syntheticScore := topologymanager.CalculateTopologyScore(1, topologymanager.DefaultLocalDistance+memoryHopDistanceStep, 0, 0, topologymanager.PolicyOptions{})
+	if hint.GetScore() >= syntheticScore {
+		t.Errorf("Expected the regenerated score %v to beat the synthetic score %v", hint.GetScore(), syntheticScore)
+	}
+}
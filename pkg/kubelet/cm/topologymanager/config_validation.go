@@ -0,0 +1,62 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"fmt"
+
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	pkgfeatures "k8s.io/kubernetes/pkg/features"
+)
+
+// ValidateTopologyManagerConfig checks a topology manager configuration
+// without constructing a manager, so kubelet config validation can reject a
+// bad config at startup instead of waiting for NewManager to fail at
+// runtime. It mirrors the checks NewManager performs: known policy and scope
+// names, feature-gate availability of the distributed policy, and
+// option-level validation.
+func ValidateTopologyManagerConfig(policyName, scopeName string, options map[string]string) error {
+	switch policyName {
+	case PolicyNone:
+		// The none policy short-circuits NewManager before the scope and
+		// options are even looked at, so nothing else needs checking.
+		return nil
+
+	case PolicyBestEffort, PolicyRestricted, PolicySingleNumaNode:
+
+	case PolicyDistributed:
+		if !utilfeature.DefaultFeatureGate.Enabled(pkgfeatures.EnhancedTopologyHints) {
+			return fmt.Errorf("policy %q requires the EnhancedTopologyHints feature gate", PolicyDistributed)
+		}
+
+	default:
+		return fmt.Errorf("unknown policy: %q", policyName)
+	}
+
+	switch scopeName {
+	case containerTopologyScope, podTopologyScope:
+
+	default:
+		return fmt.Errorf("unknown scope: %q", scopeName)
+	}
+
+	if _, err := NewPolicyOptions(options); err != nil {
+		return err
+	}
+
+	return nil
+}
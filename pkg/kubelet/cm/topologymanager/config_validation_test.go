@@ -0,0 +1,114 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"strings"
+	"testing"
+
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/component-base/featuregate"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	pkgfeatures "k8s.io/kubernetes/pkg/features"
+)
+
+func TestValidateTopologyManagerConfig(t *testing.T) {
+	tcases := []struct {
+		name              string
+		policyName        string
+		scopeName         string
+		options           map[string]string
+		featureGate       featuregate.Feature
+		featureGateEnable bool
+		expectedErr       string
+	}{
+		{
+			name:       "valid best-effort container scope",
+			policyName: PolicyBestEffort,
+			scopeName:  containerTopologyScope,
+		},
+		{
+			name:       "valid single-numa-node pod scope with options",
+			policyName: PolicySingleNumaNode,
+			scopeName:  podTopologyScope,
+			options:    map[string]string{PreferClosestNUMANodes: "true"},
+		},
+		{
+			name:       "none policy ignores scope and options",
+			policyName: PolicyNone,
+			scopeName:  "bogus",
+			options:    map[string]string{"bogus-option": "true"},
+		},
+		{
+			name:        "unknown policy",
+			policyName:  "bogus",
+			scopeName:   containerTopologyScope,
+			expectedErr: "unknown policy",
+		},
+		{
+			name:        "unknown scope",
+			policyName:  PolicyRestricted,
+			scopeName:   "bogus",
+			expectedErr: "unknown scope",
+		},
+		{
+			name:        "distributed policy without the feature gate",
+			policyName:  PolicyDistributed,
+			scopeName:   containerTopologyScope,
+			expectedErr: "requires the EnhancedTopologyHints feature gate",
+		},
+		{
+			name:              "distributed policy with the feature gate",
+			policyName:        PolicyDistributed,
+			scopeName:         containerTopologyScope,
+			featureGate:       pkgfeatures.EnhancedTopologyHints,
+			featureGateEnable: true,
+		},
+		{
+			name:        "unknown option",
+			policyName:  PolicyBestEffort,
+			scopeName:   containerTopologyScope,
+			options:     map[string]string{"bogus-option": "true"},
+			expectedErr: "unknown Topology Manager Policy option",
+		},
+		{
+			name:        "bad option value",
+			policyName:  PolicyBestEffort,
+			scopeName:   containerTopologyScope,
+			options:     map[string]string{PreferClosestNUMANodes: "not a boolean"},
+			expectedErr: "bad value for option",
+		},
+	}
+
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.featureGate != "" {
+				featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, tc.featureGate, tc.featureGateEnable)
+			}
+			err := ValidateTopologyManagerConfig(tc.policyName, tc.scopeName, tc.options)
+			if tc.expectedErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+				t.Errorf("Expected error containing %q, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
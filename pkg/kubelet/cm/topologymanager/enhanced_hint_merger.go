@@ -0,0 +1,394 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kubernetes/pkg/features"
+	"k8s.io/kubernetes/pkg/kubelet/metrics"
+)
+
+// Reason codes recorded for candidate hints the enhanced merger discarded.
+const (
+	// DiscardReasonFiltered indicates the candidate's merged affinity was
+	// empty, so it could never satisfy the allocation.
+	DiscardReasonFiltered = "Filtered"
+	// DiscardReasonNotPreferred indicates the candidate lost against a
+	// preferred hint because it was not itself preferred.
+	DiscardReasonNotPreferred = "NotPreferred"
+	// DiscardReasonHigherScore indicates the candidate had a higher
+	// (worse) topology score than the selected hint.
+	DiscardReasonHigherScore = "HigherScore"
+	// DiscardReasonWiderMask indicates the candidate's affinity mask
+	// compared worse than the selected hint's.
+	DiscardReasonWiderMask = "WiderMask"
+)
+
+// DiscardReason records why a candidate merged hint was not selected.
+type DiscardReason struct {
+	Hint   TopologyHint
+	Reason string
+}
+
+// EnhancedHintMerger merges provider hints the same way HintMerger does, but
+// additionally carries the enhanced topology fields through the merge so the
+// committed affinity retains hop count, distance, bandwidth and score
+// information.
+type EnhancedHintMerger struct {
+	HintMerger
+	// recordDiscards enables accumulation of per-candidate discard reasons
+	// for debugging tools. It is controlled by the merge-diagnostics policy
+	// option and off by default to avoid overhead.
+	recordDiscards bool
+	discardReasons []DiscardReason
+	// scoreOverridesPreference ranks candidates purely by score, ignoring
+	// the Preferred flag, per the score-overrides-preference policy option.
+	scoreOverridesPreference bool
+	// socketCrossingPenalty is the score cost charged when a merged affinity
+	// spans sockets, per the socket-crossing-penalty policy option.
+	socketCrossingPenalty float64
+	// opts carries the full policy options so merged scores are recomputed
+	// under the configured local distance baseline and rounding.
+	opts PolicyOptions
+}
+
+// NewEnhancedHintMerger returns an EnhancedHintMerger configured identically
+// to the basic merger for the given policy.
+func NewEnhancedHintMerger(numaInfo *NUMAInfo, hints [][]TopologyHint, policyName string, opts PolicyOptions) EnhancedHintMerger {
+	return EnhancedHintMerger{
+		HintMerger:               NewHintMerger(numaInfo, hints, policyName, opts),
+		recordDiscards:           opts.MergeDiagnostics,
+		scoreOverridesPreference: opts.ScoreOverridesPreference,
+		socketCrossingPenalty:    opts.SocketCrossingPenalty,
+		opts:                     opts,
+	}
+}
+
+// Merge behaves like HintMerger.Merge, with each merged permutation also
+// combining the enhanced fields of its input hints.
+func (m *EnhancedHintMerger) Merge() TopologyHint {
+	defaultAffinity := m.NUMAInfo.DefaultAffinityMask()
+
+	var bestHint *TopologyHint
+	iterateAllProviderTopologyHints(m.Hints, func(permutation []TopologyHint) {
+		mergedHint := mergePermutation(defaultAffinity, permutation)
+		mergeEnhancedFields(&mergedHint, permutation, m.opts)
+		m.applySocketCrossingPenalty(&mergedHint)
+
+		// Compare the current bestHint with the candidate mergedHint and
+		// update bestHint if appropriate.
+		var winner *TopologyHint
+		if m.scoreOverridesPreference {
+			winner = m.compareByScore(bestHint, &mergedHint)
+		} else {
+			winner = m.compare(bestHint, &mergedHint)
+		}
+		if m.recordDiscards {
+			if winner != &mergedHint {
+				m.recordDiscard(winner, &mergedHint)
+			} else if bestHint != nil {
+				m.recordDiscard(winner, bestHint)
+			}
+		}
+		bestHint = winner
+	})
+
+	if bestHint == nil {
+		bestHint = &TopologyHint{NUMANodeAffinity: defaultAffinity, Preferred: false}
+	}
+
+	return *bestHint
+}
+
+// RankedCandidates returns every distinct candidate affinity the merge
+// considers, ranked best first by the same comparator Merge uses, so the
+// first element always carries the affinity Merge would select. Candidates
+// sharing an affinity mask are collapsed to the comparator-best of them. The
+// ranking is deterministic: ties fall through the same cascade as Merge.
+func (m *EnhancedHintMerger) RankedCandidates() []TopologyHint {
+	defaultAffinity := m.NUMAInfo.DefaultAffinityMask()
+
+	var candidates []TopologyHint
+	iterateAllProviderTopologyHints(m.Hints, func(permutation []TopologyHint) {
+		mergedHint := mergePermutation(defaultAffinity, permutation)
+		if mergedHint.NUMANodeAffinity.Count() == 0 {
+			return
+		}
+		mergeEnhancedFields(&mergedHint, permutation, m.opts)
+		m.applySocketCrossingPenalty(&mergedHint)
+		candidates = append(candidates, mergedHint)
+	})
+
+	// Repeatedly select the comparator-best remaining candidate and drop
+	// every other candidate with the same mask. The distinct mask count is
+	// bounded by the machine's NUMA node combinations, so the quadratic
+	// selection stays cheap.
+	ranked := make([]TopologyHint, 0, len(candidates))
+	for len(candidates) > 0 {
+		var best *TopologyHint
+		for i := range candidates {
+			if m.scoreOverridesPreference {
+				best = m.compareByScore(best, &candidates[i])
+			} else {
+				best = m.compare(best, &candidates[i])
+			}
+		}
+		winner := *best
+		ranked = append(ranked, winner)
+		kept := candidates[:0]
+		for i := range candidates {
+			if !candidates[i].NUMANodeAffinity.IsEqual(winner.NUMANodeAffinity) {
+				kept = append(kept, candidates[i])
+			}
+		}
+		candidates = kept
+	}
+	return ranked
+}
+
+// DiscardReasons returns, for each candidate hint the merger rejected, the
+// reason it lost against the selected hint, in the order candidates were
+// considered. It is empty unless the merge-diagnostics policy option is
+// enabled.
+func (m *EnhancedHintMerger) DiscardReasons() []DiscardReason {
+	return m.discardReasons
+}
+
+// compareByScore ranks two candidate hints purely by their enhanced score,
+// lower being better. Unlike compare, the Preferred flag plays no role, so a
+// badly scoring preferred hint loses to a well scoring non-preferred one.
+// Score ties cascade through the raw enhanced fields — shorter distance, then
+// higher bandwidth — since score rounding can equalize scores computed from
+// different inputs, and fully tied candidates fall through to the
+// deterministic affinity tie-break.
+func (m *EnhancedHintMerger) compareByScore(current *TopologyHint, candidate *TopologyHint) *TopologyHint {
+	// Only consider candidates that result in a NUMANodeAffinity > 0 to
+	// replace the current bestHint.
+	if candidate.NUMANodeAffinity.Count() == 0 {
+		return current
+	}
+	if current == nil {
+		return candidate
+	}
+	if candidate.GetScore() != current.GetScore() {
+		if candidate.GetScore() < current.GetScore() {
+			return candidate
+		}
+		return current
+	}
+	if candidate.GetDistance() != current.GetDistance() {
+		if candidate.GetDistance() < current.GetDistance() {
+			return candidate
+		}
+		return current
+	}
+	if candidate.GetBandwidth() != current.GetBandwidth() {
+		if candidate.GetBandwidth() > current.GetBandwidth() {
+			return candidate
+		}
+		return current
+	}
+	return breakAffinityTie(current, candidate)
+}
+
+// breakAffinityTie deterministically picks between two fully tied candidate
+// hints so the winner never depends on the order permutations are visited:
+// the mask containing the lowest-numbered NUMA node wins, the narrower mask
+// breaks a shared lowest node, and the mask with more lower-numbered bits set
+// decides the remainder. Equal masks keep the current hint, so repeated
+// merges of the same hints stay stable.
+func breakAffinityTie(current *TopologyHint, candidate *TopologyHint) *TopologyHint {
+	currentAffinity := current.NUMANodeAffinity
+	candidateAffinity := candidate.NUMANodeAffinity
+	if candidateAffinity.IsEqual(currentAffinity) {
+		return current
+	}
+	currentLowest := currentAffinity.GetBits()[0]
+	candidateLowest := candidateAffinity.GetBits()[0]
+	if candidateLowest != currentLowest {
+		if candidateLowest < currentLowest {
+			return candidate
+		}
+		return current
+	}
+	if candidateAffinity.Count() != currentAffinity.Count() {
+		if candidateAffinity.Count() < currentAffinity.Count() {
+			return candidate
+		}
+		return current
+	}
+	if candidateAffinity.IsLessThan(currentAffinity) {
+		return candidate
+	}
+	return current
+}
+
+// applySocketCrossingPenalty charges the configured penalty on top of a
+// candidate's score when its affinity spans NUMA nodes on different sockets.
+// Basic hints carry no score, so they are left untouched.
+func (m *EnhancedHintMerger) applySocketCrossingPenalty(hint *TopologyHint) {
+	if m.socketCrossingPenalty <= 0 || !hint.hasEnhancedFields() {
+		return
+	}
+	if hint.NUMANodeAffinity == nil || !m.NUMAInfo.CrossesSocketBoundary(hint.NUMANodeAffinity) {
+		return
+	}
+	score := hint.GetScore() + m.socketCrossingPenalty
+	hint.Score = &score
+}
+
+// recordDiscard classifies why the losing hint was not selected over the
+// winning one and appends it to the accumulated discard reasons.
+func (m *EnhancedHintMerger) recordDiscard(winner, loser *TopologyHint) {
+	reason := DiscardReasonWiderMask
+	switch {
+	case loser.NUMANodeAffinity != nil && loser.NUMANodeAffinity.Count() == 0:
+		reason = DiscardReasonFiltered
+	case winner.Preferred && !loser.Preferred:
+		reason = DiscardReasonNotPreferred
+	case loser.GetScore() > winner.GetScore():
+		reason = DiscardReasonHigherScore
+	}
+	m.discardReasons = append(m.discardReasons, DiscardReason{Hint: *loser, Reason: reason})
+}
+
+// mergeEnhancedFields combines the enhanced fields carried by the hints in
+// the permutation into the merged hint, taking the most pessimistic value for
+// each field at least one input hint supplies: the largest hop count and
+// distance, and the smallest bandwidth. The merged score is then recomputed
+// from the combined fields under the given policy options. Hints without
+// enhanced fields contribute nothing, so a permutation of purely basic hints
+// stays basic.
+func mergeEnhancedFields(merged *TopologyHint, permutation []TopologyHint, opts PolicyOptions) {
+	enhanced := false
+	for _, hint := range permutation {
+		if !hint.hasEnhancedFields() {
+			continue
+		}
+		enhanced = true
+		if hint.HopCount != nil && (merged.HopCount == nil || *hint.HopCount > *merged.HopCount) {
+			hopCount := *hint.HopCount
+			merged.HopCount = &hopCount
+		}
+		if hint.Distance != nil && (merged.Distance == nil || *hint.Distance > *merged.Distance) {
+			distance := *hint.Distance
+			merged.Distance = &distance
+		}
+		if hint.Bandwidth != nil && (merged.Bandwidth == nil || *hint.Bandwidth < *merged.Bandwidth) {
+			bandwidth := *hint.Bandwidth
+			merged.Bandwidth = &bandwidth
+		}
+	}
+	if enhanced {
+		merged.UpdateScore(0, opts)
+	}
+}
+
+// fallbackOnNilAffinity guards against the enhanced merger committing a hint
+// without a NUMA node affinity when the basic merger would have produced a
+// valid one. The mergers are meant to agree on which affinities are feasible,
+// so a divergence indicates a bug in the enhanced merge path; rather than
+// failing admission on it, commit the basic result and log a warning.
+func fallbackOnNilAffinity(enhancedHint TopologyHint, basicMerge func() TopologyHint) TopologyHint {
+	if enhancedHint.NUMANodeAffinity != nil {
+		return enhancedHint
+	}
+	basicHint := basicMerge()
+	if basicHint.NUMANodeAffinity == nil {
+		return enhancedHint
+	}
+	klog.InfoS("Enhanced merge produced no NUMA node affinity, falling back to the basic merge result", "enhancedHint", enhancedHint, "basicHint", basicHint)
+	return basicHint
+}
+
+// mergeTopNCandidates ranks the merged permutations of the already-filtered
+// provider hints and returns up to n distinct candidates the given admission
+// predicate accepts, best first. The enhanced merger does the ranking
+// regardless of the feature gate — hints without enhanced fields simply rank
+// by preference and mask — since the result is informational and never drives
+// admission.
+func mergeTopNCandidates(numaInfo *NUMAInfo, filteredHints [][]TopologyHint, policyName string, opts PolicyOptions, n int, canAdmit func(*TopologyHint) bool) []MergeResult {
+	if n <= 0 {
+		return nil
+	}
+	merger := NewEnhancedHintMerger(numaInfo, filteredHints, policyName, opts)
+	var results []MergeResult
+	for _, hint := range merger.RankedCandidates() {
+		if !canAdmit(&hint) {
+			continue
+		}
+		results = append(results, MergeResult{Hint: hint, Admit: true})
+		if len(results) == n {
+			break
+		}
+	}
+	if len(results) > 0 && utilfeature.DefaultFeatureGate.Enabled(features.EnhancedTopologyHints) {
+		basicHint := NewHintMerger(numaInfo, filteredHints, policyName, opts).Merge()
+		results[0].EnhancedChangedDecision = enhancedChangedDecision(results[0].Hint, basicHint)
+	}
+	return results
+}
+
+// enhancedChangedDecision reports whether the enhanced merge reached a
+// different decision than the basic merge: a different NUMA node affinity or
+// a different preference, either of which can change what a policy admits.
+func enhancedChangedDecision(enhancedHint, basicHint TopologyHint) bool {
+	if enhancedHint.Preferred != basicHint.Preferred {
+		return true
+	}
+	if enhancedHint.NUMANodeAffinity == nil || basicHint.NUMANodeAffinity == nil {
+		return enhancedHint.NUMANodeAffinity != basicHint.NUMANodeAffinity
+	}
+	return !enhancedHint.NUMANodeAffinity.IsEqual(basicHint.NUMANodeAffinity)
+}
+
+// mergeFilteredHints runs the enhanced merger when the EnhancedTopologyHints
+// feature gate is enabled and the basic merger otherwise, so hints generated
+// with enhanced fields only influence merging while the gate is on.
+func mergeFilteredHints(numaInfo *NUMAInfo, hints [][]TopologyHint, policyName string, opts PolicyOptions) TopologyHint {
+	if utilfeature.DefaultFeatureGate.Enabled(features.EnhancedTopologyHints) {
+		merger := NewEnhancedHintMerger(numaInfo, hints, policyName, opts)
+		bestHint := merger.Merge()
+		for _, discard := range merger.DiscardReasons() {
+			klog.V(5).InfoS("Discarded candidate topology hint", "hint", discard.Hint, "reason", discard.Reason)
+		}
+		// Always compute the basic result alongside the enhanced one, so A/B
+		// evaluation can count the merges where enhanced scoring actually
+		// changed the pick rather than confirming the obvious winner.
+		basicHint := NewHintMerger(numaInfo, hints, policyName, opts).Merge()
+		if enhancedChangedDecision(bestHint, basicHint) {
+			metrics.TopologyManagerEnhancedDecisionChangesTotal.Inc()
+			klog.V(4).InfoS("Enhanced merge changed the decision", "enhancedHint", bestHint, "basicHint", basicHint)
+		}
+		if opts.EnhancedObserveOnly {
+			// Canary mode: report what the enhanced merger would have chosen,
+			// but commit the basic result so admission behavior matches a
+			// gate-off node.
+			metrics.TopologyManagerMergeModeTotal.WithLabelValues(metrics.MergeModeObserveOnly).Inc()
+			klog.V(4).InfoS("Enhanced merge in observe-only mode", "enhancedHint", bestHint, "enhancedScore", bestHint.GetScore(), "committedHint", basicHint)
+			return basicHint
+		}
+		metrics.TopologyManagerMergeModeTotal.WithLabelValues(metrics.MergeModeEnhanced).Inc()
+		return fallbackOnNilAffinity(bestHint, func() TopologyHint {
+			return basicHint
+		})
+	}
+	metrics.TopologyManagerMergeModeTotal.WithLabelValues(metrics.MergeModeBasicFallback).Inc()
+	return NewHintMerger(numaInfo, hints, policyName, opts).Merge()
+}
@@ -0,0 +1,485 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"reflect"
+	"testing"
+
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	pkgfeatures "k8s.io/kubernetes/pkg/features"
+)
+
+func TestEnhancedHintMergerDiscardReasons(t *testing.T) {
+	hopCountZero := 0
+	hopCountOne := 1
+
+	tcases := []struct {
+		name            string
+		hints           [][]TopologyHint
+		expectedReasons []string
+	}{
+		{
+			name: "candidate with empty merged affinity is Filtered",
+			hints: [][]TopologyHint{
+				{{NUMANodeAffinity: NewTestBitMask(0), Preferred: true}},
+				{{NUMANodeAffinity: NewTestBitMask(1), Preferred: true}},
+			},
+			expectedReasons: []string{DiscardReasonFiltered},
+		},
+		{
+			name: "non-preferred candidate loses as NotPreferred",
+			hints: [][]TopologyHint{
+				{
+					{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+					{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: false},
+				},
+			},
+			expectedReasons: []string{DiscardReasonNotPreferred},
+		},
+		{
+			name: "preferred candidate with worse score loses as HigherScore",
+			hints: [][]TopologyHint{
+				{
+					{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, HopCount: &hopCountZero},
+					{NUMANodeAffinity: NewTestBitMask(1), Preferred: true, HopCount: &hopCountOne},
+				},
+			},
+			expectedReasons: []string{DiscardReasonHigherScore},
+		},
+		{
+			name: "preferred candidate with wider mask loses as WiderMask",
+			hints: [][]TopologyHint{
+				{
+					{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+					{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: true},
+				},
+			},
+			expectedReasons: []string{DiscardReasonWiderMask},
+		},
+		{
+			name: "diagnostics disabled records nothing",
+			hints: [][]TopologyHint{
+				{
+					{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+					{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: false},
+				},
+			},
+			expectedReasons: nil,
+		},
+	}
+
+	for _, tc := range tcases {
+		opts := PolicyOptions{MergeDiagnostics: tc.expectedReasons != nil}
+		merger := NewEnhancedHintMerger(commonNUMAInfoTwoNodes(), tc.hints, PolicyBestEffort, opts)
+		merger.Merge()
+
+		reasons := merger.DiscardReasons()
+		if len(reasons) != len(tc.expectedReasons) {
+			t.Errorf("Test Case: %s: Expected %d discard reasons, got %v", tc.name, len(tc.expectedReasons), reasons)
+			continue
+		}
+		for i, expected := range tc.expectedReasons {
+			if reasons[i].Reason != expected {
+				t.Errorf("Test Case: %s: Expected reason %q at index %d, got %q for hint %v", tc.name, expected, i, reasons[i].Reason, reasons[i].Hint)
+			}
+		}
+	}
+}
+
+func TestEnhancedHintMergerScoreOverridesPreference(t *testing.T) {
+	hopCountZero := 0
+	hopCountFour := 4
+
+	// A preferred hint with a terrible score against a non-preferred hint
+	// with a perfect one: the classic ordering keeps the preferred hint,
+	// score-overrides-preference flips the outcome.
+	hints := [][]TopologyHint{
+		{
+			{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, HopCount: &hopCountFour},
+			{NUMANodeAffinity: NewTestBitMask(1), Preferred: false, HopCount: &hopCountZero},
+		},
+	}
+
+	classic := NewEnhancedHintMerger(commonNUMAInfoTwoNodes(), hints, PolicyBestEffort, PolicyOptions{})
+	classicBest := classic.Merge()
+	if !classicBest.NUMANodeAffinity.IsEqual(NewTestBitMask(0)) || !classicBest.Preferred {
+		t.Errorf("Expected the preferred hint to win with classic ordering, got %v", classicBest)
+	}
+
+	byScore := NewEnhancedHintMerger(commonNUMAInfoTwoNodes(), hints, PolicyBestEffort, PolicyOptions{ScoreOverridesPreference: true})
+	byScoreBest := byScore.Merge()
+	if !byScoreBest.NUMANodeAffinity.IsEqual(NewTestBitMask(1)) {
+		t.Errorf("Expected the lower-scoring hint to win with score-overrides-preference, got %v", byScoreBest)
+	}
+	if byScoreBest.GetScore() != 0 {
+		t.Errorf("Expected the winning hint to carry the lower score, got %v", byScoreBest.GetScore())
+	}
+}
+
+func TestEnhancedHintMergerSocketCrossingPenalty(t *testing.T) {
+	hopCountOne := 1
+
+	// Nodes 0 and 1 sit on different sockets while nodes 2 and 3 share one,
+	// so of two equally wide, equally distant masks only {0,1} crosses a
+	// socket boundary.
+	numaInfo := &NUMAInfo{
+		Nodes: []int{0, 1, 2, 3},
+		NUMADistances: NUMADistances{
+			0: nil,
+			1: nil,
+			2: nil,
+			3: nil,
+		},
+		SocketIDs: map[int]int{0: 0, 1: 1, 2: 1, 3: 1},
+	}
+	hints := [][]TopologyHint{
+		{
+			{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: true, HopCount: &hopCountOne},
+			{NUMANodeAffinity: NewTestBitMask(2, 3), Preferred: true, HopCount: &hopCountOne},
+		},
+	}
+
+	// Rank by score so the penalty decides the outcome. Without it the masks
+	// score identically and the tie-break keeps the lower-numbered one.
+	classic := NewEnhancedHintMerger(numaInfo, hints, PolicyBestEffort, PolicyOptions{ScoreOverridesPreference: true})
+	classicBest := classic.Merge()
+	if !classicBest.NUMANodeAffinity.IsEqual(NewTestBitMask(0, 1)) {
+		t.Errorf("Expected the lower-numbered mask to win without a penalty, got %v", classicBest)
+	}
+
+	// With the penalty the cross-socket mask scores worse and the same-socket
+	// mask wins.
+	opts := PolicyOptions{ScoreOverridesPreference: true, SocketCrossingPenalty: 25.0}
+	penalized := NewEnhancedHintMerger(numaInfo, hints, PolicyBestEffort, opts)
+	penalizedBest := penalized.Merge()
+	if !penalizedBest.NUMANodeAffinity.IsEqual(NewTestBitMask(2, 3)) {
+		t.Errorf("Expected the same-socket mask to win with a penalty, got %v", penalizedBest)
+	}
+	if penalizedBest.GetScore() != scoreHopPenalty {
+		t.Errorf("Expected the winning same-socket mask to keep its unpenalized score %v, got %v", scoreHopPenalty, penalizedBest.GetScore())
+	}
+}
+
+func TestEnhancedHintMergerDeterministicTieBreak(t *testing.T) {
+	hopCountOne := 1
+	hopCountTwo := 2
+	distanceTen := 10
+	distanceTwenty := 20
+	bandwidthHundred := 100.0
+
+	numaInfo := &NUMAInfo{
+		Nodes: []int{0, 1, 2, 3},
+		NUMADistances: NUMADistances{
+			0: nil,
+			1: nil,
+			2: nil,
+			3: nil,
+		},
+	}
+	opts := PolicyOptions{ScoreOverridesPreference: true}
+
+	// Two fully tied masks — identical score, distance and bandwidth — must
+	// resolve to the one containing the lowest-numbered NUMA node, regardless
+	// of the order the permutations are visited in. Note that {0,3} is the
+	// numerically larger mask, so a raw mask-value comparison would pick the
+	// other one.
+	fullyTied := []TopologyHint{
+		{NUMANodeAffinity: NewTestBitMask(1, 2), Preferred: true, HopCount: &hopCountOne, Distance: &distanceTwenty, Bandwidth: &bandwidthHundred},
+		{NUMANodeAffinity: NewTestBitMask(0, 3), Preferred: true, HopCount: &hopCountOne, Distance: &distanceTwenty, Bandwidth: &bandwidthHundred},
+	}
+	for _, hints := range [][]TopologyHint{fullyTied, {fullyTied[1], fullyTied[0]}} {
+		merger := NewEnhancedHintMerger(numaInfo, [][]TopologyHint{hints}, PolicyBestEffort, opts)
+		best := merger.Merge()
+		if !best.NUMANodeAffinity.IsEqual(NewTestBitMask(0, 3)) {
+			t.Errorf("Expected the mask with the lowest NUMA node to win the full tie, got %v", best)
+		}
+	}
+
+	// A shared lowest node falls through to the narrower mask.
+	sharedLowest := []TopologyHint{
+		{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: true, HopCount: &hopCountOne, Distance: &distanceTwenty, Bandwidth: &bandwidthHundred},
+		{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, HopCount: &hopCountOne, Distance: &distanceTwenty, Bandwidth: &bandwidthHundred},
+	}
+	for _, hints := range [][]TopologyHint{sharedLowest, {sharedLowest[1], sharedLowest[0]}} {
+		merger := NewEnhancedHintMerger(numaInfo, [][]TopologyHint{hints}, PolicyBestEffort, opts)
+		best := merger.Merge()
+		if !best.NUMANodeAffinity.IsEqual(NewTestBitMask(0)) {
+			t.Errorf("Expected the narrower mask to win a shared lowest node, got %v", best)
+		}
+	}
+
+	// Distance still precedes the affinity tie-break: one extra hop on node 1
+	// trades off exactly against ten extra units of distance on node 0, so the
+	// scores tie but the shorter distance wins despite the higher node index.
+	tiedScores := [][]TopologyHint{
+		{
+			{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, HopCount: &hopCountOne, Distance: &distanceTwenty},
+			{NUMANodeAffinity: NewTestBitMask(1), Preferred: true, HopCount: &hopCountTwo, Distance: &distanceTen},
+		},
+	}
+	merger := NewEnhancedHintMerger(numaInfo, tiedScores, PolicyBestEffort, opts)
+	best := merger.Merge()
+	if !best.NUMANodeAffinity.IsEqual(NewTestBitMask(1)) {
+		t.Errorf("Expected the shorter distance to break the score tie, got %v", best)
+	}
+}
+
+// updateGoldens regenerates the enhanced merger golden file from the current
+// merger output instead of comparing against it:
+//
+//	go test ./pkg/kubelet/cm/topologymanager/ -run TestEnhancedHintMergerGoldens -update-goldens
+//
+// Inspect the resulting diff before committing it: the goldens pin down the
+// merger's contract, so a changed golden is a changed contract.
+var updateGoldens = flag.Bool("update-goldens", false, "regenerate golden files instead of comparing against them")
+
+const enhancedMergerGoldenFile = "testdata/enhanced_hint_merger_goldens.json"
+
+func enhancedMergerGoldenCases() []struct {
+	name  string
+	hints [][]TopologyHint
+} {
+	hopCountOne := 1
+	hopCountTwo := 2
+	distanceTwenty := 20
+	bandwidthFifty := 50.0
+	bandwidthHundred := 100.0
+
+	return []struct {
+		name  string
+		hints [][]TopologyHint
+	}{
+		{
+			name: "nil-affinity hint defers to the concrete one",
+			hints: [][]TopologyHint{
+				{{NUMANodeAffinity: nil, Preferred: true}},
+				{{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, HopCount: &hopCountOne}},
+			},
+		},
+		{
+			name: "duplicate masks collapse to one result",
+			hints: [][]TopologyHint{
+				{
+					{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, HopCount: &hopCountOne},
+					{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, HopCount: &hopCountOne},
+				},
+			},
+		},
+		{
+			name: "mixed enhanced and basic hints take the pessimistic fields",
+			hints: [][]TopologyHint{
+				{{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: true, HopCount: &hopCountTwo, Distance: &distanceTwenty, Bandwidth: &bandwidthFifty}},
+				{{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: true, HopCount: &hopCountOne, Bandwidth: &bandwidthHundred}},
+			},
+		},
+		{
+			name: "multi-resource intersection narrows the affinity",
+			hints: [][]TopologyHint{
+				{
+					{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: true, HopCount: &hopCountOne},
+				},
+				{
+					{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+					{NUMANodeAffinity: NewTestBitMask(1), Preferred: true, HopCount: &hopCountTwo},
+				},
+			},
+		},
+		{
+			name: "no feasible intersection falls back to the default affinity",
+			hints: [][]TopologyHint{
+				{{NUMANodeAffinity: NewTestBitMask(0), Preferred: true}},
+				{{NUMANodeAffinity: NewTestBitMask(1), Preferred: true}},
+			},
+		},
+	}
+}
+
+func TestEnhancedHintMergerGoldens(t *testing.T) {
+	actual := map[string]TopologyHintSnapshot{}
+	for _, tc := range enhancedMergerGoldenCases() {
+		merger := NewEnhancedHintMerger(commonNUMAInfoTwoNodes(), tc.hints, PolicyBestEffort, PolicyOptions{})
+		actual[tc.name] = snapshotTopologyHint(merger.Merge())
+	}
+
+	if *updateGoldens {
+		data, err := json.MarshalIndent(actual, "", "  ")
+		if err != nil {
+			t.Fatalf("Could not serialize goldens: %v", err)
+		}
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatalf("Could not create the testdata directory: %v", err)
+		}
+		if err := os.WriteFile(enhancedMergerGoldenFile, append(data, '\n'), 0644); err != nil {
+			t.Fatalf("Could not write the golden file: %v", err)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(enhancedMergerGoldenFile)
+	if err != nil {
+		t.Fatalf("Could not read the golden file (regenerate with -update-goldens): %v", err)
+	}
+	expected := map[string]TopologyHintSnapshot{}
+	if err := json.Unmarshal(data, &expected); err != nil {
+		t.Fatalf("Could not parse the golden file: %v", err)
+	}
+
+	for name, golden := range expected {
+		if _, ok := actual[name]; !ok {
+			t.Errorf("Golden case %q no longer exists; regenerate with -update-goldens", name)
+		}
+		_ = golden
+	}
+	for name, merged := range actual {
+		golden, ok := expected[name]
+		if !ok {
+			t.Errorf("Test Case: %s: no golden recorded; regenerate with -update-goldens", name)
+			continue
+		}
+		if !reflect.DeepEqual(merged, golden) {
+			t.Errorf("Test Case: %s: merged hint %+v does not match golden %+v", name, merged, golden)
+		}
+	}
+}
+
+func TestMergeFilteredHintsNilAffinityFallback(t *testing.T) {
+	hopCount := 1
+	validHints := [][]TopologyHint{
+		{{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, HopCount: &hopCount}},
+	}
+
+	// Construct the divergence directly: an enhanced result that lost its
+	// affinity paired with hints the basic merger resolves fine. The fallback
+	// must commit the basic result so admission still gets a valid affinity.
+	basicCalls := 0
+	hint := fallbackOnNilAffinity(TopologyHint{NUMANodeAffinity: nil, Preferred: false}, func() TopologyHint {
+		basicCalls++
+		return NewHintMerger(commonNUMAInfoTwoNodes(), validHints, PolicyBestEffort, PolicyOptions{}).Merge()
+	})
+	if basicCalls != 1 {
+		t.Errorf("Expected the basic merger to run exactly once, got %d runs", basicCalls)
+	}
+	if hint.NUMANodeAffinity == nil || !hint.NUMANodeAffinity.IsEqual(NewTestBitMask(0)) {
+		t.Errorf("Expected fallback to commit the basic affinity %v, got %v", NewTestBitMask(0), hint.NUMANodeAffinity)
+	}
+	if !hint.Preferred {
+		t.Errorf("Expected fallback hint to remain preferred, got %v", hint)
+	}
+
+	// A valid enhanced result is committed as-is, without running the basic
+	// merger at all.
+	enhancedHint := TopologyHint{NUMANodeAffinity: NewTestBitMask(1), Preferred: true, HopCount: &hopCount}
+	hint = fallbackOnNilAffinity(enhancedHint, func() TopologyHint {
+		t.Fatalf("Expected the basic merger not to run for a valid enhanced hint")
+		return TopologyHint{}
+	})
+	if !reflect.DeepEqual(hint, enhancedHint) {
+		t.Errorf("Expected the enhanced hint to pass through unchanged, got %v", hint)
+	}
+
+	// When both mergers come up empty there is nothing to fall back to and
+	// the enhanced result stands.
+	hint = fallbackOnNilAffinity(TopologyHint{NUMANodeAffinity: nil, Preferred: false}, func() TopologyHint {
+		return TopologyHint{NUMANodeAffinity: nil, Preferred: false}
+	})
+	if hint.NUMANodeAffinity != nil {
+		t.Errorf("Expected a nil affinity when both mergers produce none, got %v", hint.NUMANodeAffinity)
+	}
+}
+
+func TestMergeTopNEnhancedChangedDecision(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.EnhancedTopologyHints, true)
+
+	hop0 := 0
+	hop1 := 1
+	distLocal := 10
+	distFar := 20
+
+	policy := NewBestEffortPolicy(commonNUMAInfoTwoNodes(), PolicyOptions{ScoreOverridesPreference: true}).(TopNMergePolicy)
+
+	// The preferred {0,1} hint wins the basic merge, but the non-preferred
+	// {0} hint scores better, so score-overrides-preference flips the pick.
+	changed := policy.MergeTopN([]map[string][]TopologyHint{
+		{
+			"resource": {
+				{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: true, HopCount: &hop1, Distance: &distFar},
+				{NUMANodeAffinity: NewTestBitMask(0), Preferred: false, HopCount: &hop0, Distance: &distLocal},
+			},
+		},
+	}, 2)
+	if len(changed) == 0 || !changed[0].Hint.NUMANodeAffinity.IsEqual(NewTestBitMask(0)) {
+		t.Fatalf("Expected the score-ranked {0} candidate first, got %+v", changed)
+	}
+	if !changed[0].EnhancedChangedDecision {
+		t.Errorf("Expected the top result to be flagged as an enhanced decision change, got %+v", changed[0])
+	}
+
+	// A single obvious winner merges identically with and without enhanced
+	// scoring, so the flag stays unset.
+	unchanged := policy.MergeTopN([]map[string][]TopologyHint{
+		{
+			"resource": {
+				{NUMANodeAffinity: NewTestBitMask(1), Preferred: true, HopCount: &hop0, Distance: &distLocal},
+			},
+		},
+	}, 2)
+	if len(unchanged) == 0 || unchanged[0].EnhancedChangedDecision {
+		t.Errorf("Expected no enhanced decision change for a single candidate, got %+v", unchanged)
+	}
+}
+
+func TestMergeEnhancedHintsWithGateOff(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.EnhancedTopologyHints, false)
+
+	// Hints generated while the gate was on still carry enhanced fields after
+	// an operator flips it off. Merging them must deterministically ignore
+	// those fields and commit a hint without any.
+	hopNear := 0
+	hopFar := 2
+	bandwidth := 100.0
+	hints := [][]TopologyHint{
+		{
+			{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, HopCount: &hopFar},
+			{NUMANodeAffinity: NewTestBitMask(1), Preferred: true, HopCount: &hopNear, Bandwidth: &bandwidth},
+		},
+		{
+			{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, HopCount: &hopNear},
+			{NUMANodeAffinity: NewTestBitMask(1), Preferred: true, HopCount: &hopFar},
+		},
+	}
+
+	merged := mergeFilteredHints(commonNUMAInfoTwoNodes(), hints, PolicyBestEffort, PolicyOptions{})
+	if merged.hasEnhancedFields() || merged.HasScore() {
+		t.Errorf("Expected the committed hint to carry no enhanced fields with the gate off, got %v", merged)
+	}
+	expected := TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true}
+	if !reflect.DeepEqual(merged, expected) {
+		t.Errorf("Expected the basic merge result %v, got %v", expected, merged)
+	}
+
+	// The result is stable across repeated merges of the same hints.
+	for i := 0; i < 3; i++ {
+		if again := mergeFilteredHints(commonNUMAInfoTwoNodes(), hints, PolicyBestEffort, PolicyOptions{}); !reflect.DeepEqual(again, merged) {
+			t.Fatalf("Expected a deterministic merge with the gate off, got %v then %v", merged, again)
+		}
+	}
+}
@@ -18,6 +18,7 @@ package topologymanager
 
 import (
 	"k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/kubelet/cm/admission"
 	"k8s.io/kubernetes/pkg/kubelet/lifecycle"
@@ -64,6 +65,10 @@ func (m *fakeManager) GetPolicy() Policy {
 	return m.policy
 }
 
+func (m *fakeManager) GetScopeName() string {
+	return noneTopologyScope
+}
+
 func (m *fakeManager) AddHintProvider(h HintProvider) {
 	klog.InfoS("AddHintProvider", "hintProvider", h)
 }
@@ -77,6 +82,49 @@ func (m *fakeManager) RemoveContainer(containerID string) error {
 	return nil
 }
 
+func (m *fakeManager) AlignedCapacity(resource string) map[int]int64 {
+	klog.InfoS("AlignedCapacity", "resource", resource)
+	return nil
+}
+
+func (m *fakeManager) PinnedNodeCounts() map[int]int {
+	klog.InfoS("PinnedNodeCounts")
+	return nil
+}
+
+func (m *fakeManager) Snapshot() ManagerSnapshot {
+	klog.InfoS("Snapshot")
+	snapshot := ManagerSnapshot{
+		PolicyName: PolicyNone,
+		ScopeName:  noneTopologyScope,
+	}
+	if m.policy != nil {
+		snapshot.PolicyName = m.policy.Name()
+	}
+	return snapshot
+}
+
+func (m *fakeManager) NotifyTopologyChanged() {
+	klog.InfoS("NotifyTopologyChanged")
+}
+
+func (m *fakeManager) SetEventRecorder(recorder record.EventRecorder) {
+	klog.InfoS("SetEventRecorder")
+}
+
+func (m *fakeManager) SetPodStatusWriter(writer PodStatusWriter) {
+	klog.InfoS("SetPodStatusWriter")
+}
+
+func (m *fakeManager) CheckTopologyDrift(stateDir string) {
+	klog.InfoS("CheckTopologyDrift", "stateDir", stateDir)
+}
+
+func (m *fakeManager) PolicyOptions() PolicyOptions {
+	klog.InfoS("PolicyOptions")
+	return PolicyOptions{}
+}
+
 func (m *fakeManager) Admit(attrs *lifecycle.PodAdmitAttributes) lifecycle.PodAdmitResult {
 	klog.InfoS("Topology Admit Handler")
 	return admission.GetPodAdmitResult(nil)
@@ -0,0 +1,110 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"fmt"
+	"math"
+
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	pkgfeatures "k8s.io/kubernetes/pkg/features"
+	"k8s.io/kubernetes/pkg/kubelet/cm/topologymanager/bitmask"
+)
+
+// ValidateTopologyHint checks the hint's enhanced fields for out-of-range
+// values. Basic hints, and hints whose enhanced fields are all unset, always
+// validate.
+func ValidateTopologyHint(hint TopologyHint) error {
+	if hint.HopCount != nil && *hint.HopCount < 0 {
+		return fmt.Errorf("hop count must not be negative, got %d", *hint.HopCount)
+	}
+	if hint.Distance != nil && (*hint.Distance < 0 || *hint.Distance > UnreachableNUMADistance) {
+		return fmt.Errorf("distance must be between 0 and %d, got %d", UnreachableNUMADistance, *hint.Distance)
+	}
+	if hint.Bandwidth != nil && (*hint.Bandwidth < 0 || math.IsNaN(*hint.Bandwidth) || math.IsInf(*hint.Bandwidth, 0)) {
+		return fmt.Errorf("bandwidth must be a non-negative finite number, got %v", *hint.Bandwidth)
+	}
+	// +Inf is a legitimate score: an unreachable distance yields it.
+	if hint.Score != nil && (*hint.Score < 0 || math.IsNaN(*hint.Score)) {
+		return fmt.Errorf("score must not be negative or NaN, got %v", *hint.Score)
+	}
+	return nil
+}
+
+// HintBuilder assembles a TopologyHint through a fluent interface, applying
+// the EnhancedTopologyHints feature gate and the range checks of
+// ValidateTopologyHint in one place, so hint providers cannot forget either
+// when constructing hints by hand.
+type HintBuilder struct {
+	hint TopologyHint
+}
+
+// NewHintBuilder returns a builder for a hint with the given affinity. A nil
+// affinity builds a "don't care" hint.
+func NewHintBuilder(affinity bitmask.BitMask) *HintBuilder {
+	return &HintBuilder{hint: TopologyHint{NUMANodeAffinity: affinity}}
+}
+
+// Preferred marks the hint as preferred.
+func (b *HintBuilder) Preferred(preferred bool) *HintBuilder {
+	b.hint.Preferred = preferred
+	return b
+}
+
+// Hop sets the hint's interconnect hop count.
+func (b *HintBuilder) Hop(hopCount int) *HintBuilder {
+	b.hint.HopCount = &hopCount
+	return b
+}
+
+// Distance sets the hint's NUMA distance.
+func (b *HintBuilder) Distance(distance int) *HintBuilder {
+	b.hint.Distance = &distance
+	return b
+}
+
+// Bandwidth sets the hint's interconnect bandwidth in GB/s.
+func (b *HintBuilder) Bandwidth(bandwidth float64) *HintBuilder {
+	b.hint.Bandwidth = &bandwidth
+	return b
+}
+
+// Score sets the hint's placement score directly, for providers that compute
+// it themselves instead of deriving it with UpdateScore.
+func (b *HintBuilder) Score(score float64) *HintBuilder {
+	b.hint.Score = &score
+	return b
+}
+
+// Build returns the assembled hint. When the EnhancedTopologyHints feature
+// gate is disabled, the enhanced fields are dropped, so gated-off nodes only
+// ever see basic hints regardless of what the call site set. Out-of-range
+// enhanced values fail the build.
+func (b *HintBuilder) Build() (TopologyHint, error) {
+	hint := b.hint
+	if !utilfeature.DefaultFeatureGate.Enabled(pkgfeatures.EnhancedTopologyHints) {
+		hint.HopCount = nil
+		hint.Distance = nil
+		hint.Bandwidth = nil
+		hint.Score = nil
+		return hint, nil
+	}
+	if err := ValidateTopologyHint(hint); err != nil {
+		return TopologyHint{}, err
+	}
+	return hint, nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"testing"
+
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	pkgfeatures "k8s.io/kubernetes/pkg/features"
+)
+
+func TestHintBuilder(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.EnhancedTopologyHints, true)
+
+	hint, err := NewHintBuilder(NewTestBitMask(0, 1)).
+		Preferred(true).
+		Hop(1).
+		Distance(30).
+		Bandwidth(80).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error building a valid hint: %v", err)
+	}
+	if !hint.NUMANodeAffinity.IsEqual(NewTestBitMask(0, 1)) || !hint.Preferred {
+		t.Errorf("Expected a preferred hint on NUMA nodes {0,1}, got %+v", hint)
+	}
+	if hint.GetHopCount() != 1 || hint.GetDistance() != 30 || hint.GetBandwidth() != 80 {
+		t.Errorf("Expected the built hint to carry the enhanced fields, got %+v", hint)
+	}
+
+	if _, err := NewHintBuilder(NewTestBitMask(0)).Distance(UnreachableNUMADistance + 1).Build(); err == nil {
+		t.Errorf("Expected an out-of-range distance to fail the build")
+	}
+	if _, err := NewHintBuilder(NewTestBitMask(0)).Bandwidth(-1).Build(); err == nil {
+		t.Errorf("Expected a negative bandwidth to fail the build")
+	}
+}
+
+func TestHintBuilderFeatureGateDisabled(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.EnhancedTopologyHints, false)
+
+	hint, err := NewHintBuilder(NewTestBitMask(0)).
+		Preferred(true).
+		Hop(0).
+		Distance(10).
+		Bandwidth(80).
+		Score(0).
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error building a hint with the gate disabled: %v", err)
+	}
+	if hint.hasEnhancedFields() {
+		t.Errorf("Expected the builder to drop the enhanced fields with the gate disabled, got %+v", hint)
+	}
+	if !hint.NUMANodeAffinity.IsEqual(NewTestBitMask(0)) || !hint.Preferred {
+		t.Errorf("Expected the basic fields to survive the gate, got %+v", hint)
+	}
+}
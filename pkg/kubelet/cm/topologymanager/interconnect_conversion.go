@@ -0,0 +1,118 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	draapi "k8s.io/dynamic-resource-allocation/api"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/kubelet/cm/topologymanager/bitmask"
+)
+
+// clampInterconnectValue converts an int32 interconnect metric from the
+// resource API into an int, clamping to [0, UnreachableNUMADistance]. The
+// API validates that range today, but values from drivers that bypass
+// validation (or from a future widening of the API) must not silently
+// truncate or go negative, so out-of-range values are logged and clamped.
+func clampInterconnectValue(value int32, field string) int {
+	if value < 0 {
+		klog.InfoS("Interconnect metric below valid range, clamping", "field", field, "value", value)
+		return 0
+	}
+	if value > UnreachableNUMADistance {
+		klog.InfoS("Interconnect metric above valid range, clamping", "field", field, "value", value)
+		return UnreachableNUMADistance
+	}
+	return int(value)
+}
+
+// connectivityForResource resolves the connectivity entry to use for the
+// given resource. A resource-specific override from PerResource replaces the
+// fields it sets and inherits the rest from the pair-wide entry; without an
+// override the pair-wide entry applies as-is.
+func connectivityForResource(entry *draapi.NodeConnectivity, resource string) draapi.NodeConnectivity {
+	override, ok := entry.PerResource[resource]
+	if !ok {
+		return *entry
+	}
+	resolved := *entry
+	if override.Bandwidth != nil {
+		resolved.Bandwidth = override.Bandwidth
+	}
+	if override.Latency != nil {
+		resolved.Latency = override.Latency
+	}
+	if override.HopCount != nil {
+		resolved.HopCount = override.HopCount
+	}
+	return resolved
+}
+
+// hopCountForAffinity returns the hop count for a hint with the given
+// affinity. The connectivity matrix's per-target hop counts are more precise
+// than the scalar summary, so when the matrix covers nodes in the affinity
+// the worst per-target hop count wins; otherwise the scalar HopCount is used.
+func hopCountForAffinity(affinity bitmask.BitMask, info *draapi.InterconnectInfo, resource string) *int {
+	var matrixHopCount *int
+	for i := range info.ConnectivityMatrix {
+		entry := connectivityForResource(&info.ConnectivityMatrix[i], resource)
+		if entry.HopCount == nil || affinity == nil || !affinity.IsSet(int(entry.TargetNodeID)) {
+			continue
+		}
+		hopCount := clampInterconnectValue(*entry.HopCount, "connectivityMatrix.hopCount")
+		if matrixHopCount == nil || hopCount > *matrixHopCount {
+			matrixHopCount = &hopCount
+		}
+	}
+	if matrixHopCount != nil {
+		return matrixHopCount
+	}
+	if info.HopCount != nil {
+		hopCount := clampInterconnectValue(*info.HopCount, "hopCount")
+		return &hopCount
+	}
+	return nil
+}
+
+// TopologyHintFromInterconnectInfo builds a TopologyHint for the given
+// resource with the given affinity and preference, populating the enhanced
+// fields from the resource API's InterconnectInfo. Connectivity matrix
+// entries carrying a PerResource override for the resource are resolved
+// through connectivityForResource first. Fields the driver did not set stay
+// nil, and set fields pass through clampInterconnectValue so callers never
+// need ad-hoc int32 casts. Latency has no TopologyHint counterpart and is
+// ignored. The hint's score is computed under the given policy options.
+func TopologyHintFromInterconnectInfo(affinity bitmask.BitMask, preferred bool, info *draapi.InterconnectInfo, resource string, opts PolicyOptions) TopologyHint {
+	hint := TopologyHint{NUMANodeAffinity: affinity, Preferred: preferred}
+	if info == nil {
+		return hint
+	}
+	hint.HopCount = hopCountForAffinity(affinity, info, resource)
+	if info.Distance != nil {
+		distance := clampInterconnectValue(*info.Distance, "distance")
+		hint.Distance = &distance
+	}
+	if info.Bandwidth != nil {
+		bandwidth := *info.Bandwidth
+		if bandwidth < 0 {
+			klog.InfoS("Interconnect metric below valid range, clamping", "field", "bandwidth", "value", bandwidth)
+			bandwidth = 0
+		}
+		hint.Bandwidth = &bandwidth
+	}
+	hint.UpdateScore(0, opts)
+	return hint
+}
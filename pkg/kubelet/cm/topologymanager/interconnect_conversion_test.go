@@ -0,0 +1,196 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"math"
+	"testing"
+
+	draapi "k8s.io/dynamic-resource-allocation/api"
+)
+
+func TestClampInterconnectValue(t *testing.T) {
+	tcases := []struct {
+		name     string
+		value    int32
+		expected int
+	}{
+		{
+			name:     "zero is preserved",
+			value:    0,
+			expected: 0,
+		},
+		{
+			name:     "upper boundary is preserved",
+			value:    255,
+			expected: 255,
+		},
+		{
+			name:     "above range clamps to the unreachable sentinel",
+			value:    300,
+			expected: UnreachableNUMADistance,
+		},
+		{
+			name:     "negative clamps to zero",
+			value:    -5,
+			expected: 0,
+		},
+	}
+
+	for _, tc := range tcases {
+		actual := clampInterconnectValue(tc.value, "test")
+		if actual != tc.expected {
+			t.Errorf("%v: Expected %d, got %d", tc.name, tc.expected, actual)
+		}
+	}
+}
+
+func TestTopologyHintFromInterconnectInfo(t *testing.T) {
+	hopCount := int32(1)
+	distance := int32(20)
+	bandwidth := float64(50)
+	unreachable := int32(255)
+	outOfRange := int32(300)
+	negativeBandwidth := float64(-1)
+	matrixHopCount := int32(3)
+	otherHopCount := int32(7)
+
+	tcases := []struct {
+		name              string
+		info              *draapi.InterconnectInfo
+		resource          string
+		expectedHopCount  *int
+		expectedDistance  *int
+		expectedBandwidth *float64
+		expectedScore     float64
+	}{
+		{
+			name: "nil info leaves enhanced fields unset",
+			info: nil,
+		},
+		{
+			name: "all fields convert",
+			info: &draapi.InterconnectInfo{
+				HopCount:  &hopCount,
+				Distance:  &distance,
+				Bandwidth: &bandwidth,
+			},
+			expectedHopCount:  intPointer(1),
+			expectedDistance:  intPointer(20),
+			expectedBandwidth: &bandwidth,
+			expectedScore:     scoreHopPenalty + 10*scoreDistancePenalty,
+		},
+		{
+			name: "unreachable distance scores infinite",
+			info: &draapi.InterconnectInfo{
+				Distance: &unreachable,
+			},
+			expectedDistance: intPointer(255),
+			expectedScore:    math.Inf(1),
+		},
+		{
+			name: "matrix hop count for a spanned node beats the scalar",
+			info: &draapi.InterconnectInfo{
+				HopCount: &hopCount,
+				ConnectivityMatrix: []draapi.NodeConnectivity{
+					{TargetNodeID: 0, HopCount: &matrixHopCount},
+					{TargetNodeID: 1, HopCount: &otherHopCount},
+				},
+			},
+			expectedHopCount: intPointer(3),
+			expectedScore:    3 * scoreHopPenalty,
+		},
+		{
+			name: "per-resource override beats the pair-wide entry",
+			info: &draapi.InterconnectInfo{
+				HopCount: &hopCount,
+				ConnectivityMatrix: []draapi.NodeConnectivity{
+					{
+						TargetNodeID: 0,
+						HopCount:     &matrixHopCount,
+						PerResource: map[string]draapi.NodeConnectivity{
+							"example.com/gpu": {HopCount: &otherHopCount},
+						},
+					},
+				},
+			},
+			resource:         "example.com/gpu",
+			expectedHopCount: intPointer(7),
+			expectedScore:    7 * scoreHopPenalty,
+		},
+		{
+			name: "per-resource override for another resource is ignored",
+			info: &draapi.InterconnectInfo{
+				ConnectivityMatrix: []draapi.NodeConnectivity{
+					{
+						TargetNodeID: 0,
+						HopCount:     &matrixHopCount,
+						PerResource: map[string]draapi.NodeConnectivity{
+							"example.com/gpu": {HopCount: &otherHopCount},
+						},
+					},
+				},
+			},
+			resource:         "example.com/nic",
+			expectedHopCount: intPointer(3),
+			expectedScore:    3 * scoreHopPenalty,
+		},
+		{
+			name: "out-of-range values clamp",
+			info: &draapi.InterconnectInfo{
+				HopCount:  &outOfRange,
+				Bandwidth: &negativeBandwidth,
+			},
+			expectedHopCount:  intPointer(UnreachableNUMADistance),
+			expectedBandwidth: float64Pointer(0),
+			expectedScore:     UnreachableNUMADistance * scoreHopPenalty,
+		},
+	}
+
+	for _, tc := range tcases {
+		hint := TopologyHintFromInterconnectInfo(NewTestBitMask(0), true, tc.info, tc.resource, PolicyOptions{})
+		if !hint.NUMANodeAffinity.IsEqual(NewTestBitMask(0)) || !hint.Preferred {
+			t.Errorf("%v: Expected affinity and preference to pass through, got %v", tc.name, hint)
+		}
+		if !equalIntPointer(hint.HopCount, tc.expectedHopCount) {
+			t.Errorf("%v: Expected HopCount %v, got %v", tc.name, tc.expectedHopCount, hint.HopCount)
+		}
+		if !equalIntPointer(hint.Distance, tc.expectedDistance) {
+			t.Errorf("%v: Expected Distance %v, got %v", tc.name, tc.expectedDistance, hint.Distance)
+		}
+		if (hint.Bandwidth == nil) != (tc.expectedBandwidth == nil) || (hint.Bandwidth != nil && *hint.Bandwidth != *tc.expectedBandwidth) {
+			t.Errorf("%v: Expected Bandwidth %v, got %v", tc.name, tc.expectedBandwidth, hint.Bandwidth)
+		}
+		if tc.info == nil {
+			if hint.Score != nil {
+				t.Errorf("%v: Expected no score for basic hint, got %v", tc.name, *hint.Score)
+			}
+			continue
+		}
+		if hint.Score == nil || *hint.Score != tc.expectedScore {
+			t.Errorf("%v: Expected score %v, got %v", tc.name, tc.expectedScore, hint.Score)
+		}
+	}
+}
+
+func intPointer(v int) *int {
+	return &v
+}
+
+func float64Pointer(v float64) *float64 {
+	return &v
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/utils/lru"
+
+	"k8s.io/kubernetes/pkg/features"
+)
+
+// mergeCacheSize bounds the number of cached merge results. Retry storms for
+// the same few pods produce identical provider hints, so a small cache is
+// enough.
+const mergeCacheSize = 128
+
+// mergeCacheKey identifies a cached merge result. The generation is part of
+// the key so that bumping it on a topology change invalidates every
+// previously cached result without an explicit purge. The enhanced bit keys
+// results by the EnhancedTopologyHints gate state at merge time, so a result
+// merged before a live gate flip is never served after it.
+type mergeCacheKey struct {
+	generation  uint64
+	enhanced    bool
+	fingerprint string
+}
+
+// mergeCacheEntry is the cached outcome of a policy merge.
+type mergeCacheEntry struct {
+	hint  TopologyHint
+	admit bool
+}
+
+// mergeResultCache memoizes policy merge results keyed by a fingerprint of
+// the flattened provider hints. Re-admissions of a pod with identical
+// provider hints (e.g. failed admission retries) then skip the full merge.
+type mergeResultCache struct {
+	generation uint64
+	cache      *lru.Cache
+}
+
+func newMergeResultCache() *mergeResultCache {
+	return &mergeResultCache{
+		cache: lru.New(mergeCacheSize),
+	}
+}
+
+func (c *mergeResultCache) get(fingerprint string) (TopologyHint, bool, bool) {
+	value, ok := c.cache.Get(c.key(fingerprint))
+	if !ok {
+		return TopologyHint{}, false, false
+	}
+	entry := value.(mergeCacheEntry)
+	return entry.hint, entry.admit, true
+}
+
+func (c *mergeResultCache) add(fingerprint string, hint TopologyHint, admit bool) {
+	c.cache.Add(c.key(fingerprint), mergeCacheEntry{hint: hint, admit: admit})
+}
+
+func (c *mergeResultCache) key(fingerprint string) mergeCacheKey {
+	return mergeCacheKey{
+		generation:  c.generation,
+		enhanced:    utilfeature.DefaultFeatureGate.Enabled(features.EnhancedTopologyHints),
+		fingerprint: fingerprint,
+	}
+}
+
+// bumpGeneration invalidates all cached merge results. It must be called
+// whenever the underlying topology (or anything else the merge depends on)
+// changes.
+func (c *mergeResultCache) bumpGeneration() {
+	c.generation++
+}
+
+// fingerprintProvidersHints returns a stable hash of the flattened provider
+// hints, distinguishing nil hint lists ("no preference") from empty ones
+// ("no possible affinity") and including the enhanced fields. Each enhanced
+// field is hashed together with its presence, since the getters return
+// defaults for unset fields and an explicitly supplied default value merges
+// differently from an absent one.
+func fingerprintProvidersHints(providersHints []map[string][]TopologyHint) string {
+	hash := fnv.New64a()
+	for _, providerHints := range providersHints {
+		resources := make([]string, 0, len(providerHints))
+		for resource := range providerHints {
+			resources = append(resources, resource)
+		}
+		sort.Strings(resources)
+		for _, resource := range resources {
+			fmt.Fprintf(hash, "%s=%t:", resource, providerHints[resource] == nil)
+			for _, hint := range providerHints[resource] {
+				fmt.Fprintf(hash, "%v/%t/%t%d/%t%d/%t%g/%t%g;", hint.NUMANodeAffinity, hint.Preferred,
+					hint.HasHopCount(), hint.GetHopCount(), hint.HasDistance(), hint.GetDistance(),
+					hint.HasBandwidth(), hint.GetBandwidth(), hint.HasScore(), hint.GetScore())
+			}
+		}
+		fmt.Fprint(hash, "|")
+	}
+	return fmt.Sprintf("%x", hash.Sum64())
+}
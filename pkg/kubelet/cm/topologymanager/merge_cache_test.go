@@ -0,0 +1,162 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	pkgfeatures "k8s.io/kubernetes/pkg/features"
+	"k8s.io/kubernetes/pkg/kubelet/cm/containermap"
+)
+
+type countingMergePolicy struct {
+	nonePolicy
+	mergeCount int
+	hint       TopologyHint
+}
+
+func (p *countingMergePolicy) Merge(providersHints []map[string][]TopologyHint) (TopologyHint, bool) {
+	p.mergeCount++
+	return p.hint, true
+}
+
+func TestMergeCache(t *testing.T) {
+	policy := &countingMergePolicy{
+		hint: TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+	}
+	cScope := &containerScope{
+		scope{
+			name:             containerTopologyScope,
+			podTopologyHints: podTopologyHints{},
+			policy:           policy,
+			podMap:           containermap.NewContainerMap(),
+			mergeCache:       newMergeResultCache(),
+			hintProviders: []HintProvider{
+				&mockHintProvider{
+					map[string][]TopologyHint{
+						"resource": {
+							{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+							{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: false},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{Name: "fakeContainer"},
+			},
+		},
+	}
+
+	firstHint, firstAdmit := cScope.calculateAffinity(pod, &pod.Spec.Containers[0])
+	if policy.mergeCount != 1 {
+		t.Fatalf("Expected one merge after the first admission, got %d", policy.mergeCount)
+	}
+
+	// Identical provider hints must be served from the cache with an
+	// identical result.
+	secondHint, secondAdmit := cScope.calculateAffinity(pod, &pod.Spec.Containers[0])
+	if policy.mergeCount != 1 {
+		t.Errorf("Expected the second admission to hit the cache, got %d merges", policy.mergeCount)
+	}
+	if !firstHint.IsEqual(secondHint) || firstAdmit != secondAdmit {
+		t.Errorf("Expected identical results from the cache, got %v/%v and %v/%v", firstHint, firstAdmit, secondHint, secondAdmit)
+	}
+
+	// A generation bump invalidates all cached results.
+	cScope.mergeCache.bumpGeneration()
+	cScope.calculateAffinity(pod, &pod.Spec.Containers[0])
+	if policy.mergeCount != 2 {
+		t.Errorf("Expected a recomputed merge after a generation bump, got %d merges", policy.mergeCount)
+	}
+}
+
+func TestFingerprintProvidersHints(t *testing.T) {
+	hintsA := []map[string][]TopologyHint{
+		{"resource": {{NUMANodeAffinity: NewTestBitMask(0), Preferred: true}}},
+	}
+	hintsB := []map[string][]TopologyHint{
+		{"resource": {{NUMANodeAffinity: NewTestBitMask(1), Preferred: true}}},
+	}
+	if fingerprintProvidersHints(hintsA) != fingerprintProvidersHints(hintsA) {
+		t.Errorf("Expected identical hints to produce identical fingerprints")
+	}
+	if fingerprintProvidersHints(hintsA) == fingerprintProvidersHints(hintsB) {
+		t.Errorf("Expected different hints to produce different fingerprints")
+	}
+
+	// A nil hint list means "no preference" while an empty one means "no
+	// possible affinity"; they must not collide.
+	nilHints := []map[string][]TopologyHint{{"resource": nil}}
+	emptyHints := []map[string][]TopologyHint{{"resource": {}}}
+	if fingerprintProvidersHints(nilHints) == fingerprintProvidersHints(emptyHints) {
+		t.Errorf("Expected nil and empty hint lists to produce different fingerprints")
+	}
+
+	// An explicitly supplied local distance merges differently from an
+	// absent one (the hint counts as enhanced), so the fingerprints must
+	// not collide even though GetDistance returns the same value for both.
+	localDistance := DefaultLocalDistance
+	explicitLocal := []map[string][]TopologyHint{
+		{"resource": {{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, Distance: &localDistance}}},
+	}
+	unset := []map[string][]TopologyHint{
+		{"resource": {{NUMANodeAffinity: NewTestBitMask(0), Preferred: true}}},
+	}
+	if fingerprintProvidersHints(explicitLocal) == fingerprintProvidersHints(unset) {
+		t.Errorf("Expected an explicit local distance and an unset distance to produce different fingerprints")
+	}
+}
+
+func TestMergeCacheGateFlip(t *testing.T) {
+	cache := newMergeResultCache()
+	hint := TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true}
+
+	// A result cached while the gate is on must not be served after a live
+	// reload flips it off: the basic merger may commit a different hint for
+	// the same provider hints.
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.EnhancedTopologyHints, true)
+	cache.add("fingerprint", hint, true)
+	if _, _, ok := cache.get("fingerprint"); !ok {
+		t.Fatalf("Expected a cache hit with the gate unchanged")
+	}
+
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.EnhancedTopologyHints, false)
+	if _, _, ok := cache.get("fingerprint"); ok {
+		t.Errorf("Expected a cache miss after flipping the gate off")
+	}
+
+	// The same applies in the other direction.
+	cache.add("otherFingerprint", hint, true)
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.EnhancedTopologyHints, true)
+	if _, _, ok := cache.get("otherFingerprint"); ok {
+		t.Errorf("Expected a cache miss after flipping the gate back on")
+	}
+
+	// Flipping the gate back restores the results cached under the matching
+	// gate state; they are still valid.
+	if _, _, ok := cache.get("fingerprint"); !ok {
+		t.Errorf("Expected the result cached with the gate on to be served again once it is back on")
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"testing"
+
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"k8s.io/component-base/metrics/testutil"
+	pkgfeatures "k8s.io/kubernetes/pkg/features"
+	kubeletmetrics "k8s.io/kubernetes/pkg/kubelet/metrics"
+)
+
+func mergeModeMetricValue(t *testing.T, mode string) float64 {
+	t.Helper()
+	value, err := testutil.GetCounterMetricValue(kubeletmetrics.TopologyManagerMergeModeTotal.WithLabelValues(mode))
+	if err != nil {
+		t.Fatalf("Could not read merge mode metric for %q: %v", mode, err)
+	}
+	return value
+}
+
+func TestMergeModeMetric(t *testing.T) {
+	// The metric is normally created during registration; force creation so
+	// WithLabelValues returns live counters in this test.
+	kubeletmetrics.TopologyManagerMergeModeTotal.Create(nil)
+
+	numaInfo := commonNUMAInfoTwoNodes()
+	singleResourceHints := []map[string][]TopologyHint{
+		{
+			"resource1": {
+				{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+			},
+		},
+	}
+
+	tcases := []struct {
+		name         string
+		policy       Policy
+		gateEnabled  bool
+		expectedMode string
+	}{
+		{
+			name:         "gate on, single resource uses the enhanced merger",
+			policy:       NewBestEffortPolicy(numaInfo, PolicyOptions{}),
+			gateEnabled:  true,
+			expectedMode: kubeletmetrics.MergeModeEnhanced,
+		},
+		{
+			name:         "gate on, distributed policy with a single resource falls back to the enhanced merger",
+			policy:       NewDistributedPolicy(numaInfo, PolicyOptions{DistributedNodePenalty: defaultDistributedNodePenalty}),
+			gateEnabled:  true,
+			expectedMode: kubeletmetrics.MergeModeEnhanced,
+		},
+		{
+			name:         "gate off falls back to the basic merger",
+			policy:       NewBestEffortPolicy(numaInfo, PolicyOptions{}),
+			gateEnabled:  false,
+			expectedMode: kubeletmetrics.MergeModeBasicFallback,
+		},
+		{
+			name:         "gate on, observe-only counts separately",
+			policy:       NewBestEffortPolicy(numaInfo, PolicyOptions{EnhancedObserveOnly: true}),
+			gateEnabled:  true,
+			expectedMode: kubeletmetrics.MergeModeObserveOnly,
+		},
+		{
+			name:         "gate on, distributed policy uses the distributed merge",
+			policy:       NewDistributedPolicy(numaInfo, PolicyOptions{DistributedNodePenalty: defaultDistributedNodePenalty, DistributedSingleResourceSpread: true}),
+			gateEnabled:  true,
+			expectedMode: kubeletmetrics.MergeModeDistributed,
+		},
+		{
+			name:         "gate off, distributed policy counts as a basic fallback",
+			policy:       NewDistributedPolicy(numaInfo, PolicyOptions{DistributedNodePenalty: defaultDistributedNodePenalty}),
+			gateEnabled:  false,
+			expectedMode: kubeletmetrics.MergeModeBasicFallback,
+		},
+	}
+
+	modes := []string{kubeletmetrics.MergeModeEnhanced, kubeletmetrics.MergeModeBasicFallback, kubeletmetrics.MergeModeDistributed, kubeletmetrics.MergeModeObserveOnly}
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.EnhancedTopologyHints, tc.gateEnabled)
+
+			before := map[string]float64{}
+			for _, mode := range modes {
+				before[mode] = mergeModeMetricValue(t, mode)
+			}
+
+			tc.policy.Merge(singleResourceHints)
+
+			for _, mode := range modes {
+				expectedDelta := 0.0
+				if mode == tc.expectedMode {
+					expectedDelta = 1.0
+				}
+				if delta := mergeModeMetricValue(t, mode) - before[mode]; delta != expectedDelta {
+					t.Errorf("Expected mode %q to increment by %v, got %v", mode, expectedDelta, delta)
+				}
+			}
+		})
+	}
+}
+
+func TestEnhancedObserveOnlyCommitsBasicResult(t *testing.T) {
+	kubeletmetrics.TopologyManagerMergeModeTotal.Create(nil)
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.EnhancedTopologyHints, true)
+
+	hopCountZero := 0
+	hopCountFour := 4
+	numaInfo := commonNUMAInfoTwoNodes()
+
+	// With score-overrides-preference the enhanced merger picks the
+	// well-scoring non-preferred hint on node 1, while the basic merger keeps
+	// the preferred hint on node 0, so the two paths visibly diverge.
+	providersHints := []map[string][]TopologyHint{
+		{
+			"resource1": {
+				{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, HopCount: &hopCountFour},
+				{NUMANodeAffinity: NewTestBitMask(1), Preferred: false, HopCount: &hopCountZero},
+			},
+		},
+	}
+	opts := PolicyOptions{ScoreOverridesPreference: true}
+
+	enhancedBest, _ := NewBestEffortPolicy(numaInfo, opts).Merge(providersHints)
+	if !enhancedBest.NUMANodeAffinity.IsEqual(NewTestBitMask(1)) {
+		t.Fatalf("Expected the enhanced merge to pick node 1 by score, got %v", enhancedBest)
+	}
+
+	opts.EnhancedObserveOnly = true
+	before := mergeModeMetricValue(t, kubeletmetrics.MergeModeObserveOnly)
+	observedBest, _ := NewBestEffortPolicy(numaInfo, opts).Merge(providersHints)
+	if !observedBest.NUMANodeAffinity.IsEqual(NewTestBitMask(0)) || !observedBest.Preferred {
+		t.Errorf("Expected observe-only to commit the basic result on node 0, got %v", observedBest)
+	}
+	if delta := mergeModeMetricValue(t, kubeletmetrics.MergeModeObserveOnly) - before; delta != 1.0 {
+		t.Errorf("Expected the observe-only merge mode to be recorded once, got %v", delta)
+	}
+}
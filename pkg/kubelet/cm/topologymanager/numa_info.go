@@ -18,8 +18,11 @@ package topologymanager
 
 import (
 	"fmt"
+	"math"
+	"sync"
 
 	cadvisorapi "github.com/google/cadvisor/info/v1"
+	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/kubelet/cm/topologymanager/bitmask"
 )
 
@@ -28,11 +31,22 @@ type NUMADistances map[int][]uint64
 type NUMAInfo struct {
 	Nodes         []int
 	NUMADistances NUMADistances
+	// SocketIDs maps each NUMA node to the physical socket hosting its
+	// cores, so scoring can tell an intra-socket hop from a much more
+	// expensive cross-socket (UPI/QPI) one. Nodes without cores (e.g.
+	// memory-only CXL nodes) have no entry; the map is nil when cadvisor
+	// reports no core information at all.
+	SocketIDs map[int]int
+	// defaultAffinity caches the mask with every node set so merges do not
+	// rebuild it per call. It stays nil for NUMAInfos constructed as literals;
+	// DefaultAffinityMask then builds the mask on demand.
+	defaultAffinity bitmask.BitMask
 }
 
 func NewNUMAInfo(topology []cadvisorapi.Node, opts PolicyOptions) (*NUMAInfo, error) {
 	var numaNodes []int
 	distances := map[int][]uint64{}
+	sockets := map[int]int{}
 	for _, node := range topology {
 		numaNodes = append(numaNodes, node.Id)
 
@@ -44,16 +58,70 @@ func NewNUMAInfo(topology []cadvisorapi.Node, opts PolicyOptions) (*NUMAInfo, er
 			}
 		}
 		distances[node.Id] = nodeDistance
+
+		// All cores of a NUMA node share a socket, so the first core tells
+		// us which one hosts the node.
+		if len(node.Cores) > 0 {
+			sockets[node.Id] = node.Cores[0].SocketID
+		}
 	}
 
+	defaultAffinity, _ := bitmask.NewBitMask(numaNodes...)
+
 	numaInfo := &NUMAInfo{
-		Nodes:         numaNodes,
-		NUMADistances: distances,
+		Nodes:           numaNodes,
+		NUMADistances:   distances,
+		defaultAffinity: defaultAffinity,
+	}
+	if len(sockets) > 0 {
+		numaInfo.SocketIDs = sockets
 	}
 
 	return numaInfo, nil
 }
 
+// DistancesEqual reports whether both NUMAInfos describe the same distance
+// matrix: the same node rows holding the same distances in the same order.
+// A nil other never matches, so a missing previous snapshot counts as drift
+// for callers comparing across reboots.
+func (n *NUMAInfo) DistancesEqual(other *NUMAInfo) bool {
+	if other == nil || len(n.NUMADistances) != len(other.NUMADistances) {
+		return false
+	}
+	for node, row := range n.NUMADistances {
+		otherRow, ok := other.NUMADistances[node]
+		if !ok || len(row) != len(otherRow) {
+			return false
+		}
+		for i := range row {
+			if row[i] != otherRow[i] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// CrossesSocketBoundary reports whether the mask spans NUMA nodes on more
+// than one socket. Nodes without socket information are skipped, so a span
+// over memory-only nodes is never charged as a crossing.
+func (n *NUMAInfo) CrossesSocketBoundary(bm bitmask.BitMask) bool {
+	socket := 0
+	known := false
+	for _, node := range bm.GetBits() {
+		id, ok := n.SocketIDs[node]
+		if !ok {
+			continue
+		}
+		if known && id != socket {
+			return true
+		}
+		socket = id
+		known = true
+	}
+	return false
+}
+
 func (n *NUMAInfo) Narrowest(m1 bitmask.BitMask, m2 bitmask.BitMask) bitmask.BitMask {
 	if m1.IsNarrowerThan(m2) {
 		return m1
@@ -67,25 +135,56 @@ func (n *NUMAInfo) Closest(m1 bitmask.BitMask, m2 bitmask.BitMask) bitmask.BitMa
 		return n.Narrowest(m1, m2)
 	}
 
-	m1Distance := n.NUMADistances.CalculateAverageFor(m1)
-	m2Distance := n.NUMADistances.CalculateAverageFor(m2)
-	// If average distance is the same, take bitmask with more lower-number bits set.
-	if m1Distance == m2Distance {
-		if m1.IsLessThan(m2) {
-			return m1
+	// Otherwise, return the bitmask with the shortest average distance between NUMA nodes.
+	closest, _ := CheapestMask(n, []bitmask.BitMask{m1, m2})
+	return closest
+}
+
+// CheapestMask returns the candidate whose average internal NUMA distance is
+// smallest, together with the total of the pairwise distances inside the
+// winning mask. When averages tie, the mask with more lower-numbered bits set
+// wins, matching Closest. It returns nil when no candidates are given.
+func CheapestMask(numaInfo *NUMAInfo, candidates []bitmask.BitMask) (bitmask.BitMask, uint64) {
+	var cheapest bitmask.BitMask
+	cheapestAverage := math.Inf(1)
+	var cheapestTotal uint64
+	for _, candidate := range candidates {
+		if candidate == nil {
+			continue
+		}
+		average := numaInfo.NUMADistances.CalculateAverageFor(candidate)
+		if cheapest == nil || average < cheapestAverage ||
+			(average == cheapestAverage && candidate.IsLessThan(cheapest)) {
+			cheapest = candidate
+			cheapestAverage = average
+			cheapestTotal = numaInfo.NUMADistances.totalFor(candidate)
 		}
-		return m2
 	}
+	return cheapest, cheapestTotal
+}
 
-	// Otherwise, return the bitmask with the shortest average distance between NUMA nodes.
-	if m1Distance < m2Distance {
-		return m1
+// totalFor sums the pairwise distances between all nodes in the mask,
+// skipping pairs the distance table has no entry for.
+func (d NUMADistances) totalFor(bm bitmask.BitMask) uint64 {
+	var sum uint64
+	for _, node1 := range bm.GetBits() {
+		row := d[node1]
+		for _, node2 := range bm.GetBits() {
+			if node2 < len(row) {
+				sum += row[node2]
+			}
+		}
 	}
-
-	return m2
+	return sum
 }
 
+// DefaultAffinityMask returns the mask with every NUMA node set. The mask is
+// shared across calls and must not be mutated in place; combine it with
+// bitmask.And/Or instead.
 func (n NUMAInfo) DefaultAffinityMask() bitmask.BitMask {
+	if n.defaultAffinity != nil {
+		return n.defaultAffinity
+	}
 	defaultAffinity, _ := bitmask.NewBitMask(n.Nodes...)
 	return defaultAffinity
 }
@@ -99,11 +198,56 @@ func (d NUMADistances) CalculateAverageFor(bm bitmask.BitMask) float64 {
 	var count float64 = 0
 	var sum float64 = 0
 	for _, node1 := range bm.GetBits() {
+		row := d[node1]
 		for _, node2 := range bm.GetBits() {
-			sum += float64(d[node1][node2])
+			// A node the distance matrix does not cover (possible when sysfs
+			// and cadvisor disagree about the node list) must not be scored
+			// optimistically, so charge the fallback distance for the pair:
+			// strongly, but finitely, penalized.
+			if node2 >= len(row) {
+				logUnknownDistanceNodeOnce.Do(func() {
+					klog.InfoS("NUMA distance matrix does not cover a node pair, using the fallback distance", "node", node1, "targetNode", node2, "fallbackDistance", d.fallbackDistance())
+				})
+				sum += d.fallbackDistance()
+				count++
+				continue
+			}
+			distance := row[node2]
+			// Firmware uses 255 to mean "no direct path"; averaging it in
+			// would make unreachable spans look finitely expensive, so treat
+			// the whole span as infinitely distant instead.
+			if distance >= UnreachableNUMADistance {
+				return math.Inf(1)
+			}
+			sum += float64(distance)
 			count++
 		}
 	}
 
 	return sum / count
 }
+
+// unknownNUMADistancePenalty is added on top of the largest known distance
+// when a mask spans a node the distance matrix has no entry for, so unknown
+// spans rank behind every fully-known span without looking unreachable.
+const unknownNUMADistancePenalty = 10
+
+// logUnknownDistanceNodeOnce limits the unknown-node log line to once per
+// kubelet run; the node list mismatch is a machine-level condition, not a
+// per-merge one.
+var logUnknownDistanceNodeOnce sync.Once
+
+// fallbackDistance returns the distance charged for a node pair the matrix
+// has no entry for: the maximum known finite distance plus a penalty. With no
+// finite entries at all, the ACPI local distance serves as the floor.
+func (d NUMADistances) fallbackDistance() float64 {
+	maxKnown := uint64(DefaultLocalDistance)
+	for _, row := range d {
+		for _, distance := range row {
+			if distance > maxKnown && distance < UnreachableNUMADistance {
+				maxKnown = distance
+			}
+		}
+	}
+	return float64(maxKnown + unknownNUMADistancePenalty)
+}
@@ -18,6 +18,7 @@ package topologymanager
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"strings"
 	"testing"
@@ -95,6 +96,35 @@ func TestNUMAInfo(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "positive test 3 nodes, with socket information",
+			topology: []cadvisorapi.Node{
+				{
+					Id:    0,
+					Cores: []cadvisorapi.Core{{Id: 0, SocketID: 0}},
+				},
+				{
+					Id:    1,
+					Cores: []cadvisorapi.Core{{Id: 1, SocketID: 1}},
+				},
+				{
+					// A memory-only node without cores gets no socket entry.
+					Id: 2,
+				},
+			},
+			expectedNUMAInfo: &NUMAInfo{
+				Nodes: []int{0, 1, 2},
+				NUMADistances: NUMADistances{
+					0: nil,
+					1: nil,
+					2: nil,
+				},
+				SocketIDs: map[int]int{
+					0: 0,
+					1: 1,
+				},
+			},
+		},
 		{
 			name: "positive test 2 nodes, with PreferClosestNUMA",
 			topology: []cadvisorapi.Node{
@@ -449,6 +479,11 @@ func TestNUMAInfo(t *testing.T) {
 
 	for _, tcase := range tcases {
 		topology, err := NewNUMAInfo(tcase.topology, tcase.opts)
+		if topology != nil {
+			// The cached default affinity mask is derived from Nodes; clear it
+			// so the literal expectations compare the semantic fields only.
+			topology.defaultAffinity = nil
+		}
 		if tcase.expectedErr == nil && err != nil {
 			t.Fatalf("Expected err to equal nil, not %v", err)
 		} else if tcase.expectedErr != nil && err == nil {
@@ -595,6 +630,44 @@ func TestCalculateAvgDistanceFor(t *testing.T) {
 			distance:    NUMADistances{},
 			expectedAvg: 0,
 		},
+		{
+			name: "mask spans a node absent from the distance matrix",
+			bm: []int{
+				0,
+				2,
+			},
+			distance: NUMADistances{
+				0: {
+					10,
+					11,
+				},
+				1: {
+					11,
+					10,
+				},
+			},
+			// Pairs involving node 2 charge the fallback distance: the
+			// maximum known distance (11) plus the unknown-node penalty.
+			expectedAvg: (10 + 3*(11+unknownNUMADistancePenalty)) / 4.0,
+		},
+		{
+			name: "2 NUMA node, 2 set in bitmask, unreachable sentinel between them",
+			bm: []int{
+				0,
+				1,
+			},
+			distance: NUMADistances{
+				0: {
+					10,
+					255,
+				},
+				1: {
+					255,
+					10,
+				},
+			},
+			expectedAvg: math.Inf(1),
+		},
 	}
 
 	for _, tcase := range tcases {
@@ -686,6 +759,33 @@ func TestClosest(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "current and candidate length is the same, candidate spans unreachable nodes",
+			current:     NewTestBitMask(2, 3),
+			candidate:   NewTestBitMask(0, 1),
+			expected:    "current",
+			numaInfo: &NUMAInfo{
+				NUMADistances: NUMADistances{
+					0: {10, 255, 12, 12},
+					1: {255, 10, 12, 12},
+					2: {12, 12, 10, 11},
+					3: {12, 12, 11, 10},
+				},
+			},
+		},
+		{
+			description: "current and candidate length is the same, current spans unreachable nodes",
+			current:     NewTestBitMask(0, 1),
+			candidate:   NewTestBitMask(1, 2),
+			expected:    "candidate",
+			numaInfo: &NUMAInfo{
+				NUMADistances: NUMADistances{
+					0: {10, 255, 12},
+					1: {255, 10, 12},
+					2: {12, 12, 10},
+				},
+			},
+		},
 	}
 
 	for _, tc := range tcases {
@@ -704,3 +804,211 @@ func TestClosest(t *testing.T) {
 		})
 	}
 }
+
+func TestCheapestMask(t *testing.T) {
+	numaInfo := &NUMAInfo{
+		Nodes: []int{0, 1, 2},
+		NUMADistances: NUMADistances{
+			0: {10, 20, 30},
+			1: {20, 10, 12},
+			2: {30, 12, 10},
+		},
+	}
+
+	tcases := []struct {
+		name          string
+		candidates    []bitmask.BitMask
+		expectedMask  bitmask.BitMask
+		expectedTotal uint64
+	}{
+		{
+			name:          "no candidates",
+			candidates:    nil,
+			expectedMask:  nil,
+			expectedTotal: 0,
+		},
+		{
+			name: "tightest two-node cluster wins",
+			candidates: []bitmask.BitMask{
+				NewTestBitMask(0, 1),
+				NewTestBitMask(1, 2),
+				NewTestBitMask(0, 2),
+			},
+			expectedMask:  NewTestBitMask(1, 2),
+			expectedTotal: 44,
+		},
+		{
+			name: "single-bit candidate scores the local distance",
+			candidates: []bitmask.BitMask{
+				NewTestBitMask(1),
+			},
+			expectedMask:  NewTestBitMask(1),
+			expectedTotal: 10,
+		},
+		{
+			name: "single-bit candidate beats any pair",
+			candidates: []bitmask.BitMask{
+				NewTestBitMask(1, 2),
+				NewTestBitMask(2),
+			},
+			expectedMask:  NewTestBitMask(2),
+			expectedTotal: 10,
+		},
+		{
+			name: "equal averages break toward lower-numbered bits",
+			candidates: []bitmask.BitMask{
+				NewTestBitMask(2),
+				NewTestBitMask(0),
+			},
+			expectedMask:  NewTestBitMask(0),
+			expectedTotal: 10,
+		},
+	}
+
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			mask, total := CheapestMask(numaInfo, tcase.candidates)
+			if tcase.expectedMask == nil {
+				if mask != nil {
+					t.Fatalf("Expected no mask, got %v", mask)
+				}
+			} else if mask == nil || !mask.IsEqual(tcase.expectedMask) {
+				t.Errorf("Expected mask to be %v, got %v", tcase.expectedMask, mask)
+			}
+			if total != tcase.expectedTotal {
+				t.Errorf("Expected total distance to be %v, got %v", tcase.expectedTotal, total)
+			}
+		})
+	}
+}
+
+func TestCrossesSocketBoundary(t *testing.T) {
+	numaInfo := &NUMAInfo{
+		Nodes: []int{0, 1, 2, 3},
+		// Node 3 has no socket entry, e.g. a memory-only node.
+		SocketIDs: map[int]int{0: 0, 1: 0, 2: 1},
+	}
+
+	tcases := []struct {
+		name     string
+		mask     bitmask.BitMask
+		expected bool
+	}{
+		{
+			name:     "single node never crosses",
+			mask:     NewTestBitMask(0),
+			expected: false,
+		},
+		{
+			name:     "nodes on the same socket",
+			mask:     NewTestBitMask(0, 1),
+			expected: false,
+		},
+		{
+			name:     "nodes on different sockets",
+			mask:     NewTestBitMask(1, 2),
+			expected: true,
+		},
+		{
+			name:     "node without socket information is ignored",
+			mask:     NewTestBitMask(2, 3),
+			expected: false,
+		},
+	}
+
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			if actual := numaInfo.CrossesSocketBoundary(tcase.mask); actual != tcase.expected {
+				t.Errorf("Expected CrossesSocketBoundary to be %v for mask %v, got %v", tcase.expected, tcase.mask, actual)
+			}
+		})
+	}
+}
+
+func TestDistancesEqual(t *testing.T) {
+	tcases := []struct {
+		name     string
+		a        NUMADistances
+		b        *NUMAInfo
+		expected bool
+	}{
+		{
+			name: "equal matrices",
+			a: NUMADistances{
+				0: {10, 20},
+				1: {20, 10},
+			},
+			b: &NUMAInfo{NUMADistances: NUMADistances{
+				0: {10, 20},
+				1: {20, 10},
+			}},
+			expected: true,
+		},
+		{
+			name: "different number of nodes",
+			a: NUMADistances{
+				0: {10, 20},
+				1: {20, 10},
+			},
+			b: &NUMAInfo{NUMADistances: NUMADistances{
+				0: {10, 20, 30},
+				1: {20, 10, 20},
+				2: {30, 20, 10},
+			}},
+			expected: false,
+		},
+		{
+			name: "different row lengths",
+			a: NUMADistances{
+				0: {10, 20},
+				1: {20, 10},
+			},
+			b: &NUMAInfo{NUMADistances: NUMADistances{
+				0: {10, 20},
+				1: {20, 10, 30},
+			}},
+			expected: false,
+		},
+		{
+			name: "different distance values",
+			a: NUMADistances{
+				0: {10, 20},
+				1: {20, 10},
+			},
+			b: &NUMAInfo{NUMADistances: NUMADistances{
+				0: {10, 62},
+				1: {62, 10},
+			}},
+			expected: false,
+		},
+		{
+			name: "different node ids",
+			a: NUMADistances{
+				0: {10, 20},
+				1: {20, 10},
+			},
+			b: &NUMAInfo{NUMADistances: NUMADistances{
+				0: {10, 20},
+				2: {20, 10},
+			}},
+			expected: false,
+		},
+		{
+			name: "nil other never matches",
+			a: NUMADistances{
+				0: {10},
+			},
+			b:        nil,
+			expected: false,
+		},
+	}
+
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			numaInfo := &NUMAInfo{NUMADistances: tcase.a}
+			if actual := numaInfo.DistancesEqual(tcase.b); actual != tcase.expected {
+				t.Errorf("Expected DistancesEqual to be %v, got %v", tcase.expected, actual)
+			}
+		})
+	}
+}
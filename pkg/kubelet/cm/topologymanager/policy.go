@@ -17,10 +17,21 @@ limitations under the License.
 package topologymanager
 
 import (
+	"math"
+	"sort"
+
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/kubelet/cm/topologymanager/bitmask"
+	"k8s.io/kubernetes/pkg/kubelet/metrics"
 )
 
+// defaultMaxHintsPerResource is the default cap on the number of hints a
+// single provider may contribute for one resource. It is deliberately large:
+// well-behaved providers stay far below it, and it only exists to bound the
+// merger's combinatorics against a buggy or malicious provider. It can be
+// tuned with the max-hints-per-resource policy option.
+const defaultMaxHintsPerResource = 1024
+
 // Policy interface for Topology Manager Pod Admit Result
 type Policy interface {
 	// Returns Policy Name
@@ -30,6 +41,63 @@ type Policy interface {
 	Merge(providersHints []map[string][]TopologyHint) (TopologyHint, bool)
 }
 
+// MergeResult pairs a candidate merged affinity with whether the policy would
+// admit a pod placed on it, mirroring the pair Merge returns.
+type MergeResult struct {
+	Hint  TopologyHint
+	Admit bool
+	// EnhancedChangedDecision reports whether the enhanced merge reached a
+	// different decision than the basic merge would have, for A/B evaluation
+	// of enhanced scoring. It is only set on the first (best) result, and
+	// only while the EnhancedTopologyHints feature gate is enabled.
+	EnhancedChangedDecision bool
+}
+
+// TopNMergePolicy is an optional interface a Policy can implement to expose
+// its best N candidate placements instead of only the single merged one, so
+// external consumers like a topology-aware scheduler extender can weigh a
+// node's alternatives against other nodes'.
+type TopNMergePolicy interface {
+	Policy
+	// MergeTopN merges like Merge, but returns up to n distinct admissible
+	// candidates ranked best first by the enhanced comparator. The first
+	// result, when one exists, carries the same affinity Merge would commit.
+	MergeTopN(providersHints []map[string][]TopologyHint, n int) []MergeResult
+}
+
+// AntiAffinityAwarePolicy is an optional interface a Policy can implement to
+// honor per-pod resource anti-affinity constraints during merging.
+type AntiAffinityAwarePolicy interface {
+	Policy
+	// MergeWithAntiAffinity merges like Merge, additionally requiring that
+	// the named resources can be placed on pairwise distinct NUMA nodes.
+	// The merge fails when no such placement exists.
+	MergeWithAntiAffinity(providersHints []map[string][]TopologyHint, antiAffinityResources []string) (TopologyHint, bool)
+}
+
+// CapacityAwarePolicy is an optional interface a Policy can implement to
+// weight its merge results by the remaining aligned capacity of each NUMA
+// node, e.g. to spread data toward the nodes with the most room.
+type CapacityAwarePolicy interface {
+	Policy
+	// SetAlignedCapacityProvider supplies the function the policy uses to
+	// look up the remaining per-NUMA-node capacity of a resource at merge
+	// time.
+	SetAlignedCapacityProvider(provider func(resource string) map[int]int64)
+	// CapacityWeighted reports whether merges actually consult the capacity,
+	// so callers can bypass caches that assume identical provider hints
+	// always merge identically.
+	CapacityWeighted() bool
+}
+
+// policyOptionsSource is implemented by the enforcing policies so the scopes
+// can read the options of the policy they admit through without carrying a
+// separate copy. The none policy does not implement it; a zero-valued
+// PolicyOptions then keeps every option at its default.
+type policyOptionsSource interface {
+	policyOptions() PolicyOptions
+}
+
 // IsAlignmentGuaranteed return true if the given policy guarantees that either
 // the compute resources will be allocated within a NUMA boundary, or the allocation will fail at all.
 func IsAlignmentGuaranteed(p Policy) bool {
@@ -65,19 +133,39 @@ func mergePermutation(defaultAffinity bitmask.BitMask, permutation []TopologyHin
 	mergedAffinity := bitmask.And(defaultAffinity, numaAffinities...)
 	// Build a mergedHint from the merged affinity mask, setting preferred as
 	// appropriate based on the logic above.
-	return TopologyHint{mergedAffinity, preferred}
+	return TopologyHint{NUMANodeAffinity: mergedAffinity, Preferred: preferred}
 }
 
-func filterProvidersHints(providersHints []map[string][]TopologyHint) [][]TopologyHint {
+func filterProvidersHints(providersHints []map[string][]TopologyHint, opts PolicyOptions) [][]TopologyHint {
+	providersHints = validateProvidersHints(providersHints, opts.StrictHintValidation)
+	providersHints = constrainLocalityClasses(providersHints, opts.LocalityClasses)
+
+	// A zero-valued options struct keeps the default cap, so callers that
+	// hand-build their PolicyOptions are still protected from hint floods.
+	maxHintsPerResource := opts.MaxHintsPerResource
+	if maxHintsPerResource <= 0 {
+		maxHintsPerResource = defaultMaxHintsPerResource
+	}
+
 	// Loop through all hint providers and save an accumulated list of the
 	// hints returned by each hint provider. If no hints are provided, assume
 	// that provider has no preference for topology-aware allocation.
-	var allProviderHints [][]TopologyHint
+	// Pre-size the accumulated list so device-heavy pods do not regrow it
+	// once per resource.
+	total := 0
+	for _, hints := range providersHints {
+		if len(hints) == 0 {
+			total++
+			continue
+		}
+		total += len(hints)
+	}
+	allProviderHints := make([][]TopologyHint, 0, total)
 	for _, hints := range providersHints {
 		// If hints is nil, insert a single, preferred any-numa hint into allProviderHints.
 		if len(hints) == 0 {
 			klog.InfoS("Hint Provider has no preference for NUMA affinity with any resource")
-			allProviderHints = append(allProviderHints, []TopologyHint{{nil, true}})
+			allProviderHints = append(allProviderHints, []TopologyHint{{NUMANodeAffinity: nil, Preferred: true}})
 			continue
 		}
 
@@ -85,13 +173,20 @@ func filterProvidersHints(providersHints []map[string][]TopologyHint) [][]Topolo
 		for resource := range hints {
 			if hints[resource] == nil {
 				klog.InfoS("Hint Provider has no preference for NUMA affinity with resource", "resource", resource)
-				allProviderHints = append(allProviderHints, []TopologyHint{{nil, true}})
+				allProviderHints = append(allProviderHints, []TopologyHint{{NUMANodeAffinity: nil, Preferred: true}})
 				continue
 			}
 
 			if len(hints[resource]) == 0 {
 				klog.InfoS("Hint Provider has no possible NUMA affinities for resource", "resource", resource)
-				allProviderHints = append(allProviderHints, []TopologyHint{{nil, false}})
+				allProviderHints = append(allProviderHints, []TopologyHint{{NUMANodeAffinity: nil, Preferred: false}})
+				continue
+			}
+
+			if len(hints[resource]) > maxHintsPerResource {
+				klog.InfoS("Hint Provider returned more hints for resource than the cap, truncating", "resource", resource, "hints", len(hints[resource]), "maxHintsPerResource", maxHintsPerResource)
+				metrics.TopologyManagerHintTruncationsTotal.Inc()
+				allProviderHints = append(allProviderHints, truncateHints(hints[resource], maxHintsPerResource))
 				continue
 			}
 
@@ -101,6 +196,146 @@ func filterProvidersHints(providersHints []map[string][]TopologyHint) [][]Topolo
 	return allProviderHints
 }
 
+// truncateHints bounds the number of hints considered for one resource,
+// keeping the best-scoring ones and breaking score ties in favor of the
+// narrowest affinities. The input slice is left untouched.
+func truncateHints(hints []TopologyHint, limit int) []TopologyHint {
+	truncated := make([]TopologyHint, len(hints))
+	copy(truncated, hints)
+	sort.SliceStable(truncated, func(i, j int) bool {
+		if truncated[i].GetScore() != truncated[j].GetScore() {
+			return truncated[i].GetScore() < truncated[j].GetScore()
+		}
+		return hintWidth(&truncated[i]) < hintWidth(&truncated[j])
+	})
+	return truncated[:limit]
+}
+
+// hintWidth orders hints by how many NUMA nodes they span, treating a
+// don't-care affinity as the widest possible.
+func hintWidth(hint *TopologyHint) int {
+	if hint.NUMANodeAffinity == nil {
+		return math.MaxInt
+	}
+	return hint.NUMANodeAffinity.Count()
+}
+
+// validateProvidersHints screens the providers' hints through
+// ValidateTopologyHint before merging. A malformed hint is normally dropped
+// with a log line and the remaining hints merge as usual; with strict set
+// (the strict-hint-validation policy option) the offending resource's hint
+// list is emptied instead, which the policies already treat as an
+// unsatisfiable resource, so the pod is rejected rather than placed on
+// corrupt data. Don't-care (nil) hint lists pass through untouched. The
+// input hints are left untouched.
+func validateProvidersHints(providersHints []map[string][]TopologyHint, strict bool) []map[string][]TopologyHint {
+	validated := make([]map[string][]TopologyHint, 0, len(providersHints))
+	for _, providerHints := range providersHints {
+		validatedProvider := make(map[string][]TopologyHint, len(providerHints))
+		for resource, hints := range providerHints {
+			if hints == nil {
+				validatedProvider[resource] = nil
+				continue
+			}
+			validatedHints := make([]TopologyHint, 0, len(hints))
+			for _, hint := range hints {
+				if err := ValidateTopologyHint(hint); err != nil {
+					if strict {
+						klog.ErrorS(err, "Hint Provider returned a malformed hint, rejecting the resource", "resource", resource, "hint", hint)
+						validatedHints = []TopologyHint{}
+						break
+					}
+					klog.InfoS("Hint Provider returned a malformed hint, dropping it", "resource", resource, "hint", hint, "err", err)
+					continue
+				}
+				validatedHints = append(validatedHints, hint)
+			}
+			validatedProvider[resource] = validatedHints
+		}
+		validated = append(validated, validatedProvider)
+	}
+	return validated
+}
+
+// constrainLocalityClasses enforces the configured locality classes on the
+// providers' hints. Resources mapped to the same class must be placeable on a
+// shared NUMA node — e.g. a GPU and the NIC bonded to it — so each member's
+// hints are filtered down to the masks that overlap the nodes feasible for
+// every other member of its class. A member left without any feasible hint
+// surfaces as an empty hint list, which the policies already treat as an
+// unsatisfiable resource. Don't-care hints carry no placement information and
+// are kept as-is, as are resources whose class has fewer than two members
+// with concrete hints. The input hints are left untouched.
+func constrainLocalityClasses(providersHints []map[string][]TopologyHint, classes map[string]string) []map[string][]TopologyHint {
+	if len(classes) == 0 {
+		return providersHints
+	}
+
+	// Union the NUMA nodes each class member's concrete hints can use, then
+	// intersect the unions per class to find the nodes the whole class can
+	// share.
+	memberCounts := make(map[string]int)
+	sharedNodes := make(map[string]bitmask.BitMask)
+	for _, providerHints := range providersHints {
+		for resource, hints := range providerHints {
+			class, ok := classes[resource]
+			if !ok {
+				continue
+			}
+			feasible := bitmask.NewEmptyBitMask()
+			for _, hint := range hints {
+				if hint.NUMANodeAffinity != nil {
+					feasible.Or(hint.NUMANodeAffinity)
+				}
+			}
+			if feasible.Count() == 0 {
+				continue
+			}
+			memberCounts[class]++
+			if shared, ok := sharedNodes[class]; ok {
+				sharedNodes[class] = bitmask.And(shared, feasible)
+			} else {
+				sharedNodes[class] = feasible
+			}
+		}
+	}
+
+	constrained := false
+	for class, count := range memberCounts {
+		if count > 1 {
+			constrained = true
+			continue
+		}
+		delete(sharedNodes, class)
+	}
+	if !constrained {
+		return providersHints
+	}
+
+	filtered := make([]map[string][]TopologyHint, 0, len(providersHints))
+	for _, providerHints := range providersHints {
+		filteredProvider := make(map[string][]TopologyHint, len(providerHints))
+		for resource, hints := range providerHints {
+			shared, ok := sharedNodes[classes[resource]]
+			if !ok || hints == nil {
+				filteredProvider[resource] = hints
+				continue
+			}
+			filteredHints := make([]TopologyHint, 0, len(hints))
+			for _, hint := range hints {
+				if hint.NUMANodeAffinity != nil && bitmask.And(hint.NUMANodeAffinity, shared).Count() == 0 {
+					klog.InfoS("Dropping topology hint that would separate a locality class", "resource", resource, "localityClass", classes[resource], "hint", hint)
+					continue
+				}
+				filteredHints = append(filteredHints, hint)
+			}
+			filteredProvider[resource] = filteredHints
+		}
+		filtered = append(filtered, filteredProvider)
+	}
+	return filtered
+}
+
 func narrowestHint(hints []TopologyHint) *TopologyHint {
 	if len(hints) == 0 {
 		return nil
@@ -315,7 +550,7 @@ func (m HintMerger) Merge() TopologyHint {
 	})
 
 	if bestHint == nil {
-		bestHint = &TopologyHint{defaultAffinity, false}
+		bestHint = &TopologyHint{NUMANodeAffinity: defaultAffinity, Preferred: false}
 	}
 
 	return *bestHint
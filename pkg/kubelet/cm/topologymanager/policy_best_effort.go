@@ -16,6 +16,10 @@ limitations under the License.
 
 package topologymanager
 
+import (
+	"k8s.io/kubernetes/pkg/kubelet/metrics"
+)
+
 type bestEffortPolicy struct {
 	// numaInfo represents list of NUMA Nodes available on the underlying machine and distances between them
 	numaInfo *NUMAInfo
@@ -23,6 +27,7 @@ type bestEffortPolicy struct {
 }
 
 var _ Policy = &bestEffortPolicy{}
+var _ TopNMergePolicy = &bestEffortPolicy{}
 
 // PolicyBestEffort policy name.
 const PolicyBestEffort string = "best-effort"
@@ -36,14 +41,39 @@ func (p *bestEffortPolicy) Name() string {
 	return PolicyBestEffort
 }
 
+func (p *bestEffortPolicy) policyOptions() PolicyOptions {
+	return p.opts
+}
+
 func (p *bestEffortPolicy) canAdmitPodResult(hint *TopologyHint) bool {
 	return true
 }
 
 func (p *bestEffortPolicy) Merge(providersHints []map[string][]TopologyHint) (TopologyHint, bool) {
-	filteredHints := filterProvidersHints(providersHints)
-	merger := NewHintMerger(p.numaInfo, filteredHints, p.Name(), p.opts)
-	bestHint := merger.Merge()
+	// No provider supplied any hints at all; admit with the default affinity
+	// spanning the whole machine, which is what merging no hints yields.
+	if len(providersHints) == 0 {
+		return TopologyHint{NUMANodeAffinity: p.numaInfo.DefaultAffinityMask(), Preferred: true}, true
+	}
+
+	filteredHints := filterProvidersHints(providersHints, p.opts)
+	bestHint := mergeFilteredHints(p.numaInfo, filteredHints, p.Name(), p.opts)
+	// The merged Preferred flag only holds when every resource contributed a
+	// preferred hint. Best-effort admits either way, so surface the
+	// without-consensus admissions through a dedicated counter.
+	if !bestHint.Preferred {
+		metrics.TopologyManagerNonPreferredAdmissionsTotal.Inc()
+	}
 	admit := p.canAdmitPodResult(&bestHint)
 	return bestHint, admit
 }
+
+// MergeTopN returns up to n distinct candidate placements ranked by the
+// enhanced comparator, best first. Best-effort admits any placement, so every
+// ranked candidate is admissible.
+func (p *bestEffortPolicy) MergeTopN(providersHints []map[string][]TopologyHint, n int) []MergeResult {
+	if len(providersHints) == 0 {
+		return nil
+	}
+	return mergeTopNCandidates(p.numaInfo, filterProvidersHints(providersHints, p.opts), p.Name(), p.opts, n, p.canAdmitPodResult)
+}
@@ -18,6 +18,9 @@ package topologymanager
 
 import (
 	"testing"
+
+	"k8s.io/component-base/metrics/testutil"
+	kubeletmetrics "k8s.io/kubernetes/pkg/kubelet/metrics"
 )
 
 func TestPolicyBestEffortCanAdmitPodResult(t *testing.T) {
@@ -28,12 +31,12 @@ func TestPolicyBestEffortCanAdmitPodResult(t *testing.T) {
 	}{
 		{
 			name:     "Preferred is set to false in topology hints",
-			hint:     TopologyHint{nil, false},
+			hint:     TopologyHint{NUMANodeAffinity: nil, Preferred: false},
 			expected: true,
 		},
 		{
 			name:     "Preferred is set to true in topology hints",
-			hint:     TopologyHint{nil, true},
+			hint:     TopologyHint{NUMANodeAffinity: nil, Preferred: true},
 			expected: true,
 		},
 	}
@@ -73,3 +76,68 @@ func TestPolicyBestEffortMergeClosestNUMA(t *testing.T) {
 
 	testPolicyMerge(policy, tcases, t)
 }
+
+func TestPolicyBestEffortNonPreferredAdmissionsMetric(t *testing.T) {
+	// The metric is normally created during registration; force creation so
+	// the counter is live in this test.
+	kubeletmetrics.TopologyManagerNonPreferredAdmissionsTotal.Create(nil)
+
+	numaInfo := commonNUMAInfoTwoNodes()
+	policy := NewBestEffortPolicy(numaInfo, PolicyOptions{})
+
+	tcases := []struct {
+		name              string
+		providersHints    []map[string][]TopologyHint
+		expectedPreferred bool
+	}{
+		{
+			name: "preferred consensus across all resources",
+			providersHints: []map[string][]TopologyHint{
+				{
+					"resource1": {{NUMANodeAffinity: NewTestBitMask(0), Preferred: true}},
+					"resource2": {{NUMANodeAffinity: NewTestBitMask(0), Preferred: true}},
+				},
+			},
+			expectedPreferred: true,
+		},
+		{
+			name: "no preferred consensus admits as non-preferred",
+			providersHints: []map[string][]TopologyHint{
+				{
+					"resource1": {{NUMANodeAffinity: NewTestBitMask(0), Preferred: true}},
+					"resource2": {{NUMANodeAffinity: NewTestBitMask(1), Preferred: true}},
+				},
+			},
+			expectedPreferred: false,
+		},
+	}
+
+	for _, tc := range tcases {
+		t.Run(tc.name, func(t *testing.T) {
+			before, err := testutil.GetCounterMetricValue(kubeletmetrics.TopologyManagerNonPreferredAdmissionsTotal)
+			if err != nil {
+				t.Fatalf("Could not read non-preferred admissions metric: %v", err)
+			}
+
+			bestHint, admit := policy.Merge(tc.providersHints)
+			if !admit {
+				t.Fatalf("Expected best-effort to admit, got rejection for hint %v", bestHint)
+			}
+			if bestHint.Preferred != tc.expectedPreferred {
+				t.Errorf("Expected the committed hint's Preferred to be %v, got %v", tc.expectedPreferred, bestHint)
+			}
+
+			after, err := testutil.GetCounterMetricValue(kubeletmetrics.TopologyManagerNonPreferredAdmissionsTotal)
+			if err != nil {
+				t.Fatalf("Could not read non-preferred admissions metric: %v", err)
+			}
+			expectedDelta := 0.0
+			if !tc.expectedPreferred {
+				expectedDelta = 1.0
+			}
+			if delta := after - before; delta != expectedDelta {
+				t.Errorf("Expected the non-preferred admissions metric to increment by %v, got %v", expectedDelta, delta)
+			}
+		})
+	}
+}
@@ -0,0 +1,373 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"math"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/features"
+	"k8s.io/kubernetes/pkg/kubelet/cm/topologymanager/bitmask"
+	"k8s.io/kubernetes/pkg/kubelet/metrics"
+)
+
+type distributedPolicy struct {
+	// numaInfo represents list of NUMA Nodes available on the underlying machine and distances between them
+	numaInfo *NUMAInfo
+	opts     PolicyOptions
+	// alignedCapacity looks up the remaining per-NUMA-node capacity of a
+	// resource, used to weight spreads when the capacity-weighted-distribution
+	// policy option is enabled. Nil until the manager wires it up.
+	alignedCapacity func(resource string) map[int]int64
+}
+
+var _ Policy = &distributedPolicy{}
+var _ AntiAffinityAwarePolicy = &distributedPolicy{}
+var _ CapacityAwarePolicy = &distributedPolicy{}
+
+// PolicyDistributed policy name.
+const PolicyDistributed string = "distributed"
+
+// defaultDistributedNodePenalty is the default score cost added for every
+// NUMA node a distributed hint spans beyond the first, so spreading stays a
+// deliberate trade-off rather than a free default. It can be tuned with the
+// distributed-node-penalty policy option.
+const defaultDistributedNodePenalty float64 = 5.0
+
+// NewDistributedPolicy returns distributed policy.
+func NewDistributedPolicy(numaInfo *NUMAInfo, opts PolicyOptions) Policy {
+	return &distributedPolicy{numaInfo: numaInfo, opts: opts}
+}
+
+func (p *distributedPolicy) Name() string {
+	return PolicyDistributed
+}
+
+func (p *distributedPolicy) policyOptions() PolicyOptions {
+	return p.opts
+}
+
+func (p *distributedPolicy) canAdmitPodResult(hint *TopologyHint) bool {
+	return true
+}
+
+func (p *distributedPolicy) SetAlignedCapacityProvider(provider func(resource string) map[int]int64) {
+	p.alignedCapacity = provider
+}
+
+func (p *distributedPolicy) CapacityWeighted() bool {
+	return p.opts.CapacityWeightedDistribution && p.alignedCapacity != nil
+}
+
+func (p *distributedPolicy) Merge(providersHints []map[string][]TopologyHint) (TopologyHint, bool) {
+	// No provider supplied any hints at all, so there is nothing to
+	// distribute over; admit with a don't-care affinity.
+	if len(providersHints) == 0 {
+		return TopologyHint{NUMANodeAffinity: nil, Preferred: true}, true
+	}
+
+	filteredHints := filterProvidersHints(providersHints, p.opts)
+
+	// Without the enhanced hint metrics there is nothing to score a spread
+	// on, so behave like best-effort.
+	if !utilfeature.DefaultFeatureGate.Enabled(features.EnhancedTopologyHints) {
+		bestHint := mergeFilteredHints(p.numaInfo, filteredHints, p.Name(), p.opts)
+		admit := p.canAdmitPodResult(&bestHint)
+		return bestHint, admit
+	}
+
+	// A single resource type has nothing to spread against: its hints are
+	// alternative placements, not co-allocated parts, so standard merging
+	// picks the best one. The distributed-single-resource-spread policy
+	// option forces a spread anyway, e.g. to interleave memory pages across
+	// the capable nodes.
+	if !p.opts.DistributedSingleResourceSpread && countResourceTypes(providersHints) < 2 {
+		bestHint := mergeFilteredHints(p.numaInfo, filteredHints, p.Name(), p.opts)
+		admit := p.canAdmitPodResult(&bestHint)
+		return bestHint, admit
+	}
+
+	metrics.TopologyManagerMergeModeTotal.WithLabelValues(metrics.MergeModeDistributed).Inc()
+	bestHint := p.createDistributedHint(providersHints, filteredHints)
+	admit := p.canAdmitPodResult(&bestHint)
+	return bestHint, admit
+}
+
+// MergeWithAntiAffinity merges like Merge, but first verifies that each of
+// the named resources can be assigned its own NUMA node given the nodes the
+// providers' hints make available to it. When no such pairwise-distinct
+// assignment exists (e.g. on a single-node machine) the merge fails, so the
+// pod is rejected rather than silently colocating the resources.
+func (p *distributedPolicy) MergeWithAntiAffinity(providersHints []map[string][]TopologyHint, antiAffinityResources []string) (TopologyHint, bool) {
+	named := sets.New[string](antiAffinityResources...)
+	candidateNodes := make(map[string]sets.Set[int])
+	for _, provider := range providersHints {
+		for resource, hints := range provider {
+			if !named.Has(resource) {
+				continue
+			}
+			for _, hint := range hints {
+				if hint.NUMANodeAffinity == nil {
+					continue
+				}
+				if candidateNodes[resource] == nil {
+					candidateNodes[resource] = sets.New[int]()
+				}
+				candidateNodes[resource].Insert(hint.NUMANodeAffinity.GetBits()...)
+			}
+		}
+	}
+
+	// Resources without any concrete node candidates have nothing to keep
+	// apart; only the remaining ones participate in the assignment.
+	candidates := make(map[string][]int, len(candidateNodes))
+	var resources []string
+	for resource, nodes := range candidateNodes {
+		candidates[resource] = sets.List(nodes)
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+
+	if len(resources) > 1 && !assignDistinctNodes(resources, candidates, sets.New[int]()) {
+		klog.InfoS("Cannot satisfy resource anti-affinity: no NUMA node assignment keeps the resources apart", "resources", antiAffinityResources)
+		return TopologyHint{NUMANodeAffinity: nil, Preferred: false}, false
+	}
+
+	return p.Merge(providersHints)
+}
+
+// assignDistinctNodes reports whether every resource can be assigned a NUMA
+// node from its candidate list without any two resources sharing one, by
+// backtracking over the (small) candidate sets.
+func assignDistinctNodes(resources []string, candidates map[string][]int, used sets.Set[int]) bool {
+	if len(resources) == 0 {
+		return true
+	}
+	for _, node := range candidates[resources[0]] {
+		if used.Has(node) {
+			continue
+		}
+		used.Insert(node)
+		if assignDistinctNodes(resources[1:], candidates, used) {
+			return true
+		}
+		used.Delete(node)
+	}
+	return false
+}
+
+// createDistributedHint builds a single hint spanning every NUMA node that
+// can contribute capacity to some resource, judged per resource from the
+// providers' hint affinities. Hop counts and bandwidths are averaged uniformly
+// across all hints that carry them, while distances are weighted by how many
+// of the span's nodes each hint covers, so a hint describing most of the
+// spread dominates the reported distance over one describing a single node.
+// The resulting score is charged the configured distribution penalty for each
+// spanned node beyond the first. With the capacity-weighted-distribution
+// policy option the averages are replaced by capacity-weighted ones.
+func (p *distributedPolicy) createDistributedHint(providersHints []map[string][]TopologyHint, filteredHints [][]TopologyHint) TopologyHint {
+	span := bitmask.NewEmptyBitMask()
+	for _, resourceHints := range filteredHints {
+		span.Or(contributingNodes(resourceHints))
+	}
+	preferred := true
+	var hopSum, distanceSum, bandwidthSum float64
+	var hopCount, bandwidthCount int
+	var distanceWeight float64
+	for _, resourceHints := range filteredHints {
+		resourcePreferred := false
+		for i := range resourceHints {
+			hint := &resourceHints[i]
+			if hint.Preferred {
+				resourcePreferred = true
+			}
+			if hint.HopCount != nil {
+				hopSum += float64(*hint.HopCount)
+				hopCount++
+			}
+			if hint.Distance != nil {
+				if weight := float64(spanMembership(hint.NUMANodeAffinity, span)); weight > 0 {
+					distanceSum += weight * float64(*hint.Distance)
+					distanceWeight += weight
+				}
+			}
+			if hint.Bandwidth != nil {
+				bandwidthSum += *hint.Bandwidth
+				bandwidthCount++
+			}
+		}
+		if !resourcePreferred {
+			preferred = false
+		}
+	}
+
+	// No provider named a concrete NUMA node, so the spread is a don't-care.
+	if span.Count() == 0 {
+		return TopologyHint{NUMANodeAffinity: nil, Preferred: preferred}
+	}
+
+	distributedHint := TopologyHint{NUMANodeAffinity: span, Preferred: preferred}
+
+	hop := span.Count() - 1
+	if hopCount > 0 {
+		hop = int(math.Round(hopSum / float64(hopCount)))
+	}
+	distributedHint.HopCount = &hop
+	if distanceWeight > 0 {
+		distance := int(math.Round(distanceSum / distanceWeight))
+		distributedHint.Distance = &distance
+	}
+	if bandwidthCount > 0 {
+		bandwidth := roundMetric(bandwidthSum/float64(bandwidthCount), p.opts.ScoreRoundingDecimals)
+		distributedHint.Bandwidth = &bandwidth
+	}
+
+	// Capacity-weighted distribution replaces the uniform averages, so hints
+	// over roomier NUMA nodes dominate the combined metrics.
+	if p.CapacityWeighted() {
+		hopWeighted, distanceWeighted, bandwidthWeighted := p.capacityWeightedMetrics(providersHints, span)
+		if hopWeighted != nil {
+			distributedHint.HopCount = hopWeighted
+		}
+		if distanceWeighted != nil {
+			distributedHint.Distance = distanceWeighted
+		}
+		if bandwidthWeighted != nil {
+			distributedHint.Bandwidth = bandwidthWeighted
+		}
+	}
+
+	score := CalculateTopologyScore(distributedHint.GetHopCount(), distributedHint.scoringDistance(p.opts), distributedHint.GetBandwidth(), 0, p.opts)
+	score += p.opts.DistributedNodePenalty * float64(span.Count()-1)
+	if p.opts.SocketCrossingPenalty > 0 && p.numaInfo.CrossesSocketBoundary(span) {
+		score += p.opts.SocketCrossingPenalty
+	}
+	distributedHint.Score = &score
+
+	return distributedHint
+}
+
+// capacityWeightedMetrics averages the providers' hint metrics weighted by
+// the share of the span's free capacity held by the NUMA nodes each hint
+// names, per resource, so hints over roomier nodes contribute proportionally
+// more. Resources the capacity provider knows nothing about keep a uniform
+// weight. A metric's result is nil when no hint carried it.
+func (p *distributedPolicy) capacityWeightedMetrics(providersHints []map[string][]TopologyHint, span bitmask.BitMask) (hop *int, distance *int, bandwidth *float64) {
+	var hopSum, distanceSum, bandwidthSum float64
+	var hopWeight, distanceWeight, bandwidthWeight float64
+	for _, provider := range providersHints {
+		for resource, hints := range provider {
+			capacity := p.alignedCapacity(resource)
+			var total int64
+			for _, node := range span.GetBits() {
+				total += capacity[node]
+			}
+			for i := range hints {
+				hint := &hints[i]
+				weight := 1.0
+				if total > 0 && hint.NUMANodeAffinity != nil {
+					var free int64
+					for _, node := range hint.NUMANodeAffinity.GetBits() {
+						if span.IsSet(node) {
+							free += capacity[node]
+						}
+					}
+					weight = float64(free) / float64(total)
+				}
+				if weight == 0 {
+					continue
+				}
+				if hint.HopCount != nil {
+					hopSum += weight * float64(*hint.HopCount)
+					hopWeight += weight
+				}
+				if hint.Distance != nil {
+					distanceSum += weight * float64(*hint.Distance)
+					distanceWeight += weight
+				}
+				if hint.Bandwidth != nil {
+					bandwidthSum += weight * *hint.Bandwidth
+					bandwidthWeight += weight
+				}
+			}
+		}
+	}
+	if hopWeight > 0 {
+		value := int(math.Round(hopSum / hopWeight))
+		hop = &value
+	}
+	if distanceWeight > 0 {
+		value := int(math.Round(distanceSum / distanceWeight))
+		distance = &value
+	}
+	if bandwidthWeight > 0 {
+		value := roundMetric(bandwidthSum/bandwidthWeight, p.opts.ScoreRoundingDecimals)
+		bandwidth = &value
+	}
+	return hop, distance, bandwidth
+}
+
+// spanMembership returns how many of the span's NUMA nodes the affinity
+// covers, used to weight a hint's distance by its share of the spread. A
+// don't-care affinity describes the whole span.
+func spanMembership(affinity bitmask.BitMask, span bitmask.BitMask) int {
+	if affinity == nil {
+		return span.Count()
+	}
+	return bitmask.And(affinity, span).Count()
+}
+
+// countResourceTypes returns the number of distinct resource types named
+// across all providers' hints.
+func countResourceTypes(providersHints []map[string][]TopologyHint) int {
+	resources := sets.New[string]()
+	for _, provider := range providersHints {
+		for resource := range provider {
+			resources.Insert(resource)
+		}
+	}
+	return resources.Len()
+}
+
+// contributingNodes returns the NUMA nodes that can contribute capacity to a
+// resource, as the union of the affinities of the resource's narrowest hints.
+// Providers only emit a mask of minimal width when the nodes in it together
+// hold the capacity, whereas a node that shows up solely in wider masks rides
+// along without adding anything, so it is kept out of the distributed span.
+func contributingNodes(resourceHints []TopologyHint) bitmask.BitMask {
+	narrowest := 0
+	for i := range resourceHints {
+		affinity := resourceHints[i].NUMANodeAffinity
+		if affinity == nil || affinity.Count() == 0 {
+			continue
+		}
+		if narrowest == 0 || affinity.Count() < narrowest {
+			narrowest = affinity.Count()
+		}
+	}
+	nodes := bitmask.NewEmptyBitMask()
+	for i := range resourceHints {
+		affinity := resourceHints[i].NUMANodeAffinity
+		if affinity != nil && affinity.Count() == narrowest {
+			nodes.Or(affinity)
+		}
+	}
+	return nodes
+}
@@ -0,0 +1,649 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	pkgfeatures "k8s.io/kubernetes/pkg/features"
+	"k8s.io/kubernetes/pkg/kubelet/cm/topologymanager/bitmask"
+)
+
+func TestPolicyDistributedCanAdmitPodResult(t *testing.T) {
+	tcases := []struct {
+		name     string
+		hint     TopologyHint
+		expected bool
+	}{
+		{
+			name:     "Preferred is set to false in topology hints",
+			hint:     TopologyHint{NUMANodeAffinity: nil, Preferred: false},
+			expected: true,
+		},
+		{
+			name:     "Preferred is set to true in topology hints",
+			hint:     TopologyHint{NUMANodeAffinity: nil, Preferred: true},
+			expected: true,
+		},
+	}
+
+	for _, tc := range tcases {
+		numaInfo := commonNUMAInfoTwoNodes()
+		policy := &distributedPolicy{numaInfo: numaInfo}
+		result := policy.canAdmitPodResult(&tc.hint)
+
+		if result != tc.expected {
+			t.Errorf("Expected result to be %t, got %t", tc.expected, result)
+		}
+	}
+}
+
+func TestPolicyDistributedMerge(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.EnhancedTopologyHints, true)
+
+	hopOne := 1
+	scoreSpread := defaultDistributedNodePenalty + scoreHopPenalty
+
+	tcases := []struct {
+		name           string
+		providersHints []map[string][]TopologyHint
+		expected       TopologyHint
+		expectedAdmit  bool
+	}{
+		{
+			name:           "No hints provided",
+			providersHints: []map[string][]TopologyHint{},
+			expected:       TopologyHint{NUMANodeAffinity: nil, Preferred: true},
+			expectedAdmit:  true,
+		},
+		{
+			name: "Single resource type falls back to standard merging",
+			providersHints: []map[string][]TopologyHint{
+				{
+					"resource1": {
+						{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+					},
+				},
+			},
+			expected: TopologyHint{
+				NUMANodeAffinity: NewTestBitMask(0),
+				Preferred:        true,
+			},
+			expectedAdmit: true,
+		},
+		{
+			name: "Two providers spread across two nodes",
+			providersHints: []map[string][]TopologyHint{
+				{
+					"resource1": {
+						{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+					},
+				},
+				{
+					"resource2": {
+						{NUMANodeAffinity: NewTestBitMask(1), Preferred: true},
+					},
+				},
+			},
+			expected: TopologyHint{
+				NUMANodeAffinity: NewTestBitMask(0, 1),
+				Preferred:        true,
+				HopCount:         &hopOne,
+				Score:            &scoreSpread,
+			},
+			expectedAdmit: true,
+		},
+		{
+			name: "Non-preferred resource makes the spread non-preferred",
+			providersHints: []map[string][]TopologyHint{
+				{
+					"resource1": {
+						{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+					},
+					"resource2": {
+						{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: false},
+					},
+				},
+			},
+			expected: TopologyHint{
+				NUMANodeAffinity: NewTestBitMask(0, 1),
+				Preferred:        false,
+				HopCount:         &hopOne,
+				Score:            &scoreSpread,
+			},
+			expectedAdmit: true,
+		},
+	}
+
+	numaInfo := commonNUMAInfoTwoNodes()
+	policy := &distributedPolicy{numaInfo: numaInfo, opts: PolicyOptions{DistributedNodePenalty: defaultDistributedNodePenalty}}
+	for _, tc := range tcases {
+		actual, admit := policy.Merge(tc.providersHints)
+		if !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("%v: Expected Topology Hint to be %v, got %v", tc.name, tc.expected, actual)
+		}
+		if admit != tc.expectedAdmit {
+			t.Errorf("%v: Expected admit to be %t, got %t", tc.name, tc.expectedAdmit, admit)
+		}
+	}
+}
+
+func TestPolicyDistributedSingleResourceSpread(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.EnhancedTopologyHints, true)
+
+	// A single resource type whose capacity is split across both nodes.
+	providersHints := []map[string][]TopologyHint{
+		{
+			"memory": {
+				{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+				{NUMANodeAffinity: NewTestBitMask(1), Preferred: true},
+			},
+		},
+	}
+
+	numaInfo := commonNUMAInfoTwoNodes()
+	consensus := &distributedPolicy{numaInfo: numaInfo, opts: PolicyOptions{DistributedNodePenalty: defaultDistributedNodePenalty}}
+	spread := &distributedPolicy{numaInfo: numaInfo, opts: PolicyOptions{DistributedNodePenalty: defaultDistributedNodePenalty, DistributedSingleResourceSpread: true}}
+
+	// By default a lone resource is not spread: the policy falls back to
+	// standard merging and settles on one node.
+	consensusHint, admit := consensus.Merge(providersHints)
+	if !admit {
+		t.Fatalf("Expected the default merge to admit")
+	}
+	if consensusHint.NUMANodeAffinity == nil || consensusHint.NUMANodeAffinity.Count() != 1 {
+		t.Errorf("Expected the default merge to settle on a single node, got %v", consensusHint.NUMANodeAffinity)
+	}
+	if !consensusHint.Preferred {
+		t.Errorf("Expected the default merge result to be preferred, got %v", consensusHint)
+	}
+
+	// With the option enabled the same hints span both capable nodes.
+	spreadHint, admit := spread.Merge(providersHints)
+	if !admit {
+		t.Fatalf("Expected the spread merge to admit")
+	}
+	if !spreadHint.NUMANodeAffinity.IsEqual(NewTestBitMask(0, 1)) {
+		t.Errorf("Expected the spread to cover both nodes, got %v", spreadHint.NUMANodeAffinity)
+	}
+	expectedScore := scoreHopPenalty + defaultDistributedNodePenalty
+	if spreadHint.GetScore() != expectedScore {
+		t.Errorf("Expected the spread score to be %v, got %v", expectedScore, spreadHint.GetScore())
+	}
+}
+
+func TestPolicyDistributedMergeFeatureGateDisabled(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.EnhancedTopologyHints, false)
+
+	numaInfo := commonNUMAInfoTwoNodes()
+	policy := &distributedPolicy{numaInfo: numaInfo}
+
+	// With the gate off the policy behaves like best-effort: disjoint
+	// preferred hints merge to the non-preferred default affinity.
+	providersHints := []map[string][]TopologyHint{
+		{
+			"resource1": {
+				{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+			},
+		},
+		{
+			"resource2": {
+				{NUMANodeAffinity: NewTestBitMask(1), Preferred: true},
+			},
+		},
+	}
+
+	actual, admit := policy.Merge(providersHints)
+	expected := TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: false}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected Topology Hint to be %v, got %v", expected, actual)
+	}
+	if !admit {
+		t.Errorf("Expected admit to be true, got false")
+	}
+}
+
+func TestPolicyDistributedNodePenaltyOption(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.EnhancedTopologyHints, true)
+
+	// Two providers forcing a two-node spread.
+	providersHints := []map[string][]TopologyHint{
+		{
+			"resource1": {
+				{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+			},
+		},
+		{
+			"resource2": {
+				{NUMANodeAffinity: NewTestBitMask(1), Preferred: true},
+			},
+		},
+	}
+
+	numaInfo := commonNUMAInfoTwoNodes()
+	defaultPolicy := &distributedPolicy{numaInfo: numaInfo, opts: PolicyOptions{DistributedNodePenalty: defaultDistributedNodePenalty}}
+	cheapPolicy := &distributedPolicy{numaInfo: numaInfo, opts: PolicyOptions{DistributedNodePenalty: 0.5}}
+
+	defaultHint, _ := defaultPolicy.Merge(providersHints)
+	cheapHint, _ := cheapPolicy.Merge(providersHints)
+
+	expectedDefault := scoreHopPenalty + defaultDistributedNodePenalty
+	if defaultHint.GetScore() != expectedDefault {
+		t.Errorf("Expected default penalty score to be %v, got %v", expectedDefault, defaultHint.GetScore())
+	}
+	expectedCheap := scoreHopPenalty + 0.5
+	if cheapHint.GetScore() != expectedCheap {
+		t.Errorf("Expected low penalty score to be %v, got %v", expectedCheap, cheapHint.GetScore())
+	}
+
+	// A lower penalty makes the same wide spread more attractive.
+	if cheapHint.GetScore() >= defaultHint.GetScore() {
+		t.Errorf("Expected the low penalty spread score %v to beat the default %v", cheapHint.GetScore(), defaultHint.GetScore())
+	}
+}
+
+func TestPolicyDistributedMergeWithAntiAffinity(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.EnhancedTopologyHints, true)
+
+	policy := &distributedPolicy{numaInfo: commonNUMAInfoTwoNodes(), opts: PolicyOptions{DistributedNodePenalty: defaultDistributedNodePenalty}}
+
+	tcases := []struct {
+		name           string
+		providersHints []map[string][]TopologyHint
+		antiAffinity   []string
+		expectedAdmit  bool
+	}{
+		{
+			name: "split possible across two nodes",
+			providersHints: []map[string][]TopologyHint{
+				{
+					"nic-a": {
+						{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+					},
+				},
+				{
+					"nic-b": {
+						{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+						{NUMANodeAffinity: NewTestBitMask(1), Preferred: true},
+					},
+				},
+			},
+			antiAffinity:  []string{"nic-a", "nic-b"},
+			expectedAdmit: true,
+		},
+		{
+			name: "impossible when both resources only have node 0",
+			providersHints: []map[string][]TopologyHint{
+				{
+					"nic-a": {
+						{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+					},
+				},
+				{
+					"nic-b": {
+						{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+					},
+				},
+			},
+			antiAffinity:  []string{"nic-a", "nic-b"},
+			expectedAdmit: false,
+		},
+		{
+			name: "resources outside the constraint do not block admission",
+			providersHints: []map[string][]TopologyHint{
+				{
+					"nic-a": {
+						{NUMANodeAffinity: NewTestBitMask(1), Preferred: true},
+					},
+					"othergpu": {
+						{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+					},
+				},
+				{
+					"nic-b": {
+						{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+					},
+				},
+			},
+			antiAffinity:  []string{"nic-a", "nic-b"},
+			expectedAdmit: true,
+		},
+	}
+
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			hint, admit := policy.MergeWithAntiAffinity(tcase.providersHints, tcase.antiAffinity)
+			if admit != tcase.expectedAdmit {
+				t.Errorf("Expected admit to be %v, got %v", tcase.expectedAdmit, admit)
+			}
+			if !tcase.expectedAdmit && hint.Preferred {
+				t.Errorf("Expected the rejected hint not to be preferred, got %v", hint)
+			}
+		})
+	}
+}
+
+func TestPolicyDistributedLocalityClasses(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.EnhancedTopologyHints, true)
+
+	policy := &distributedPolicy{numaInfo: commonNUMAInfoTwoNodes(), opts: PolicyOptions{DistributedNodePenalty: defaultDistributedNodePenalty}}
+
+	// The GPU can live on either node, but the NIC bonded to it sits on
+	// node 0 only.
+	providersHints := []map[string][]TopologyHint{
+		{
+			"example.com/gpu": {
+				{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+				{NUMANodeAffinity: NewTestBitMask(1), Preferred: true},
+			},
+		},
+		{
+			"example.com/nic": {
+				{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+			},
+		},
+	}
+
+	hint, admit := policy.Merge(providersHints)
+	if !admit || !hint.NUMANodeAffinity.IsEqual(NewTestBitMask(0, 1)) {
+		t.Fatalf("Expected the unconstrained spread to span both nodes, got %v with admit %v", hint, admit)
+	}
+
+	policy.opts.LocalityClasses = map[string]string{"example.com/gpu": "bonded", "example.com/nic": "bonded"}
+	hint, admit = policy.Merge(providersHints)
+	if !admit {
+		t.Errorf("Expected admit to be true, got false")
+	}
+	if !hint.NUMANodeAffinity.IsEqual(NewTestBitMask(0)) {
+		t.Errorf("Expected the locality class to force both resources onto node 0, got %v", hint.NUMANodeAffinity)
+	}
+}
+
+func TestPolicyDistributedSpanExcludesIncapableNodes(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.EnhancedTopologyHints, true)
+
+	// Spreading a lone resource is opt-in, and the single-resource case below
+	// exercises exactly that.
+	policy := &distributedPolicy{numaInfo: commonNUMAInfoTwoNodes(), opts: PolicyOptions{DistributedNodePenalty: defaultDistributedNodePenalty, DistributedSingleResourceSpread: true}}
+
+	tcases := []struct {
+		name           string
+		providersHints []map[string][]TopologyHint
+		expectedSpan   []int
+	}{
+		{
+			name: "node without capacity only appears in a wider mask and is excluded",
+			providersHints: []map[string][]TopologyHint{
+				{
+					// All devices sit on node 0; the {0,1} hint only exists
+					// because the provider enumerates every covering mask.
+					"gpu": {
+						{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+						{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: false},
+					},
+				},
+			},
+			expectedSpan: []int{0},
+		},
+		{
+			name: "nodes with capacity for different resources both stay in the span",
+			providersHints: []map[string][]TopologyHint{
+				{
+					"gpu": {
+						{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+						{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: false},
+					},
+				},
+				{
+					"nic": {
+						{NUMANodeAffinity: NewTestBitMask(1), Preferred: true},
+					},
+				},
+			},
+			expectedSpan: []int{0, 1},
+		},
+	}
+
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			hint, admit := policy.Merge(tcase.providersHints)
+			if !admit {
+				t.Fatalf("Expected admit to be true, got false")
+			}
+			if !hint.NUMANodeAffinity.IsEqual(NewTestBitMask(tcase.expectedSpan...)) {
+				t.Errorf("Expected the distributed span to be %v, got %v", tcase.expectedSpan, hint.NUMANodeAffinity)
+			}
+		})
+	}
+}
+
+func TestPolicyDistributedCapacityWeightedScore(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.EnhancedTopologyHints, true)
+
+	bandwidthNode0, distanceNode0 := 100.0, 10
+	bandwidthNode1, distanceNode1 := 50.0, 30
+	providersHints := []map[string][]TopologyHint{
+		{
+			"memory": {
+				{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, Distance: &distanceNode0, Bandwidth: &bandwidthNode0},
+				{NUMANodeAffinity: NewTestBitMask(1), Preferred: true, Distance: &distanceNode1, Bandwidth: &bandwidthNode1},
+			},
+		},
+	}
+	// Free memory per NUMA node, as the memory manager would report it
+	// through AlignedCapacity: node 0 has three times the room of node 1.
+	freeMemory := map[int]int64{0: 3 * 1024 * 1024 * 1024, 1: 1 * 1024 * 1024 * 1024}
+
+	uniform := &distributedPolicy{numaInfo: commonNUMAInfoTwoNodes(), opts: PolicyOptions{DistributedNodePenalty: defaultDistributedNodePenalty, DistributedSingleResourceSpread: true}}
+	weighted := &distributedPolicy{numaInfo: commonNUMAInfoTwoNodes(), opts: PolicyOptions{DistributedNodePenalty: defaultDistributedNodePenalty, DistributedSingleResourceSpread: true, CapacityWeightedDistribution: true}}
+	weighted.SetAlignedCapacityProvider(func(resource string) map[int]int64 {
+		if resource != "memory" {
+			return nil
+		}
+		return freeMemory
+	})
+
+	uniformHint, admit := uniform.Merge(providersHints)
+	if !admit {
+		t.Fatalf("Expected the uniform merge to admit")
+	}
+	weightedHint, admit := weighted.Merge(providersHints)
+	if !admit {
+		t.Fatalf("Expected the weighted merge to admit")
+	}
+
+	if !weightedHint.NUMANodeAffinity.IsEqual(uniformHint.NUMANodeAffinity) {
+		t.Errorf("Expected the weighting to leave the span unchanged, got %v and %v", weightedHint.NUMANodeAffinity, uniformHint.NUMANodeAffinity)
+	}
+	// Uniform averaging gives (100+50)/2 = 75; weighting by the 3:1 free
+	// memory split gives 0.75*100 + 0.25*50 = 87.5.
+	if uniformHint.GetBandwidth() != 75.0 {
+		t.Errorf("Expected the uniform bandwidth to be 75, got %v", uniformHint.GetBandwidth())
+	}
+	if weightedHint.GetBandwidth() != 87.5 {
+		t.Errorf("Expected the weighted bandwidth to be 87.5, got %v", weightedHint.GetBandwidth())
+	}
+	// The same 3:1 weighting pulls the averaged distance from 20 down to 15,
+	// so the weighted spread scores better than the uniform one.
+	if uniformHint.GetDistance() != 20 || weightedHint.GetDistance() != 15 {
+		t.Errorf("Expected distances 20 (uniform) and 15 (weighted), got %v and %v", uniformHint.GetDistance(), weightedHint.GetDistance())
+	}
+	if weightedHint.GetScore() >= uniformHint.GetScore() {
+		t.Errorf("Expected the weighted score %v to beat the uniform score %v", weightedHint.GetScore(), uniformHint.GetScore())
+	}
+}
+
+func TestPolicyDistributedWeightedDistance(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.EnhancedTopologyHints, true)
+
+	distanceNear := 10
+	distanceFar := 30
+
+	// resource1 sits on node 0 alone while resource2 spans both nodes, so the
+	// far distance describes twice as much of the spread as the near one. A
+	// plain mean would report 20; weighting by span membership reports
+	// (1*10 + 2*30) / 3 = 23.
+	providersHints := []map[string][]TopologyHint{
+		{
+			"resource1": {
+				{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, Distance: &distanceNear},
+			},
+		},
+		{
+			"resource2": {
+				{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: true, Distance: &distanceFar},
+			},
+		},
+	}
+
+	policy := &distributedPolicy{numaInfo: commonNUMAInfoTwoNodes(), opts: PolicyOptions{DistributedNodePenalty: defaultDistributedNodePenalty}}
+	hint, admit := policy.Merge(providersHints)
+	if !admit {
+		t.Fatalf("Expected distributed merge to admit, got %v", hint)
+	}
+	if !hint.NUMANodeAffinity.IsEqual(NewTestBitMask(0, 1)) {
+		t.Fatalf("Expected the hint to span both nodes, got %v", hint.NUMANodeAffinity)
+	}
+	if hint.GetDistance() != 23 {
+		t.Errorf("Expected span-membership weighted distance to be 23, got %d", hint.GetDistance())
+	}
+}
+
+// benchmarkNUMAInfo builds a NUMAInfo literal for numNodes nodes with local
+// distance on the diagonal and a uniform remote distance elsewhere.
+func benchmarkNUMAInfo(numNodes int) *NUMAInfo {
+	nodes := make([]int, numNodes)
+	distances := NUMADistances{}
+	for i := 0; i < numNodes; i++ {
+		nodes[i] = i
+		row := make([]uint64, numNodes)
+		for j := range row {
+			row[j] = 20
+		}
+		row[i] = 10
+		distances[i] = row
+	}
+	return &NUMAInfo{Nodes: nodes, NUMADistances: distances}
+}
+
+// benchmarkProvidersHints builds four resources with one enhanced single-node
+// hint per NUMA node each, the shape a busy device-heavy node produces.
+func benchmarkProvidersHints(numNodes int) []map[string][]TopologyHint {
+	providersHints := make([]map[string][]TopologyHint, 0, 4)
+	for r := 0; r < 4; r++ {
+		hints := make([]TopologyHint, 0, numNodes)
+		for node := 0; node < numNodes; node++ {
+			hopCount := node % 3
+			distance := 10 + node
+			bandwidth := float64(50 + 10*r)
+			hints = append(hints, TopologyHint{
+				NUMANodeAffinity: NewTestBitMask(node),
+				Preferred:        true,
+				HopCount:         &hopCount,
+				Distance:         &distance,
+				Bandwidth:        &bandwidth,
+			})
+		}
+		providersHints = append(providersHints, map[string][]TopologyHint{
+			fmt.Sprintf("resource%d", r): hints,
+		})
+	}
+	return providersHints
+}
+
+func BenchmarkDistributedMerge(b *testing.B) {
+	featuregatetesting.SetFeatureGateDuringTest(b, utilfeature.DefaultFeatureGate, pkgfeatures.EnhancedTopologyHints, true)
+
+	for _, numNodes := range []int{8, 16} {
+		b.Run(fmt.Sprintf("nodes-%d", numNodes), func(b *testing.B) {
+			policy := &distributedPolicy{numaInfo: benchmarkNUMAInfo(numNodes), opts: PolicyOptions{DistributedNodePenalty: defaultDistributedNodePenalty}}
+			providersHints := benchmarkProvidersHints(numNodes)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				policy.Merge(providersHints)
+			}
+		})
+	}
+}
+
+func TestDistributedMergeLargeTopology(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.EnhancedTopologyHints, true)
+
+	const numNodes = 16
+	policy := &distributedPolicy{numaInfo: benchmarkNUMAInfo(numNodes), opts: PolicyOptions{DistributedNodePenalty: defaultDistributedNodePenalty}}
+	providersHints := benchmarkProvidersHints(numNodes)
+
+	hint, admit := policy.Merge(providersHints)
+	if !admit {
+		t.Fatalf("Expected distributed merge to admit, got %v", hint)
+	}
+
+	// Recompute the distributed hint naively from the fixture and require the
+	// optimized path to produce the identical result.
+	span := bitmask.NewEmptyBitMask()
+	for _, provider := range providersHints {
+		for _, hints := range provider {
+			for _, h := range hints {
+				span.Or(h.NUMANodeAffinity)
+			}
+		}
+	}
+	var hopSum, distanceSum, distanceWeight, bandwidthSum float64
+	var hopCount, bandwidthCount int
+	for _, provider := range providersHints {
+		for _, hints := range provider {
+			for _, h := range hints {
+				hopSum += float64(*h.HopCount)
+				hopCount++
+				weight := float64(bitmask.And(h.NUMANodeAffinity, span).Count())
+				distanceSum += weight * float64(*h.Distance)
+				distanceWeight += weight
+				bandwidthSum += *h.Bandwidth
+				bandwidthCount++
+			}
+		}
+	}
+	expectedHop := int(math.Round(hopSum / float64(hopCount)))
+	expectedDistance := int(math.Round(distanceSum / distanceWeight))
+	expectedBandwidth := bandwidthSum / float64(bandwidthCount)
+	expectedScore := CalculateTopologyScore(expectedHop, expectedDistance, expectedBandwidth, 0, PolicyOptions{}) + defaultDistributedNodePenalty*float64(numNodes-1)
+
+	if !hint.NUMANodeAffinity.IsEqual(span) {
+		t.Errorf("Expected the hint to span %v, got %v", span, hint.NUMANodeAffinity)
+	}
+	if !hint.Preferred {
+		t.Errorf("Expected the hint to be preferred, got %v", hint)
+	}
+	if hint.GetHopCount() != expectedHop {
+		t.Errorf("Expected hop count %d, got %d", expectedHop, hint.GetHopCount())
+	}
+	if hint.GetDistance() != expectedDistance {
+		t.Errorf("Expected distance %d, got %d", expectedDistance, hint.GetDistance())
+	}
+	if hint.GetBandwidth() != expectedBandwidth {
+		t.Errorf("Expected bandwidth %v, got %v", expectedBandwidth, hint.GetBandwidth())
+	}
+	if hint.GetScore() != expectedScore {
+		t.Errorf("Expected score %v, got %v", expectedScore, hint.GetScore())
+	}
+}
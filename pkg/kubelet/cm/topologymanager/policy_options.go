@@ -19,6 +19,7 @@ package topologymanager
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/util/sets"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
@@ -27,13 +28,52 @@ import (
 )
 
 const (
-	PreferClosestNUMANodes string = "prefer-closest-numa-nodes"
-	MaxAllowableNUMANodes  string = "max-allowable-numa-nodes"
+	PreferClosestNUMANodes          string = "prefer-closest-numa-nodes"
+	MaxAllowableNUMANodes           string = "max-allowable-numa-nodes"
+	MergeDiagnostics                string = "merge-diagnostics"
+	DistributedNodePenalty          string = "distributed-node-penalty"
+	LocalNUMADistance               string = "local-numa-distance"
+	ScoreOverridesPreference        string = "score-overrides-preference"
+	ScoreRoundingDecimals           string = "score-rounding-decimals"
+	CapacityWeightedDistribution    string = "capacity-weighted-distribution"
+	MaxHintsPerResource             string = "max-hints-per-resource"
+	DisabledPolicies                string = "disabled-policies"
+	EnhancedObserveOnly             string = "enhanced-observe-only"
+	DistributedSingleResourceSpread string = "distributed-single-resource-spread"
+	SocketCrossingPenalty           string = "socket-crossing-penalty"
+	PreferPodSiblingNodes           string = "prefer-pod-sibling-nodes"
+	ScoreHysteresis                 string = "score-hysteresis"
+	NonePolicyShadowMetrics         string = "none-policy-shadow-metrics"
+	PolicyOverrideNamespaces        string = "policy-override-namespaces"
+	LocalityClasses                 string = "locality-classes"
+	ExcludeInitContainers           string = "exclude-init-containers"
+	StrictHintValidation            string = "strict-hint-validation"
+	MemoryPressureFreeFraction      string = "memory-pressure-free-fraction"
 )
 
 var (
-	alphaOptions = sets.New[string]()
-	betaOptions  = sets.New[string](
+	alphaOptions = sets.New[string](
+		MergeDiagnostics,
+		DistributedNodePenalty,
+		LocalNUMADistance,
+		ScoreOverridesPreference,
+		ScoreRoundingDecimals,
+		CapacityWeightedDistribution,
+		MaxHintsPerResource,
+		DisabledPolicies,
+		EnhancedObserveOnly,
+		DistributedSingleResourceSpread,
+		SocketCrossingPenalty,
+		PreferPodSiblingNodes,
+		ScoreHysteresis,
+		NonePolicyShadowMetrics,
+		PolicyOverrideNamespaces,
+		LocalityClasses,
+		ExcludeInitContainers,
+		StrictHintValidation,
+		MemoryPressureFreeFraction,
+	)
+	betaOptions = sets.New[string](
 		MaxAllowableNUMANodes,
 	)
 	stableOptions = sets.New[string](
@@ -60,6 +100,165 @@ func CheckPolicyOptionAvailable(option string) error {
 type PolicyOptions struct {
 	PreferClosestNUMA     bool
 	MaxAllowableNUMANodes int
+	// MergeDiagnostics enables recording of per-candidate discard reasons
+	// during enhanced hint merging, for debugging tools. Diagnostic-only.
+	MergeDiagnostics bool
+	// DistributedNodePenalty is the score cost the distributed policy charges
+	// for every NUMA node a hint spans beyond the first. Lower values make
+	// wider spreads more attractive on tightly-coupled fabrics.
+	DistributedNodePenalty float64
+	// LocalNUMADistance is the distance scoring treats as local. The ACPI
+	// SLIT convention is 10, but some firmware reports a different local
+	// baseline.
+	LocalNUMADistance int
+	// ScoreOverridesPreference makes the enhanced merger rank candidate
+	// hints purely by their topology score, ignoring the Preferred flag, so
+	// a well-scoring non-preferred hint can beat a badly-scoring preferred
+	// one. Off by default to preserve the classic preference-first ordering.
+	ScoreOverridesPreference bool
+	// ScoreRoundingDecimals is the number of decimal places computed scores
+	// and derived bandwidths are rounded to, to stabilize comparisons and
+	// log output. Values below one (including the zero value and the
+	// negative default) disable rounding.
+	ScoreRoundingDecimals int
+	// CapacityWeightedDistribution makes the distributed policy weight each
+	// hint's interconnect metrics by the free aligned capacity of the NUMA
+	// nodes it names, so spreads place proportionally more load where there
+	// is room. Off by default, which keeps uniform averaging.
+	CapacityWeightedDistribution bool
+	// MaxHintsPerResource caps how many hints a single provider may
+	// contribute for one resource before the excess is truncated, bounding
+	// the merger's worst-case cost against misbehaving providers.
+	MaxHintsPerResource int
+	// DisabledPolicies lists policy names NewManager must refuse to
+	// construct, so operators can forbid a policy independently of the
+	// feature gates. Empty by default, which allows every valid policy.
+	DisabledPolicies sets.Set[string]
+	// EnhancedObserveOnly makes merging compute and report the enhanced
+	// result for observability while the committed affinity still comes from
+	// the basic merger, so canary nodes can gather enhanced scoring data
+	// without changing admission behavior.
+	EnhancedObserveOnly bool
+	// DistributedSingleResourceSpread makes the distributed policy spread
+	// even a lone resource type across its capable NUMA nodes instead of
+	// falling back to standard merging, e.g. to interleave memory pages.
+	// Off by default, which picks the best single placement.
+	DistributedSingleResourceSpread bool
+	// SocketCrossingPenalty is the score cost added when a merged affinity
+	// spans NUMA nodes on different sockets, since a cross-socket (UPI/QPI)
+	// hop is far more expensive than one between NUMA nodes within a socket.
+	// Zero, the default, scores socket crossings like any other hop.
+	SocketCrossingPenalty float64
+	// PreferPodSiblingNodes biases container-scope merging toward the NUMA
+	// nodes already committed to earlier containers of the same pod, so a
+	// multi-container pod is co-located when resources allow instead of
+	// scattering across nodes. Containers that cannot fit on the siblings'
+	// nodes fall back to the unbiased merge. Off by default.
+	PreferPodSiblingNodes bool
+	// ScoreHysteresis is the score improvement a freshly merged hint must
+	// deliver before a re-admitted container is moved away from its stored
+	// affinity, so marginal score differences do not cause placement churn.
+	// Zero, the default, always commits the freshly merged hint.
+	ScoreHysteresis float64
+	// NonePolicyShadowMetrics makes the none policy compute, without
+	// enforcing, what an enhanced best-effort merge would have chosen for
+	// each admitted container, recording the would-be score and preference
+	// as metrics. It lets operators quantify the benefit of topology
+	// management before enabling an enforcing policy. Off by default.
+	NonePolicyShadowMetrics bool
+	// PolicyOverrideNamespaces lists the namespaces whose pods may request a
+	// different topology policy through the policy annotation, e.g. a
+	// latency-critical tenant using single-numa-node on a best-effort node.
+	// Empty by default, which ignores the annotation everywhere.
+	PolicyOverrideNamespaces sets.Set[string]
+	// LocalityClasses maps resource names to a named locality class. Resources
+	// sharing a class are placed together during merging regardless of type,
+	// e.g. a GPU and the NIC bonded to it, by filtering hint masks that would
+	// separate them. Empty by default, which places resources independently.
+	LocalityClasses map[string]string
+	// ExcludeInitContainers keeps the placements committed for regular init
+	// containers from influencing the placement of the containers that run
+	// after them, e.g. through the prefer-pod-sibling-nodes bias, since init
+	// resources are usually released before the app containers start.
+	// Restartable init containers keep running alongside the app containers
+	// and are never excluded. Off by default.
+	ExcludeInitContainers bool
+	// StrictHintValidation makes merging treat a resource whose provider
+	// returned a malformed enhanced hint — a negative hop count, an
+	// out-of-range distance, or a non-finite bandwidth or score — as
+	// unsatisfiable, so the pod is rejected instead of placed on corrupt
+	// data. Off by default, which keeps the lenient behavior of dropping
+	// the malformed hints and merging the rest.
+	StrictHintValidation bool
+	// MemoryPressureFreeFraction is the free/allocatable ratio below which
+	// the memory manager considers a NUMA node under memory pressure and
+	// penalizes it in enhanced hint scoring, steering new workloads toward
+	// nodes with more headroom. Must be between 0 and 1; 0, the default,
+	// disables the penalty.
+	MemoryPressureFreeFraction float64
+}
+
+// normalizePolicyOptions trims surrounding whitespace from option names and
+// values and rejects option names that differ only in case, which map
+// iteration order would otherwise resolve arbitrarily. Option names stay
+// case-sensitive; a wrongly-cased name is still an unknown option.
+func normalizePolicyOptions(policyOptions map[string]string) (map[string]string, error) {
+	normalized := make(map[string]string, len(policyOptions))
+	byLowerName := make(map[string]string, len(policyOptions))
+	for name, value := range policyOptions {
+		name = strings.TrimSpace(name)
+		if previous, ok := byLowerName[strings.ToLower(name)]; ok {
+			return nil, fmt.Errorf("topology manager policy options %q and %q differ only in case", previous, name)
+		}
+		byLowerName[strings.ToLower(name)] = name
+		normalized[name] = strings.TrimSpace(value)
+	}
+	return normalized, nil
+}
+
+// The parse*Option helpers below are the single place each option kind is
+// validated; every case in NewPolicyOptions goes through one of them, so a
+// new option of an existing kind cannot forget its bounds check.
+
+func parseBoolOption(name, value string) (bool, error) {
+	optValue, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("bad value for option %q: %w", name, err)
+	}
+	return optValue, nil
+}
+
+func parseIntOption(name, value string, minimum int) (int, error) {
+	optValue, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("unable to convert policy option to integer %q: %w", name, err)
+	}
+	if optValue < minimum {
+		return 0, fmt.Errorf("the value of %q must be at least %d", name, minimum)
+	}
+	return optValue, nil
+}
+
+func parseNonNegativeIntOption(name, value string) (int, error) {
+	optValue, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("unable to convert policy option to integer %q: %w", name, err)
+	}
+	if optValue < 0 {
+		return 0, fmt.Errorf("the value of %q must not be negative", name)
+	}
+	return optValue, nil
+}
+
+func parseNonNegativeFloatOption(name, value string) (float64, error) {
+	optValue, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("bad value for option %q: %w", name, err)
+	}
+	if optValue < 0 {
+		return 0, fmt.Errorf("the value of %q must not be negative", name)
+	}
+	return optValue, nil
 }
 
 func NewPolicyOptions(policyOptions map[string]string) (PolicyOptions, error) {
@@ -67,6 +266,23 @@ func NewPolicyOptions(policyOptions map[string]string) (PolicyOptions, error) {
 		// Set MaxAllowableNUMANodes to the default. This will be overwritten
 		// if the user has specified a policy option for MaxAllowableNUMANodes.
 		MaxAllowableNUMANodes: defaultMaxAllowableNUMANodes,
+		// Set DistributedNodePenalty to the default. This will be overwritten
+		// if the user has specified a policy option for DistributedNodePenalty.
+		DistributedNodePenalty: defaultDistributedNodePenalty,
+		// Set LocalNUMADistance to the default. This will be overwritten
+		// if the user has specified a policy option for LocalNUMADistance.
+		LocalNUMADistance: DefaultLocalDistance,
+		// Set ScoreRoundingDecimals to the default. This will be overwritten
+		// if the user has specified a policy option for ScoreRoundingDecimals.
+		ScoreRoundingDecimals: defaultScoreRoundingDecimals,
+		// Set MaxHintsPerResource to the default. This will be overwritten
+		// if the user has specified a policy option for MaxHintsPerResource.
+		MaxHintsPerResource: defaultMaxHintsPerResource,
+	}
+
+	policyOptions, err := normalizePolicyOptions(policyOptions)
+	if err != nil {
+		return opts, err
 	}
 
 	for name, value := range policyOptions {
@@ -76,25 +292,150 @@ func NewPolicyOptions(policyOptions map[string]string) (PolicyOptions, error) {
 
 		switch name {
 		case PreferClosestNUMANodes:
-			optValue, err := strconv.ParseBool(value)
+			optValue, err := parseBoolOption(name, value)
 			if err != nil {
-				return opts, fmt.Errorf("bad value for option %q: %w", name, err)
+				return opts, err
 			}
 			opts.PreferClosestNUMA = optValue
 		case MaxAllowableNUMANodes:
-			optValue, err := strconv.Atoi(value)
+			optValue, err := parseIntOption(name, value, 1)
 			if err != nil {
-				return opts, fmt.Errorf("unable to convert policy option to integer %q: %w", name, err)
+				return opts, err
 			}
-
-			if optValue < defaultMaxAllowableNUMANodes {
-				return opts, fmt.Errorf("the minimum value of %q should not be less than %v", name, defaultMaxAllowableNUMANodes)
-			}
-
 			if optValue > defaultMaxAllowableNUMANodes {
 				klog.InfoS("WARNING: the value of max-allowable-numa-nodes is more than the default recommended value", "max-allowable-numa-nodes", optValue, "defaultMaxAllowableNUMANodes", defaultMaxAllowableNUMANodes)
 			}
 			opts.MaxAllowableNUMANodes = optValue
+		case MergeDiagnostics:
+			optValue, err := parseBoolOption(name, value)
+			if err != nil {
+				return opts, err
+			}
+			opts.MergeDiagnostics = optValue
+		case DistributedNodePenalty:
+			optValue, err := parseNonNegativeFloatOption(name, value)
+			if err != nil {
+				return opts, err
+			}
+			opts.DistributedNodePenalty = optValue
+		case LocalNUMADistance:
+			optValue, err := parseIntOption(name, value, 1)
+			if err != nil {
+				return opts, err
+			}
+			opts.LocalNUMADistance = optValue
+		case ScoreOverridesPreference:
+			optValue, err := parseBoolOption(name, value)
+			if err != nil {
+				return opts, err
+			}
+			opts.ScoreOverridesPreference = optValue
+		case ScoreRoundingDecimals:
+			optValue, err := parseNonNegativeIntOption(name, value)
+			if err != nil {
+				return opts, err
+			}
+			opts.ScoreRoundingDecimals = optValue
+		case CapacityWeightedDistribution:
+			optValue, err := parseBoolOption(name, value)
+			if err != nil {
+				return opts, err
+			}
+			opts.CapacityWeightedDistribution = optValue
+		case MaxHintsPerResource:
+			optValue, err := parseIntOption(name, value, 1)
+			if err != nil {
+				return opts, err
+			}
+			opts.MaxHintsPerResource = optValue
+		case EnhancedObserveOnly:
+			optValue, err := parseBoolOption(name, value)
+			if err != nil {
+				return opts, err
+			}
+			opts.EnhancedObserveOnly = optValue
+		case DistributedSingleResourceSpread:
+			optValue, err := parseBoolOption(name, value)
+			if err != nil {
+				return opts, err
+			}
+			opts.DistributedSingleResourceSpread = optValue
+		case SocketCrossingPenalty:
+			optValue, err := parseNonNegativeFloatOption(name, value)
+			if err != nil {
+				return opts, err
+			}
+			opts.SocketCrossingPenalty = optValue
+		case ScoreHysteresis:
+			optValue, err := parseNonNegativeFloatOption(name, value)
+			if err != nil {
+				return opts, err
+			}
+			opts.ScoreHysteresis = optValue
+		case PreferPodSiblingNodes:
+			optValue, err := parseBoolOption(name, value)
+			if err != nil {
+				return opts, err
+			}
+			opts.PreferPodSiblingNodes = optValue
+		case NonePolicyShadowMetrics:
+			optValue, err := parseBoolOption(name, value)
+			if err != nil {
+				return opts, err
+			}
+			opts.NonePolicyShadowMetrics = optValue
+		case ExcludeInitContainers:
+			optValue, err := parseBoolOption(name, value)
+			if err != nil {
+				return opts, err
+			}
+			opts.ExcludeInitContainers = optValue
+		case StrictHintValidation:
+			optValue, err := parseBoolOption(name, value)
+			if err != nil {
+				return opts, err
+			}
+			opts.StrictHintValidation = optValue
+		case MemoryPressureFreeFraction:
+			optValue, err := parseNonNegativeFloatOption(name, value)
+			if err != nil {
+				return opts, err
+			}
+			if optValue > 1 {
+				return opts, fmt.Errorf("the value of %q must be at most 1", name)
+			}
+			opts.MemoryPressureFreeFraction = optValue
+		case PolicyOverrideNamespaces:
+			namespaces := sets.New[string]()
+			for _, namespace := range strings.Split(value, ",") {
+				namespace = strings.TrimSpace(namespace)
+				if namespace == "" {
+					return opts, fmt.Errorf("bad value for option %q: empty namespace", name)
+				}
+				namespaces.Insert(namespace)
+			}
+			opts.PolicyOverrideNamespaces = namespaces
+		case LocalityClasses:
+			classes := make(map[string]string)
+			for _, entry := range strings.Split(value, ",") {
+				entry = strings.TrimSpace(entry)
+				resource, class, found := strings.Cut(entry, "=")
+				if !found || resource == "" || class == "" {
+					return opts, fmt.Errorf("bad value for option %q: entry %q is not of the form resource=class", name, entry)
+				}
+				classes[resource] = class
+			}
+			opts.LocalityClasses = classes
+		case DisabledPolicies:
+			disabled := sets.New[string]()
+			for _, policy := range strings.Split(value, ",") {
+				policy = strings.TrimSpace(policy)
+				if !IsValidPolicy(policy) {
+					return opts, fmt.Errorf("bad value for option %q: unknown policy %q", name, policy)
+				}
+				disabled.Insert(policy)
+			}
+			opts.DisabledPolicies = disabled
 		default:
 			// this should never be reached, we already detect unknown options,
 			// but we keep it as further safety.
@@ -18,9 +18,11 @@ package topologymanager
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 
+	"k8s.io/apimachinery/pkg/util/sets"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/component-base/featuregate"
 	featuregatetesting "k8s.io/component-base/featuregate/testing"
@@ -49,8 +51,12 @@ func TestNewTopologyManagerOptions(t *testing.T) {
 		{
 			description: "return TopologyManagerOptions with PreferClosestNUMA set to true",
 			expectedOptions: PolicyOptions{
-				PreferClosestNUMA:     true,
-				MaxAllowableNUMANodes: 8,
+				PreferClosestNUMA:      true,
+				MaxAllowableNUMANodes:  8,
+				DistributedNodePenalty: defaultDistributedNodePenalty,
+				LocalNUMADistance:      DefaultLocalDistance,
+				ScoreRoundingDecimals:  defaultScoreRoundingDecimals,
+				MaxHintsPerResource:    defaultMaxHintsPerResource,
 			},
 			policyOptions: map[string]string{
 				PreferClosestNUMANodes: "true",
@@ -62,12 +68,40 @@ func TestNewTopologyManagerOptions(t *testing.T) {
 			featureGate:       pkgfeatures.TopologyManagerPolicyBetaOptions,
 			featureGateEnable: true,
 			expectedOptions: PolicyOptions{
-				MaxAllowableNUMANodes: 12,
+				MaxAllowableNUMANodes:  12,
+				DistributedNodePenalty: defaultDistributedNodePenalty,
+				LocalNUMADistance:      DefaultLocalDistance,
+				ScoreRoundingDecimals:  defaultScoreRoundingDecimals,
+				MaxHintsPerResource:    defaultMaxHintsPerResource,
 			},
 			policyOptions: map[string]string{
 				MaxAllowableNUMANodes: "12",
 			},
 		},
+		{
+			description:       "return TopologyManagerOptions with MaxAllowableNUMANodes lowered to 4",
+			featureGate:       pkgfeatures.TopologyManagerPolicyBetaOptions,
+			featureGateEnable: true,
+			expectedOptions: PolicyOptions{
+				MaxAllowableNUMANodes:  4,
+				DistributedNodePenalty: defaultDistributedNodePenalty,
+				LocalNUMADistance:      DefaultLocalDistance,
+				ScoreRoundingDecimals:  defaultScoreRoundingDecimals,
+				MaxHintsPerResource:    defaultMaxHintsPerResource,
+			},
+			policyOptions: map[string]string{
+				MaxAllowableNUMANodes: "4",
+			},
+		},
+		{
+			description:       "fail on MaxAllowableNUMANodes below 1",
+			featureGate:       pkgfeatures.TopologyManagerPolicyBetaOptions,
+			featureGateEnable: true,
+			policyOptions: map[string]string{
+				MaxAllowableNUMANodes: "0",
+			},
+			expectedErr: fmt.Errorf("must be at least 1"),
+		},
 		{
 			description: "fail to set option when TopologyManagerPolicyBetaOptions feature gate is not set",
 			featureGate: pkgfeatures.TopologyManagerPolicyBetaOptions,
@@ -79,7 +113,11 @@ func TestNewTopologyManagerOptions(t *testing.T) {
 		{
 			description: "return empty TopologyManagerOptions",
 			expectedOptions: PolicyOptions{
-				MaxAllowableNUMANodes: 8,
+				MaxAllowableNUMANodes:  8,
+				DistributedNodePenalty: defaultDistributedNodePenalty,
+				LocalNUMADistance:      DefaultLocalDistance,
+				ScoreRoundingDecimals:  defaultScoreRoundingDecimals,
+				MaxHintsPerResource:    defaultMaxHintsPerResource,
 			},
 		},
 		{
@@ -107,8 +145,12 @@ func TestNewTopologyManagerOptions(t *testing.T) {
 				fancyBetaOption: "true",
 			},
 			expectedOptions: PolicyOptions{
-				PreferClosestNUMA:     false,
-				MaxAllowableNUMANodes: 8,
+				PreferClosestNUMA:      false,
+				MaxAllowableNUMANodes:  8,
+				DistributedNodePenalty: defaultDistributedNodePenalty,
+				LocalNUMADistance:      DefaultLocalDistance,
+				ScoreRoundingDecimals:  defaultScoreRoundingDecimals,
+				MaxHintsPerResource:    defaultMaxHintsPerResource,
 			},
 		},
 		{
@@ -127,9 +169,493 @@ func TestNewTopologyManagerOptions(t *testing.T) {
 				fancyAlphaOption: "true",
 			},
 			expectedOptions: PolicyOptions{
-				PreferClosestNUMA:     false,
-				MaxAllowableNUMANodes: 8,
+				PreferClosestNUMA:      false,
+				MaxAllowableNUMANodes:  8,
+				DistributedNodePenalty: defaultDistributedNodePenalty,
+				LocalNUMADistance:      DefaultLocalDistance,
+				ScoreRoundingDecimals:  defaultScoreRoundingDecimals,
+				MaxHintsPerResource:    defaultMaxHintsPerResource,
+			},
+		},
+		{
+			description:       "return TopologyManagerOptions with DistributedNodePenalty set to 2.5",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			expectedOptions: PolicyOptions{
+				MaxAllowableNUMANodes:  8,
+				DistributedNodePenalty: 2.5,
+				LocalNUMADistance:      DefaultLocalDistance,
+				ScoreRoundingDecimals:  defaultScoreRoundingDecimals,
+				MaxHintsPerResource:    defaultMaxHintsPerResource,
+			},
+			policyOptions: map[string]string{
+				DistributedNodePenalty: "2.5",
+			},
+		},
+		{
+			description:       "fail to parse options with error DistributedNodePenalty",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			policyOptions: map[string]string{
+				DistributedNodePenalty: "not a float",
+			},
+			expectedErr: fmt.Errorf("bad value for option"),
+		},
+		{
+			description:       "return TopologyManagerOptions with LocalNUMADistance set to 20",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			expectedOptions: PolicyOptions{
+				MaxAllowableNUMANodes:  8,
+				DistributedNodePenalty: defaultDistributedNodePenalty,
+				LocalNUMADistance:      20,
+				ScoreRoundingDecimals:  defaultScoreRoundingDecimals,
+				MaxHintsPerResource:    defaultMaxHintsPerResource,
+			},
+			policyOptions: map[string]string{
+				LocalNUMADistance: "20",
+			},
+		},
+		{
+			description:       "return TopologyManagerOptions with ScoreRoundingDecimals set to 2",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			expectedOptions: PolicyOptions{
+				MaxAllowableNUMANodes:  8,
+				DistributedNodePenalty: defaultDistributedNodePenalty,
+				LocalNUMADistance:      DefaultLocalDistance,
+				ScoreRoundingDecimals:  2,
+				MaxHintsPerResource:    defaultMaxHintsPerResource,
+			},
+			policyOptions: map[string]string{
+				ScoreRoundingDecimals: "2",
+			},
+		},
+		{
+			description:       "return TopologyManagerOptions with CapacityWeightedDistribution enabled",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			expectedOptions: PolicyOptions{
+				MaxAllowableNUMANodes:        8,
+				DistributedNodePenalty:       defaultDistributedNodePenalty,
+				LocalNUMADistance:            DefaultLocalDistance,
+				ScoreRoundingDecimals:        defaultScoreRoundingDecimals,
+				MaxHintsPerResource:          defaultMaxHintsPerResource,
+				CapacityWeightedDistribution: true,
+			},
+			policyOptions: map[string]string{
+				CapacityWeightedDistribution: "true",
+			},
+		},
+		{
+			description:       "fail to parse options with error CapacityWeightedDistribution",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			policyOptions: map[string]string{
+				CapacityWeightedDistribution: "not a bool",
+			},
+			expectedErr: fmt.Errorf("bad value for option"),
+		},
+		{
+			description:       "return TopologyManagerOptions with MaxHintsPerResource set to 16",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			expectedOptions: PolicyOptions{
+				MaxAllowableNUMANodes:  8,
+				DistributedNodePenalty: defaultDistributedNodePenalty,
+				LocalNUMADistance:      DefaultLocalDistance,
+				ScoreRoundingDecimals:  defaultScoreRoundingDecimals,
+				MaxHintsPerResource:    16,
+			},
+			policyOptions: map[string]string{
+				MaxHintsPerResource: "16",
+			},
+		},
+		{
+			description:       "fail on MaxHintsPerResource below 1",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			policyOptions: map[string]string{
+				MaxHintsPerResource: "0",
+			},
+			expectedErr: fmt.Errorf("must be at least 1"),
+		},
+		{
+			description:       "return TopologyManagerOptions with EnhancedObserveOnly enabled",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			expectedOptions: PolicyOptions{
+				MaxAllowableNUMANodes:  8,
+				DistributedNodePenalty: defaultDistributedNodePenalty,
+				LocalNUMADistance:      DefaultLocalDistance,
+				ScoreRoundingDecimals:  defaultScoreRoundingDecimals,
+				MaxHintsPerResource:    defaultMaxHintsPerResource,
+				EnhancedObserveOnly:    true,
+			},
+			policyOptions: map[string]string{
+				EnhancedObserveOnly: "true",
+			},
+		},
+		{
+			description:       "fail to parse options with error EnhancedObserveOnly",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			policyOptions: map[string]string{
+				EnhancedObserveOnly: "not a bool",
+			},
+			expectedErr: fmt.Errorf("bad value for option"),
+		},
+		{
+			description:       "return TopologyManagerOptions with DistributedSingleResourceSpread enabled",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			expectedOptions: PolicyOptions{
+				MaxAllowableNUMANodes:           8,
+				DistributedNodePenalty:          defaultDistributedNodePenalty,
+				LocalNUMADistance:               DefaultLocalDistance,
+				ScoreRoundingDecimals:           defaultScoreRoundingDecimals,
+				MaxHintsPerResource:             defaultMaxHintsPerResource,
+				DistributedSingleResourceSpread: true,
+			},
+			policyOptions: map[string]string{
+				DistributedSingleResourceSpread: "true",
+			},
+		},
+		{
+			description:       "fail to parse options with error DistributedSingleResourceSpread",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			policyOptions: map[string]string{
+				DistributedSingleResourceSpread: "not a bool",
+			},
+			expectedErr: fmt.Errorf("bad value for option"),
+		},
+		{
+			description:       "return TopologyManagerOptions with SocketCrossingPenalty set",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			expectedOptions: PolicyOptions{
+				MaxAllowableNUMANodes:  8,
+				DistributedNodePenalty: defaultDistributedNodePenalty,
+				LocalNUMADistance:      DefaultLocalDistance,
+				ScoreRoundingDecimals:  defaultScoreRoundingDecimals,
+				MaxHintsPerResource:    defaultMaxHintsPerResource,
+				SocketCrossingPenalty:  25.5,
+			},
+			policyOptions: map[string]string{
+				SocketCrossingPenalty: "25.5",
+			},
+		},
+		{
+			description:       "fail on negative SocketCrossingPenalty",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			policyOptions: map[string]string{
+				SocketCrossingPenalty: "-1",
+			},
+			expectedErr: fmt.Errorf("must not be negative"),
+		},
+		{
+			description:       "return TopologyManagerOptions with PreferPodSiblingNodes enabled",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			expectedOptions: PolicyOptions{
+				MaxAllowableNUMANodes:  8,
+				DistributedNodePenalty: defaultDistributedNodePenalty,
+				LocalNUMADistance:      DefaultLocalDistance,
+				ScoreRoundingDecimals:  defaultScoreRoundingDecimals,
+				MaxHintsPerResource:    defaultMaxHintsPerResource,
+				PreferPodSiblingNodes:  true,
+			},
+			policyOptions: map[string]string{
+				PreferPodSiblingNodes: "true",
+			},
+		},
+		{
+			description:       "fail to parse options with error PreferPodSiblingNodes",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			policyOptions: map[string]string{
+				PreferPodSiblingNodes: "not a bool",
+			},
+			expectedErr: fmt.Errorf("bad value for option"),
+		},
+		{
+			description:       "return TopologyManagerOptions with ScoreHysteresis set",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			expectedOptions: PolicyOptions{
+				MaxAllowableNUMANodes:  8,
+				DistributedNodePenalty: defaultDistributedNodePenalty,
+				LocalNUMADistance:      DefaultLocalDistance,
+				ScoreRoundingDecimals:  defaultScoreRoundingDecimals,
+				MaxHintsPerResource:    defaultMaxHintsPerResource,
+				ScoreHysteresis:        2.5,
+			},
+			policyOptions: map[string]string{
+				ScoreHysteresis: "2.5",
+			},
+		},
+		{
+			description:       "fail on negative ScoreHysteresis",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			policyOptions: map[string]string{
+				ScoreHysteresis: "-1",
+			},
+			expectedErr: fmt.Errorf("must not be negative"),
+		},
+		{
+			description:       "return TopologyManagerOptions with NonePolicyShadowMetrics set",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			expectedOptions: PolicyOptions{
+				MaxAllowableNUMANodes:   8,
+				DistributedNodePenalty:  defaultDistributedNodePenalty,
+				LocalNUMADistance:       DefaultLocalDistance,
+				ScoreRoundingDecimals:   defaultScoreRoundingDecimals,
+				MaxHintsPerResource:     defaultMaxHintsPerResource,
+				NonePolicyShadowMetrics: true,
+			},
+			policyOptions: map[string]string{
+				NonePolicyShadowMetrics: "true",
+			},
+		},
+		{
+			description:       "fail to parse options with error NonePolicyShadowMetrics",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			policyOptions: map[string]string{
+				NonePolicyShadowMetrics: "not a bool",
+			},
+			expectedErr: fmt.Errorf("bad value for option"),
+		},
+		{
+			description:       "return TopologyManagerOptions with PolicyOverrideNamespaces set",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			expectedOptions: PolicyOptions{
+				MaxAllowableNUMANodes:    8,
+				DistributedNodePenalty:   defaultDistributedNodePenalty,
+				LocalNUMADistance:        DefaultLocalDistance,
+				ScoreRoundingDecimals:    defaultScoreRoundingDecimals,
+				MaxHintsPerResource:      defaultMaxHintsPerResource,
+				PolicyOverrideNamespaces: sets.New[string]("trusted", "latency-critical"),
+			},
+			policyOptions: map[string]string{
+				PolicyOverrideNamespaces: "trusted, latency-critical",
+			},
+		},
+		{
+			description:       "fail on an empty namespace in PolicyOverrideNamespaces",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			policyOptions: map[string]string{
+				PolicyOverrideNamespaces: "trusted,,other",
+			},
+			expectedErr: fmt.Errorf("bad value for option"),
+		},
+		{
+			description:       "return TopologyManagerOptions with LocalityClasses set",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			expectedOptions: PolicyOptions{
+				MaxAllowableNUMANodes:  8,
+				DistributedNodePenalty: defaultDistributedNodePenalty,
+				LocalNUMADistance:      DefaultLocalDistance,
+				ScoreRoundingDecimals:  defaultScoreRoundingDecimals,
+				MaxHintsPerResource:    defaultMaxHintsPerResource,
+				LocalityClasses: map[string]string{
+					"example.com/gpu": "bonded",
+					"example.com/nic": "bonded",
+				},
+			},
+			policyOptions: map[string]string{
+				LocalityClasses: "example.com/gpu=bonded, example.com/nic=bonded",
+			},
+		},
+		{
+			description:       "fail on a LocalityClasses entry without a class",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			policyOptions: map[string]string{
+				LocalityClasses: "example.com/gpu=bonded,example.com/nic",
+			},
+			expectedErr: fmt.Errorf("bad value for option"),
+		},
+		{
+			description:       "return TopologyManagerOptions with ExcludeInitContainers enabled",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			expectedOptions: PolicyOptions{
+				MaxAllowableNUMANodes:  8,
+				DistributedNodePenalty: defaultDistributedNodePenalty,
+				LocalNUMADistance:      DefaultLocalDistance,
+				ScoreRoundingDecimals:  defaultScoreRoundingDecimals,
+				MaxHintsPerResource:    defaultMaxHintsPerResource,
+				ExcludeInitContainers:  true,
+			},
+			policyOptions: map[string]string{
+				ExcludeInitContainers: "true",
+			},
+		},
+		{
+			description:       "fail to parse options with error ExcludeInitContainers",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			policyOptions: map[string]string{
+				ExcludeInitContainers: "not a bool",
+			},
+			expectedErr: fmt.Errorf("bad value for option"),
+		},
+		{
+			description:       "return TopologyManagerOptions with StrictHintValidation enabled",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			expectedOptions: PolicyOptions{
+				MaxAllowableNUMANodes:  8,
+				DistributedNodePenalty: defaultDistributedNodePenalty,
+				LocalNUMADistance:      DefaultLocalDistance,
+				ScoreRoundingDecimals:  defaultScoreRoundingDecimals,
+				MaxHintsPerResource:    defaultMaxHintsPerResource,
+				StrictHintValidation:   true,
+			},
+			policyOptions: map[string]string{
+				StrictHintValidation: "true",
+			},
+		},
+		{
+			description:       "fail to parse options with error StrictHintValidation",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			policyOptions: map[string]string{
+				StrictHintValidation: "not a bool",
+			},
+			expectedErr: fmt.Errorf("bad value for option"),
+		},
+		{
+			description:       "return TopologyManagerOptions with MemoryPressureFreeFraction set",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			expectedOptions: PolicyOptions{
+				MaxAllowableNUMANodes:      8,
+				DistributedNodePenalty:     defaultDistributedNodePenalty,
+				LocalNUMADistance:          DefaultLocalDistance,
+				ScoreRoundingDecimals:      defaultScoreRoundingDecimals,
+				MaxHintsPerResource:        defaultMaxHintsPerResource,
+				MemoryPressureFreeFraction: 0.2,
+			},
+			policyOptions: map[string]string{
+				MemoryPressureFreeFraction: "0.2",
+			},
+		},
+		{
+			description:       "fail on a MemoryPressureFreeFraction above 1",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			policyOptions: map[string]string{
+				MemoryPressureFreeFraction: "1.5",
+			},
+			expectedErr: fmt.Errorf("must be at most 1"),
+		},
+		{
+			description: "trim whitespace around option names and values",
+			expectedOptions: PolicyOptions{
+				PreferClosestNUMA:      true,
+				MaxAllowableNUMANodes:  8,
+				DistributedNodePenalty: defaultDistributedNodePenalty,
+				LocalNUMADistance:      DefaultLocalDistance,
+				ScoreRoundingDecimals:  defaultScoreRoundingDecimals,
+				MaxHintsPerResource:    defaultMaxHintsPerResource,
+			},
+			policyOptions: map[string]string{
+				" " + PreferClosestNUMANodes + " ": " true ",
+			},
+		},
+		{
+			description: "fail on option names differing only in case",
+			policyOptions: map[string]string{
+				PreferClosestNUMANodes:      "true",
+				"Prefer-Closest-NUMA-Nodes": "false",
+			},
+			expectedErr: fmt.Errorf("differ only in case"),
+		},
+		{
+			description: "fail on an unknown option",
+			policyOptions: map[string]string{
+				"no-such-option": "true",
+			},
+			expectedErr: fmt.Errorf("unknown Topology Manager Policy option"),
+		},
+		{
+			description:       "return TopologyManagerOptions with the distributed policy disabled",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			expectedOptions: PolicyOptions{
+				MaxAllowableNUMANodes:  8,
+				DistributedNodePenalty: defaultDistributedNodePenalty,
+				LocalNUMADistance:      DefaultLocalDistance,
+				ScoreRoundingDecimals:  defaultScoreRoundingDecimals,
+				MaxHintsPerResource:    defaultMaxHintsPerResource,
+				DisabledPolicies:       sets.New[string](PolicyDistributed, PolicySingleNumaNode),
+			},
+			policyOptions: map[string]string{
+				DisabledPolicies: "distributed, single-numa-node",
+			},
+		},
+		{
+			description:       "fail on an unknown policy name in DisabledPolicies",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			policyOptions: map[string]string{
+				DisabledPolicies: "no-such-policy",
+			},
+			expectedErr: fmt.Errorf("unknown policy"),
+		},
+		{
+			description:       "fail on negative ScoreRoundingDecimals",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			policyOptions: map[string]string{
+				ScoreRoundingDecimals: "-1",
+			},
+			expectedErr: fmt.Errorf("must not be negative"),
+		},
+		{
+			description:       "fail to parse options with error ScoreRoundingDecimals",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			policyOptions: map[string]string{
+				ScoreRoundingDecimals: "not an int",
+			},
+			expectedErr: fmt.Errorf("unable to convert policy option to integer"),
+		},
+		{
+			description:       "fail on LocalNUMADistance below 1",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			policyOptions: map[string]string{
+				LocalNUMADistance: "0",
+			},
+			expectedErr: fmt.Errorf("must be at least 1"),
+		},
+		{
+			description:       "fail to parse options with error LocalNUMADistance",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			policyOptions: map[string]string{
+				LocalNUMADistance: "not an int",
+			},
+			expectedErr: fmt.Errorf("unable to convert policy option to integer"),
+		},
+		{
+			description:       "fail on negative DistributedNodePenalty",
+			featureGate:       pkgfeatures.TopologyManagerPolicyAlphaOptions,
+			featureGateEnable: true,
+			policyOptions: map[string]string{
+				DistributedNodePenalty: "-1",
 			},
+			expectedErr: fmt.Errorf("must not be negative"),
 		},
 		{
 			description: "test alpha options fail",
@@ -156,7 +682,7 @@ func TestNewTopologyManagerOptions(t *testing.T) {
 				return
 			}
 
-			if opts != tcase.expectedOptions {
+			if !reflect.DeepEqual(opts, tcase.expectedOptions) {
 				t.Errorf("Expected TopologyManagerOptions to equal %v, not %v", tcase.expectedOptions, opts)
 			}
 		})
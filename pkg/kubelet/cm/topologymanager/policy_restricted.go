@@ -21,6 +21,7 @@ type restrictedPolicy struct {
 }
 
 var _ Policy = &restrictedPolicy{}
+var _ TopNMergePolicy = &restrictedPolicy{}
 
 // PolicyRestricted policy name.
 const PolicyRestricted string = "restricted"
@@ -39,9 +40,24 @@ func (p *restrictedPolicy) canAdmitPodResult(hint *TopologyHint) bool {
 }
 
 func (p *restrictedPolicy) Merge(providersHints []map[string][]TopologyHint) (TopologyHint, bool) {
-	filteredHints := filterProvidersHints(providersHints)
-	merger := NewHintMerger(p.numaInfo, filteredHints, p.Name(), p.opts)
-	bestHint := merger.Merge()
+	// No provider supplied any hints at all; the default affinity spanning
+	// the whole machine is preferred, so restricted admits it.
+	if len(providersHints) == 0 {
+		return TopologyHint{NUMANodeAffinity: p.numaInfo.DefaultAffinityMask(), Preferred: true}, true
+	}
+
+	filteredHints := filterProvidersHints(providersHints, p.opts)
+	bestHint := mergeFilteredHints(p.numaInfo, filteredHints, p.Name(), p.opts)
 	admit := p.canAdmitPodResult(&bestHint)
 	return bestHint, admit
 }
+
+// MergeTopN returns up to n distinct candidate placements ranked by the
+// enhanced comparator, best first. Restricted only admits preferred
+// placements, so non-preferred candidates are filtered out.
+func (p *restrictedPolicy) MergeTopN(providersHints []map[string][]TopologyHint, n int) []MergeResult {
+	if len(providersHints) == 0 {
+		return nil
+	}
+	return mergeTopNCandidates(p.numaInfo, filterProvidersHints(providersHints, p.opts), p.Name(), p.opts, n, p.canAdmitPodResult)
+}
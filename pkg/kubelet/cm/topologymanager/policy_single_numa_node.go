@@ -23,6 +23,7 @@ type singleNumaNodePolicy struct {
 }
 
 var _ Policy = &singleNumaNodePolicy{}
+var _ TopNMergePolicy = &singleNumaNodePolicy{}
 
 // PolicySingleNumaNode policy name.
 const PolicySingleNumaNode string = "single-numa-node"
@@ -36,6 +37,10 @@ func (p *singleNumaNodePolicy) Name() string {
 	return PolicySingleNumaNode
 }
 
+func (p *singleNumaNodePolicy) policyOptions() PolicyOptions {
+	return p.opts
+}
+
 func (p *singleNumaNodePolicy) canAdmitPodResult(hint *TopologyHint) bool {
 	return hint.Preferred
 }
@@ -58,18 +63,92 @@ func filterSingleNumaHints(allResourcesHints [][]TopologyHint) [][]TopologyHint
 	return filteredResourcesHints
 }
 
+// SingleNumaNodeRejectionReason classifies why the single-numa-node policy
+// rejected a pod.
+type SingleNumaNodeRejectionReason string
+
+const (
+	// RejectionReasonNoSingleNUMACandidates means at least one resource had
+	// no preferred single-node hint at all, so no placement could exist.
+	RejectionReasonNoSingleNUMACandidates SingleNumaNodeRejectionReason = "NoSingleNUMACandidates"
+	// RejectionReasonConflictingCandidates means every resource offered
+	// single-node candidates, but no common node satisfied all of them.
+	RejectionReasonConflictingCandidates SingleNumaNodeRejectionReason = "ConflictingCandidates"
+)
+
+// SingleNumaNodeMergeResult describes the outcome of a single-numa-node
+// merge: why an admission failed, and how many single-node candidate hints
+// were considered across all resources. The reason is empty when the pod was
+// admitted.
+type SingleNumaNodeMergeResult struct {
+	Reason         SingleNumaNodeRejectionReason
+	CandidateCount int
+}
+
 func (p *singleNumaNodePolicy) Merge(providersHints []map[string][]TopologyHint) (TopologyHint, bool) {
-	filteredHints := filterProvidersHints(providersHints)
+	bestHint, admit, _ := p.MergeWithResult(providersHints)
+	return bestHint, admit
+}
+
+// MergeWithResult merges like Merge, additionally reporting a structured
+// result so callers can distinguish a rejection with no viable single-node
+// candidates from one where candidates existed but conflicted.
+func (p *singleNumaNodePolicy) MergeWithResult(providersHints []map[string][]TopologyHint) (TopologyHint, bool, SingleNumaNodeMergeResult) {
+	var result SingleNumaNodeMergeResult
+
+	// No provider supplied any hints at all; there is nothing to pin to a
+	// single NUMA node, so admit with a don't-care affinity.
+	if len(providersHints) == 0 {
+		return TopologyHint{NUMANodeAffinity: nil, Preferred: true}, true, result
+	}
+
+	filteredHints := filterProvidersHints(providersHints, p.opts)
 	// Filter to only include don't cares and hints with a single NUMA node.
 	singleNumaHints := filterSingleNumaHints(filteredHints)
 
-	merger := NewHintMerger(p.numaInfo, singleNumaHints, p.Name(), p.opts)
-	bestHint := merger.Merge()
+	noCandidates := false
+	for _, resourceHints := range singleNumaHints {
+		if len(resourceHints) == 0 {
+			noCandidates = true
+		}
+		for _, hint := range resourceHints {
+			if hint.NUMANodeAffinity != nil {
+				result.CandidateCount++
+			}
+		}
+	}
+
+	bestHint := mergeFilteredHints(p.numaInfo, singleNumaHints, p.Name(), p.opts)
 
 	if bestHint.NUMANodeAffinity.IsEqual(p.numaInfo.DefaultAffinityMask()) {
-		bestHint = TopologyHint{nil, bestHint.Preferred}
+		bestHint = TopologyHint{NUMANodeAffinity: nil, Preferred: bestHint.Preferred}
 	}
 
 	admit := p.canAdmitPodResult(&bestHint)
-	return bestHint, admit
+	if !admit {
+		if noCandidates {
+			result.Reason = RejectionReasonNoSingleNUMACandidates
+		} else {
+			result.Reason = RejectionReasonConflictingCandidates
+		}
+	}
+	return bestHint, admit, result
+}
+
+// MergeTopN returns up to n distinct candidate placements ranked by the
+// enhanced comparator, best first. Only preferred single-node candidates are
+// admissible, and like Merge, a candidate spanning the whole machine is
+// reported as a don't-care affinity.
+func (p *singleNumaNodePolicy) MergeTopN(providersHints []map[string][]TopologyHint, n int) []MergeResult {
+	if len(providersHints) == 0 {
+		return nil
+	}
+	singleNumaHints := filterSingleNumaHints(filterProvidersHints(providersHints, p.opts))
+	results := mergeTopNCandidates(p.numaInfo, singleNumaHints, p.Name(), p.opts, n, p.canAdmitPodResult)
+	for i := range results {
+		if results[i].Hint.NUMANodeAffinity.IsEqual(p.numaInfo.DefaultAffinityMask()) {
+			results[i].Hint.NUMANodeAffinity = nil
+		}
+	}
+	return results
 }
@@ -29,7 +29,7 @@ func TestPolicySingleNumaNodeCanAdmitPodResult(t *testing.T) {
 	}{
 		{
 			name:     "Preferred is set to false in topology hints",
-			hint:     TopologyHint{nil, false},
+			hint:     TopologyHint{NUMANodeAffinity: nil, Preferred: false},
 			expected: false,
 		},
 	}
@@ -164,3 +164,79 @@ func TestPolicySingleNumaNodeMerge(t *testing.T) {
 
 	testPolicyMerge(&policy, tcases, t)
 }
+
+func TestPolicySingleNumaNodeMergeWithResult(t *testing.T) {
+	tcases := []struct {
+		name               string
+		providersHints     []map[string][]TopologyHint
+		expectedAdmit      bool
+		expectedReason     SingleNumaNodeRejectionReason
+		expectedCandidates int
+	}{
+		{
+			name: "no single-node candidates",
+			providersHints: []map[string][]TopologyHint{
+				{
+					"resource": {
+						{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: true},
+					},
+				},
+			},
+			expectedAdmit:      false,
+			expectedReason:     RejectionReasonNoSingleNUMACandidates,
+			expectedCandidates: 0,
+		},
+		{
+			name: "candidates exist but conflict",
+			providersHints: []map[string][]TopologyHint{
+				{
+					"resource1": {
+						{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+					},
+				},
+				{
+					"resource2": {
+						{NUMANodeAffinity: NewTestBitMask(1), Preferred: true},
+					},
+				},
+			},
+			expectedAdmit:      false,
+			expectedReason:     RejectionReasonConflictingCandidates,
+			expectedCandidates: 2,
+		},
+		{
+			name: "admitted with a common node",
+			providersHints: []map[string][]TopologyHint{
+				{
+					"resource1": {
+						{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+					},
+				},
+				{
+					"resource2": {
+						{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+					},
+				},
+			},
+			expectedAdmit:      true,
+			expectedReason:     "",
+			expectedCandidates: 2,
+		},
+	}
+
+	policy := &singleNumaNodePolicy{numaInfo: commonNUMAInfoTwoNodes(), opts: PolicyOptions{}}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			_, admit, result := policy.MergeWithResult(tcase.providersHints)
+			if admit != tcase.expectedAdmit {
+				t.Errorf("Expected admit to be %v, got %v", tcase.expectedAdmit, admit)
+			}
+			if result.Reason != tcase.expectedReason {
+				t.Errorf("Expected reason to be %q, got %q", tcase.expectedReason, result.Reason)
+			}
+			if result.CandidateCount != tcase.expectedCandidates {
+				t.Errorf("Expected %d candidates, got %d", tcase.expectedCandidates, result.CandidateCount)
+			}
+		})
+	}
+}
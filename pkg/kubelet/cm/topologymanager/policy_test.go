@@ -1299,7 +1299,7 @@ func TestMaxOfMinAffinityCounts(t *testing.T) {
 		{
 			[][]TopologyHint{
 				{
-					TopologyHint{NewTestBitMask(), true},
+					TopologyHint{NUMANodeAffinity: NewTestBitMask(), Preferred: true},
 				},
 			},
 			0,
@@ -1307,7 +1307,7 @@ func TestMaxOfMinAffinityCounts(t *testing.T) {
 		{
 			[][]TopologyHint{
 				{
-					TopologyHint{NewTestBitMask(0), true},
+					TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
 				},
 			},
 			1,
@@ -1315,7 +1315,7 @@ func TestMaxOfMinAffinityCounts(t *testing.T) {
 		{
 			[][]TopologyHint{
 				{
-					TopologyHint{NewTestBitMask(0, 1), true},
+					TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: true},
 				},
 			},
 			2,
@@ -1323,8 +1323,8 @@ func TestMaxOfMinAffinityCounts(t *testing.T) {
 		{
 			[][]TopologyHint{
 				{
-					TopologyHint{NewTestBitMask(0, 1), true},
-					TopologyHint{NewTestBitMask(0, 1, 2), true},
+					TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: true},
+					TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1, 2), Preferred: true},
 				},
 			},
 			2,
@@ -1332,11 +1332,11 @@ func TestMaxOfMinAffinityCounts(t *testing.T) {
 		{
 			[][]TopologyHint{
 				{
-					TopologyHint{NewTestBitMask(0, 1), true},
-					TopologyHint{NewTestBitMask(0, 1, 2), true},
+					TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: true},
+					TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1, 2), Preferred: true},
 				},
 				{
-					TopologyHint{NewTestBitMask(0, 1, 2), true},
+					TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1, 2), Preferred: true},
 				},
 			},
 			3,
@@ -1344,12 +1344,12 @@ func TestMaxOfMinAffinityCounts(t *testing.T) {
 		{
 			[][]TopologyHint{
 				{
-					TopologyHint{NewTestBitMask(0, 1), true},
-					TopologyHint{NewTestBitMask(0, 1, 2), true},
+					TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: true},
+					TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1, 2), Preferred: true},
 				},
 				{
-					TopologyHint{NewTestBitMask(0, 1, 2), true},
-					TopologyHint{NewTestBitMask(0, 1, 2, 3), true},
+					TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1, 2), Preferred: true},
+					TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1, 2, 3), Preferred: true},
 				},
 			},
 			3,
@@ -1378,175 +1378,175 @@ func TestCompareHintsNarrowest(t *testing.T) {
 			"candidate.NUMANodeAffinity.Count() == 0 (1)",
 			-1,
 			nil,
-			&TopologyHint{bitmask.NewEmptyBitMask(), false},
+			&TopologyHint{NUMANodeAffinity: bitmask.NewEmptyBitMask(), Preferred: false},
 			"current",
 		},
 		{
 			"candidate.NUMANodeAffinity.Count() == 0 (2)",
 			-1,
-			&TopologyHint{NewTestBitMask(), true},
-			&TopologyHint{NewTestBitMask(), false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(), Preferred: true},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(), Preferred: false},
 			"current",
 		},
 		{
 			"current == nil (1)",
 			-1,
 			nil,
-			&TopologyHint{NewTestBitMask(0), true},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
 			"candidate",
 		},
 		{
 			"current == nil (2)",
 			-1,
 			nil,
-			&TopologyHint{NewTestBitMask(0), false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: false},
 			"candidate",
 		},
 		{
 			"!current.Preferred && candidate.Preferred",
 			-1,
-			&TopologyHint{NewTestBitMask(0), false},
-			&TopologyHint{NewTestBitMask(0), true},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
 			"candidate",
 		},
 		{
 			"current.Preferred && !candidate.Preferred",
 			-1,
-			&TopologyHint{NewTestBitMask(0), true},
-			&TopologyHint{NewTestBitMask(0), false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: false},
 			"current",
 		},
 		{
 			"current.Preferred && candidate.Preferred (1)",
 			-1,
-			&TopologyHint{NewTestBitMask(0), true},
-			&TopologyHint{NewTestBitMask(0), true},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
 			"current",
 		},
 		{
 			"current.Preferred && candidate.Preferred (2)",
 			-1,
-			&TopologyHint{NewTestBitMask(0, 1), true},
-			&TopologyHint{NewTestBitMask(0), true},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: true},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
 			"candidate",
 		},
 		{
 			"current.Preferred && candidate.Preferred (3)",
 			-1,
-			&TopologyHint{NewTestBitMask(0), true},
-			&TopologyHint{NewTestBitMask(0, 1), true},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: true},
 			"current",
 		},
 		{
 			"!current.Preferred && !candidate.Preferred (1.1)",
 			1,
-			&TopologyHint{NewTestBitMask(0, 1), false},
-			&TopologyHint{NewTestBitMask(0, 1), false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: false},
 			"current",
 		},
 		{
 			"!current.Preferred && !candidate.Preferred (1.2)",
 			1,
-			&TopologyHint{NewTestBitMask(1, 2), false},
-			&TopologyHint{NewTestBitMask(0, 1), false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(1, 2), Preferred: false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: false},
 			"candidate",
 		},
 		{
 			"!current.Preferred && !candidate.Preferred (1.3)",
 			1,
-			&TopologyHint{NewTestBitMask(0, 1), false},
-			&TopologyHint{NewTestBitMask(1, 2), false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(1, 2), Preferred: false},
 			"current",
 		},
 		{
 			"!current.Preferred && !candidate.Preferred (2.1)",
 			2,
-			&TopologyHint{NewTestBitMask(0, 1), false},
-			&TopologyHint{NewTestBitMask(0), false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: false},
 			"current",
 		},
 		{
 			"!current.Preferred && !candidate.Preferred (2.2)",
 			2,
-			&TopologyHint{NewTestBitMask(0, 1), false},
-			&TopologyHint{NewTestBitMask(0, 1), false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: false},
 			"current",
 		},
 		{
 			"!current.Preferred && !candidate.Preferred (2.3)",
 			2,
-			&TopologyHint{NewTestBitMask(1, 2), false},
-			&TopologyHint{NewTestBitMask(0, 1), false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(1, 2), Preferred: false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: false},
 			"candidate",
 		},
 		{
 			"!current.Preferred && !candidate.Preferred (2.4)",
 			2,
-			&TopologyHint{NewTestBitMask(0, 1), false},
-			&TopologyHint{NewTestBitMask(1, 2), false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(1, 2), Preferred: false},
 			"current",
 		},
 		{
 			"!current.Preferred && !candidate.Preferred (3a)",
 			2,
-			&TopologyHint{NewTestBitMask(0), false},
-			&TopologyHint{NewTestBitMask(0, 1, 2), false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1, 2), Preferred: false},
 			"current",
 		},
 		{
 			"!current.Preferred && !candidate.Preferred (3b)",
 			2,
-			&TopologyHint{NewTestBitMask(0), false},
-			&TopologyHint{NewTestBitMask(0, 1), false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: false},
 			"candidate",
 		},
 		{
 			"!current.Preferred && !candidate.Preferred (3ca.1)",
 			3,
-			&TopologyHint{NewTestBitMask(0), false},
-			&TopologyHint{NewTestBitMask(0, 1), false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: false},
 			"candidate",
 		},
 		{
 			"!current.Preferred && !candidate.Preferred (3ca.2)",
 			3,
-			&TopologyHint{NewTestBitMask(0), false},
-			&TopologyHint{NewTestBitMask(1, 2), false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(1, 2), Preferred: false},
 			"candidate",
 		},
 		{
 			"!current.Preferred && !candidate.Preferred (3ca.3)",
 			4,
-			&TopologyHint{NewTestBitMask(0, 1), false},
-			&TopologyHint{NewTestBitMask(1, 2, 3), false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(1, 2, 3), Preferred: false},
 			"candidate",
 		},
 		{
 			"!current.Preferred && !candidate.Preferred (3cb)",
 			4,
-			&TopologyHint{NewTestBitMask(1, 2, 3), false},
-			&TopologyHint{NewTestBitMask(0, 1), false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(1, 2, 3), Preferred: false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: false},
 			"current",
 		},
 		{
 			"!current.Preferred && !candidate.Preferred (3cc.1)",
 			4,
-			&TopologyHint{NewTestBitMask(0, 1, 2), false},
-			&TopologyHint{NewTestBitMask(0, 1, 2), false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1, 2), Preferred: false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1, 2), Preferred: false},
 			"current",
 		},
 		{
 			"!current.Preferred && !candidate.Preferred (3cc.2)",
 			4,
-			&TopologyHint{NewTestBitMask(0, 1, 2), false},
-			&TopologyHint{NewTestBitMask(1, 2, 3), false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1, 2), Preferred: false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(1, 2, 3), Preferred: false},
 			"current",
 		},
 		{
 			"!current.Preferred && !candidate.Preferred (3cc.3)",
 			4,
-			&TopologyHint{NewTestBitMask(1, 2, 3), false},
-			&TopologyHint{NewTestBitMask(0, 1, 2), false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(1, 2, 3), Preferred: false},
+			&TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1, 2), Preferred: false},
 			"candidate",
 		},
 	}
@@ -1608,3 +1608,185 @@ func commonNUMAInfoEightNodes() *NUMAInfo {
 		},
 	}
 }
+
+func TestPolicyMergeEmptyProvidersHints(t *testing.T) {
+	numaInfo := commonNUMAInfoTwoNodes()
+
+	tcases := []struct {
+		name          string
+		policy        Policy
+		expected      TopologyHint
+		expectedAdmit bool
+	}{
+		{
+			name:          "best-effort admits with the default affinity",
+			policy:        NewBestEffortPolicy(numaInfo, PolicyOptions{}),
+			expected:      TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: true},
+			expectedAdmit: true,
+		},
+		{
+			name:          "restricted admits with the default affinity",
+			policy:        NewRestrictedPolicy(numaInfo, PolicyOptions{}),
+			expected:      TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: true},
+			expectedAdmit: true,
+		},
+		{
+			name:          "single-numa-node admits with a don't-care affinity",
+			policy:        NewSingleNumaNodePolicy(numaInfo, PolicyOptions{}),
+			expected:      TopologyHint{NUMANodeAffinity: nil, Preferred: true},
+			expectedAdmit: true,
+		},
+		{
+			name:          "distributed admits with a don't-care affinity",
+			policy:        NewDistributedPolicy(numaInfo, PolicyOptions{}),
+			expected:      TopologyHint{NUMANodeAffinity: nil, Preferred: true},
+			expectedAdmit: true,
+		},
+	}
+
+	for _, tc := range tcases {
+		actual, admit := tc.policy.Merge([]map[string][]TopologyHint{})
+		if !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("%v: Expected Topology Hint to be %v, got %v", tc.name, tc.expected, actual)
+		}
+		if admit != tc.expectedAdmit {
+			t.Errorf("%v: Expected admit to be %t, got %t", tc.name, tc.expectedAdmit, admit)
+		}
+	}
+}
+
+func TestPolicyMergeTopN(t *testing.T) {
+	numaInfo := commonNUMAInfoTwoNodes()
+	opts := PolicyOptions{ScoreOverridesPreference: true}
+
+	hop0 := 0
+	hop1 := 1
+	distLocal := 10
+	dist11 := 11
+	dist12 := 12
+	dist20 := 20
+
+	// Scores derived from the enhanced fields: {1} scores 0, {0} scores 2,
+	// {0,1} scores 11, and the second {1} hint scores 10, so the distinct
+	// ranking is {1}, {0}, {0,1} with the better-scoring duplicate of {1}
+	// winning.
+	providersHints := []map[string][]TopologyHint{
+		{
+			"resource": {
+				{NUMANodeAffinity: NewTestBitMask(1), Preferred: true, HopCount: &hop0, Distance: &distLocal},
+				{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: true, HopCount: &hop1, Distance: &dist11},
+				{NUMANodeAffinity: NewTestBitMask(0), Preferred: false, HopCount: &hop0, Distance: &dist12},
+				{NUMANodeAffinity: NewTestBitMask(1), Preferred: true, HopCount: &hop0, Distance: &dist20},
+			},
+		},
+	}
+
+	policy := NewBestEffortPolicy(numaInfo, opts).(TopNMergePolicy)
+
+	results := policy.MergeTopN(providersHints, 2)
+	if len(results) != 2 {
+		t.Fatalf("Expected exactly 2 results for n=2, got %d", len(results))
+	}
+	if !results[0].Hint.NUMANodeAffinity.IsEqual(NewTestBitMask(1)) || !results[1].Hint.NUMANodeAffinity.IsEqual(NewTestBitMask(0)) {
+		t.Errorf("Expected the candidates ranked {1}, {0}, got %v, %v", results[0].Hint.NUMANodeAffinity, results[1].Hint.NUMANodeAffinity)
+	}
+	if results[0].Hint.GetScore() != 0 {
+		t.Errorf("Expected the better-scoring duplicate of mask {1} to win, got score %v", results[0].Hint.GetScore())
+	}
+	for _, result := range results {
+		if !result.Admit {
+			t.Errorf("Expected every returned candidate to be admissible, got %+v", result)
+		}
+	}
+
+	// A large n returns every distinct candidate, nothing more.
+	all := policy.MergeTopN(providersHints, 10)
+	if len(all) != 3 {
+		t.Fatalf("Expected all 3 distinct candidates for n=10, got %d", len(all))
+	}
+	if !all[2].Hint.NUMANodeAffinity.IsEqual(NewTestBitMask(0, 1)) {
+		t.Errorf("Expected {0,1} ranked last, got %v", all[2].Hint.NUMANodeAffinity)
+	}
+
+	// The top result carries the affinity Merge commits.
+	merged, _ := policy.(Policy).Merge(providersHints)
+	if !merged.NUMANodeAffinity.IsEqual(all[0].Hint.NUMANodeAffinity) {
+		t.Errorf("Expected the top candidate %v to match Merge's result %v", all[0].Hint.NUMANodeAffinity, merged.NUMANodeAffinity)
+	}
+
+	// Restricted only reports admissible, i.e. preferred, candidates.
+	restricted := NewRestrictedPolicy(numaInfo, opts).(TopNMergePolicy)
+	restrictedResults := restricted.MergeTopN(providersHints, 10)
+	if len(restrictedResults) != 2 {
+		t.Fatalf("Expected restricted to return the 2 preferred candidates, got %d", len(restrictedResults))
+	}
+	for _, result := range restrictedResults {
+		if !result.Hint.Preferred {
+			t.Errorf("Expected only preferred candidates from restricted, got %+v", result.Hint)
+		}
+	}
+}
+
+func TestFilterProvidersHintsTruncation(t *testing.T) {
+	goodScore := 1.0
+	badScore := 50.0
+	providersHints := []map[string][]TopologyHint{
+		{
+			"resource": {
+				{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: false, Score: &badScore},
+				{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, Score: &goodScore},
+				{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: true, Score: &goodScore},
+				{NUMANodeAffinity: NewTestBitMask(1), Preferred: true, Score: &badScore},
+			},
+		},
+	}
+
+	filteredHints := filterProvidersHints(providersHints, PolicyOptions{MaxHintsPerResource: 2})
+	if len(filteredHints) != 1 {
+		t.Fatalf("Expected hints for one resource, got %v", filteredHints)
+	}
+	// The two best-scoring hints survive, the narrower one first.
+	expected := []TopologyHint{
+		{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, Score: &goodScore},
+		{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: true, Score: &goodScore},
+	}
+	if !reflect.DeepEqual(filteredHints[0], expected) {
+		t.Errorf("Expected the truncated hints to be %v, got %v", expected, filteredHints[0])
+	}
+
+	// At or below the cap nothing is dropped or reordered.
+	filteredHints = filterProvidersHints(providersHints, PolicyOptions{MaxHintsPerResource: 4})
+	if !reflect.DeepEqual(filteredHints[0], providersHints[0]["resource"]) {
+		t.Errorf("Expected the hints to pass through untouched, got %v", filteredHints[0])
+	}
+}
+
+func TestStrictHintValidation(t *testing.T) {
+	negativeHopCount := -1
+	providersHints := []map[string][]TopologyHint{
+		{
+			"resource": {
+				{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+				// A malformed hint: the hop count can never be negative.
+				{NUMANodeAffinity: NewTestBitMask(1), Preferred: true, HopCount: &negativeHopCount},
+			},
+		},
+	}
+
+	// Lenient mode drops the malformed hint and merges the rest.
+	policy := NewRestrictedPolicy(commonNUMAInfoTwoNodes(), PolicyOptions{})
+	hint, admit := policy.Merge(providersHints)
+	if !admit {
+		t.Errorf("Expected admit to be true, got false")
+	}
+	if !hint.NUMANodeAffinity.IsEqual(NewTestBitMask(0)) {
+		t.Errorf("Expected the valid hint to win on node 0, got %v", hint.NUMANodeAffinity)
+	}
+
+	// Strict mode treats the resource as unsatisfiable and rejects the pod.
+	strictPolicy := NewRestrictedPolicy(commonNUMAInfoTwoNodes(), PolicyOptions{StrictHintValidation: true})
+	_, admit = strictPolicy.Merge(providersHints)
+	if admit {
+		t.Errorf("Expected admit to be false, got true")
+	}
+}
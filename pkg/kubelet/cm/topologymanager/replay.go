@@ -0,0 +1,130 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"fmt"
+
+	"k8s.io/kubernetes/pkg/kubelet/cm/topologymanager/bitmask"
+)
+
+// HintCapture is a serializable record of one merge's inputs: the provider
+// hints accumulated for a container or pod and the NUMA topology they were
+// generated against. Captures taken on production nodes can be replayed
+// offline with ReplayMerge to compare how different policies and options
+// would have placed the same workload.
+type HintCapture struct {
+	NUMANodes      []int                               `json:"numaNodes"`
+	NUMADistances  map[int][]uint64                    `json:"numaDistances,omitempty"`
+	ProvidersHints []map[string][]TopologyHintSnapshot `json:"providersHints"`
+}
+
+// CaptureProvidersHints records the given provider hints and NUMA topology in
+// their serializable form. Nil hint lists ("don't care") are preserved as
+// nil, so a replay merges them the same way the live merge did.
+func CaptureProvidersHints(numaInfo *NUMAInfo, providersHints []map[string][]TopologyHint) HintCapture {
+	capture := HintCapture{}
+	if numaInfo != nil {
+		capture.NUMANodes = append([]int{}, numaInfo.Nodes...)
+		capture.NUMADistances = make(map[int][]uint64, len(numaInfo.NUMADistances))
+		for node, distances := range numaInfo.NUMADistances {
+			capture.NUMADistances[node] = append([]uint64{}, distances...)
+		}
+	}
+	for _, providerHints := range providersHints {
+		captured := make(map[string][]TopologyHintSnapshot, len(providerHints))
+		for resource, hints := range providerHints {
+			if hints == nil {
+				captured[resource] = nil
+				continue
+			}
+			capturedHints := make([]TopologyHintSnapshot, 0, len(hints))
+			for _, hint := range hints {
+				capturedHints = append(capturedHints, snapshotTopologyHint(hint))
+			}
+			captured[resource] = capturedHints
+		}
+		capture.ProvidersHints = append(capture.ProvidersHints, captured)
+	}
+	return capture
+}
+
+// restoreTopologyHint rebuilds a TopologyHint from its serializable form.
+func restoreTopologyHint(snapshot TopologyHintSnapshot) (TopologyHint, error) {
+	hint := TopologyHint{
+		Preferred: snapshot.Preferred,
+		HopCount:  snapshot.HopCount,
+		Distance:  snapshot.Distance,
+		Bandwidth: snapshot.Bandwidth,
+		Score:     snapshot.Score,
+	}
+	if snapshot.NUMANodeAffinity != nil {
+		affinity, err := bitmask.NewBitMask(snapshot.NUMANodeAffinity...)
+		if err != nil {
+			return TopologyHint{}, fmt.Errorf("invalid NUMA node affinity %v: %w", snapshot.NUMANodeAffinity, err)
+		}
+		hint.NUMANodeAffinity = affinity
+	}
+	return hint, nil
+}
+
+// ReplayMerge merges the captured provider hints under the given policy and
+// options, without consulting any hint provider or committing anything, so a
+// standalone tuning tool can replay admissions recorded on production nodes
+// against alternative scoring weights and penalties and compare the outcomes.
+// opts should come from NewPolicyOptions, so defaulted fields are populated.
+// The merge is self-contained — every option, including the local distance
+// baseline, score rounding, the per-resource hint cap, and locality classes,
+// is read from opts — so replays can run concurrently with each other and
+// with a live topology manager.
+func ReplayMerge(captured HintCapture, policyName string, opts PolicyOptions) (MergeResult, error) {
+	numaInfo := &NUMAInfo{
+		Nodes:         append([]int{}, captured.NUMANodes...),
+		NUMADistances: NUMADistances{},
+	}
+	for node, distances := range captured.NUMADistances {
+		numaInfo.NUMADistances[node] = append([]uint64{}, distances...)
+	}
+
+	providersHints := make([]map[string][]TopologyHint, 0, len(captured.ProvidersHints))
+	for _, capturedProvider := range captured.ProvidersHints {
+		providerHints := make(map[string][]TopologyHint, len(capturedProvider))
+		for resource, capturedHints := range capturedProvider {
+			if capturedHints == nil {
+				providerHints[resource] = nil
+				continue
+			}
+			hints := make([]TopologyHint, 0, len(capturedHints))
+			for _, capturedHint := range capturedHints {
+				hint, err := restoreTopologyHint(capturedHint)
+				if err != nil {
+					return MergeResult{}, fmt.Errorf("cannot restore captured hint for resource %q: %w", resource, err)
+				}
+				hints = append(hints, hint)
+			}
+			providerHints[resource] = hints
+		}
+		providersHints = append(providersHints, providerHints)
+	}
+
+	policy, err := newPolicyByName(policyName, numaInfo, opts)
+	if err != nil {
+		return MergeResult{}, err
+	}
+	hint, admit := policy.Merge(providersHints)
+	return MergeResult{Hint: hint, Admit: admit}, nil
+}
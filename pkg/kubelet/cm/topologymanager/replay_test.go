@@ -0,0 +1,83 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"encoding/json"
+	"testing"
+
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"k8s.io/kubernetes/pkg/features"
+)
+
+func TestReplayMergeRoundTrip(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.EnhancedTopologyHints, true)
+
+	numaInfo := commonNUMAInfoTwoNodes()
+	hopCount := 1
+	distance := 20
+	bandwidth := 80.0
+	providersHints := []map[string][]TopologyHint{
+		{
+			"example.com/gpu": {
+				{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, HopCount: &hopCount, Distance: &distance, Bandwidth: &bandwidth},
+				{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: false},
+			},
+			// A "don't care" resource must survive the round trip as nil.
+			"example.com/agnostic": nil,
+		},
+		{
+			"cpu": {
+				{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+				{NUMANodeAffinity: NewTestBitMask(1), Preferred: true},
+			},
+		},
+	}
+
+	opts, err := NewPolicyOptions(nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	captured := CaptureProvidersHints(numaInfo, providersHints)
+	serialized, err := json.Marshal(captured)
+	if err != nil {
+		t.Fatalf("Unexpected error marshaling the capture: %v", err)
+	}
+	var restored HintCapture
+	if err := json.Unmarshal(serialized, &restored); err != nil {
+		t.Fatalf("Unexpected error unmarshaling the capture: %v", err)
+	}
+
+	result, err := ReplayMerge(restored, PolicyBestEffort, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	liveHint, liveAdmit := NewBestEffortPolicy(numaInfo, opts).Merge(providersHints)
+	if result.Admit != liveAdmit {
+		t.Errorf("Expected the replay to admit like the live merge (%v), got %v", liveAdmit, result.Admit)
+	}
+	if !result.Hint.IsEqual(liveHint) {
+		t.Errorf("Expected the replayed hint to equal the live one %v, got %v", liveHint, result.Hint)
+	}
+
+	if _, err := ReplayMerge(restored, "no-such-policy", opts); err == nil {
+		t.Errorf("Expected an error for an unknown policy name")
+	}
+}
@@ -17,6 +17,10 @@ limitations under the License.
 package topologymanager
 
 import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 
 	"k8s.io/api/core/v1"
@@ -24,6 +28,7 @@ import (
 	"k8s.io/kubernetes/pkg/kubelet/cm/admission"
 	"k8s.io/kubernetes/pkg/kubelet/cm/containermap"
 	"k8s.io/kubernetes/pkg/kubelet/lifecycle"
+	"k8s.io/kubernetes/pkg/kubelet/metrics"
 )
 
 const (
@@ -49,6 +54,19 @@ type Scope interface {
 	AddContainer(pod *v1.Pod, container *v1.Container, containerID string)
 	// RemoveContainer removes pod from Manager tracking
 	RemoveContainer(containerID string) error
+	// AlignedCapacity aggregates the remaining aligned capacity of the given
+	// resource across all registered hint providers that report one.
+	AlignedCapacity(resource string) map[int]int64
+	// PinnedNodeCounts counts, per NUMA node, how many containers tracked
+	// by this scope have a committed affinity pinning the node.
+	PinnedNodeCounts() map[int]int
+	// HintSnapshots returns a serializable copy of the hints committed for
+	// every container tracked by this scope.
+	HintSnapshots() []ContainerHintSnapshot
+	// NotifyTopologyChanged invalidates any cached merge results after a
+	// machine topology change, so later admissions recompute against the
+	// new topology.
+	NotifyTopologyChanged()
 	// Store is the interface for storing pod topology hints
 	Store
 }
@@ -63,6 +81,9 @@ type scope struct {
 	hintProviders []HintProvider
 	// Topology Manager Policy
 	policy Policy
+	// mergeCache optionally memoizes merge results keyed by a fingerprint of
+	// the provider hints. A nil cache disables memoization.
+	mergeCache *mergeResultCache
 	// Mapping of (PodUid, ContainerName) to ContainerID for Adding/Removing Pods from PodTopologyHints mapping
 	podMap containermap.ContainerMap
 }
@@ -95,10 +116,234 @@ func (s *scope) GetPolicy() Policy {
 	return s.policy
 }
 
+func (s *scope) GetScopeName() string {
+	return s.name
+}
+
+// policyOptions returns the options of the policy this scope admits through,
+// or a zero value — every option at its default — for policies that carry
+// none, like the none policy.
+func (s *scope) policyOptions() PolicyOptions {
+	if source, ok := s.policy.(policyOptionsSource); ok {
+		return source.policyOptions()
+	}
+	return PolicyOptions{}
+}
+
 func (s *scope) AddHintProvider(h HintProvider) {
 	s.hintProviders = append(s.hintProviders, h)
 }
 
+func (s *scope) NotifyTopologyChanged() {
+	if s.mergeCache != nil {
+		s.mergeCache.bumpGeneration()
+	}
+}
+
+// resourceAntiAffinity parses the resource anti-affinity annotation into the
+// list of resource names that must not share a NUMA node. It returns nil
+// when the pod does not carry the annotation.
+func resourceAntiAffinity(pod *v1.Pod) []string {
+	value, ok := pod.Annotations[ResourceAntiAffinityAnnotationKey]
+	if !ok {
+		return nil
+	}
+	var resources []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			resources = append(resources, name)
+		}
+	}
+	return resources
+}
+
+// resourceDataSizes parses the per-resource data size annotations into a map
+// of resource name to estimated bytes moved. Annotations with unparsable or
+// negative values are ignored with a log entry. It returns nil when the pod
+// carries no data size annotations.
+func resourceDataSizes(pod *v1.Pod) map[string]int64 {
+	var sizes map[string]int64
+	for key, value := range pod.Annotations {
+		if !strings.HasPrefix(key, DataSizeAnnotationPrefix) {
+			continue
+		}
+		resource := strings.ReplaceAll(strings.TrimPrefix(key, DataSizeAnnotationPrefix), "~1", "/")
+		bytes, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || bytes < 0 {
+			klog.InfoS("Ignoring invalid data size annotation", "pod", klog.KObj(pod), "annotation", key, "value", value)
+			continue
+		}
+		if sizes == nil {
+			sizes = make(map[string]int64)
+		}
+		sizes[resource] = bytes
+	}
+	return sizes
+}
+
+// requestedInterconnectBandwidth returns the largest interconnect bandwidth,
+// in GB/s, any container of the pod requests through the well-known extended
+// resource, or 0 when no container requests it. The maximum is used because
+// every container of the pod may land on the merged placement, so it must
+// satisfy the most demanding one.
+func requestedInterconnectBandwidth(pod *v1.Pod) float64 {
+	var bandwidth float64
+	for _, container := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+		if quantity, ok := container.Resources.Requests[InterconnectBandwidthResourceName]; ok {
+			if value := quantity.AsApproximateFloat64(); value > bandwidth {
+				bandwidth = value
+			}
+		}
+	}
+	return bandwidth
+}
+
+// demoteLowBandwidthHints returns a copy of the provider hints in which every
+// hint reporting less interconnect bandwidth than requested is demoted to
+// non-preferred. Hints without a bandwidth metric are not demoted: absence
+// means the provider does not know, not that the placement is inadequate.
+func demoteLowBandwidthHints(providersHints []map[string][]TopologyHint, requested float64) []map[string][]TopologyHint {
+	demoted := make([]map[string][]TopologyHint, 0, len(providersHints))
+	for _, providerHints := range providersHints {
+		demotedProvider := make(map[string][]TopologyHint, len(providerHints))
+		for resource, hints := range providerHints {
+			if hints == nil {
+				demotedProvider[resource] = nil
+				continue
+			}
+			demotedHints := make([]TopologyHint, 0, len(hints))
+			for _, hint := range hints {
+				if hint.HasBandwidth() && hint.GetBandwidth() < requested {
+					hint.Preferred = false
+				}
+				demotedHints = append(demotedHints, hint)
+			}
+			demotedProvider[resource] = demotedHints
+		}
+		demoted = append(demoted, demotedProvider)
+	}
+	return demoted
+}
+
+// unalignableResources reruns the policy merge for each resource in isolation
+// and returns the sorted names of the resources that cannot be aligned even on
+// their own. A rejection of a pod requesting several resources can then name
+// the culprit instead of blaming the whole request.
+func unalignableResources(policy Policy, providersHints []map[string][]TopologyHint) []string {
+	perResource := make(map[string][]map[string][]TopologyHint)
+	for _, providerHints := range providersHints {
+		for resource, hints := range providerHints {
+			perResource[resource] = append(perResource[resource], map[string][]TopologyHint{resource: hints})
+		}
+	}
+	var unalignable []string
+	for resource, resourceHints := range perResource {
+		if _, admit := policy.Merge(resourceHints); !admit {
+			unalignable = append(unalignable, resource)
+		}
+	}
+	sort.Strings(unalignable)
+	return unalignable
+}
+
+// providersHintsAreAgnostic reports whether no provider expressed a topology
+// preference for the pod: every provider returned either no hint map at all
+// or only nil hint lists ("don't care"). An empty, non-nil hint list means
+// the provider found no feasible affinity and must still go through the
+// merger so the policy can reject the pod.
+func providersHintsAreAgnostic(providersHints []map[string][]TopologyHint) bool {
+	for _, providerHints := range providersHints {
+		for _, resourceHints := range providerHints {
+			if resourceHints != nil {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// mergeProvidersHints runs the policy merge, consulting the merge cache when
+// one is configured so that re-admissions with identical provider hints skip
+// the full merge. Pods carrying a resource anti-affinity annotation are
+// merged through the policy's anti-affinity path instead, when it has one.
+// Pods that request nothing topology-relevant produce no hints at all and are
+// admitted immediately with a don't-care affinity, skipping the merger.
+func (s *scope) mergeProvidersHints(pod *v1.Pod, providersHints []map[string][]TopologyHint) (TopologyHint, bool) {
+	if providersHintsAreAgnostic(providersHints) {
+		return TopologyHint{NUMANodeAffinity: nil, Preferred: true}, true
+	}
+	if resources := resourceAntiAffinity(pod); len(resources) > 1 {
+		if policy, ok := s.policy.(AntiAffinityAwarePolicy); ok {
+			// The constraint is per-pod, so bypass the merge cache.
+			return policy.MergeWithAntiAffinity(providersHints, resources)
+		}
+		klog.InfoS("Ignoring resource anti-affinity annotation: not supported by policy", "policy", s.policy.Name(), "pod", klog.KObj(pod))
+	}
+	if bandwidth := requestedInterconnectBandwidth(pod); bandwidth > 0 {
+		// The requested bandwidth is per-pod, so bypass the merge cache and
+		// demote hints that cannot meet it before merging; the restricted
+		// policy then rejects the pod when no adequate placement remains.
+		return s.policy.Merge(demoteLowBandwidthHints(providersHints, bandwidth))
+	}
+	if sizes := resourceDataSizes(pod); len(sizes) > 0 {
+		// The sizes change the score of the merged hint per pod, so bypass
+		// the merge cache and recompute the score with one bandwidth term
+		// per resource.
+		hint, admit := s.policy.Merge(providersHints)
+		hint.UpdateScoreWithDataSizes(sizes, s.policyOptions())
+		return hint, admit
+	}
+	if policy, ok := s.policy.(CapacityAwarePolicy); ok && policy.CapacityWeighted() {
+		// Free capacity shifts between admissions, so a cached merge result
+		// would go stale; recompute every time.
+		return s.policy.Merge(providersHints)
+	}
+	if s.mergeCache == nil {
+		return s.policy.Merge(providersHints)
+	}
+	fingerprint := fingerprintProvidersHints(providersHints)
+	if hint, admit, ok := s.mergeCache.get(fingerprint); ok {
+		klog.V(5).InfoS("Topology hint merge cache hit", "fingerprint", fingerprint)
+		return hint, admit
+	}
+	hint, admit := s.policy.Merge(providersHints)
+	s.mergeCache.add(fingerprint, hint, admit)
+	return hint, admit
+}
+
+func (s *scope) AlignedCapacity(resource string) map[int]int64 {
+	capacity := make(map[int]int64)
+	for _, provider := range s.hintProviders {
+		reporter, ok := provider.(AlignedCapacityReporter)
+		if !ok {
+			continue
+		}
+		for node, remaining := range reporter.AlignedCapacity(resource) {
+			capacity[node] += remaining
+		}
+	}
+	return capacity
+}
+
+// PinnedNodeCounts aggregates the committed affinities of every container
+// tracked by this scope, counting one per container for each NUMA node its
+// affinity pins. Containers admitted with a don't-care affinity pin nothing
+// and do not contribute.
+func (s *scope) PinnedNodeCounts() map[int]int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	counts := make(map[int]int)
+	for _, containers := range s.podTopologyHints {
+		for _, hint := range containers {
+			for _, node := range hint.NodeIDs() {
+				counts[node]++
+			}
+		}
+	}
+	return counts
+}
+
 // It would be better to implement this function in topologymanager instead of scope
 // but topologymanager do not track mapping anymore
 func (s *scope) AddContainer(pod *v1.Pod, container *v1.Container, containerID string) {
@@ -148,11 +393,34 @@ func (s *scope) admitPolicyNone(pod *v1.Pod) lifecycle.PodAdmitResult {
 // It would be better to implement this function in topologymanager instead of scope
 // but topologymanager do not track providers anymore
 func (s *scope) allocateAlignedResources(pod *v1.Pod, container *v1.Container) error {
+	var allocated []HintProvider
 	for _, provider := range s.hintProviders {
 		err := provider.Allocate(pod, container)
 		if err != nil {
-			return err
+			metrics.TopologyManagerAllocateErrorsTotal.WithLabelValues(metrics.AllocateResultError).Inc()
+			s.rollbackAllocations(pod, container, allocated)
+			// Wrap rather than replace, so typed admission errors (e.g.
+			// TopologyAffinityError) still unwrap to their reason.
+			return fmt.Errorf("hint provider %T failed to allocate resources for container %q: %w", provider, container.Name, err)
 		}
+		allocated = append(allocated, provider)
 	}
 	return nil
 }
+
+// rollbackAllocations undoes, in reverse order, the allocations that already
+// succeeded before a later provider's Allocate failed. Only providers
+// implementing DeallocatableHintProvider participate; rollback failures are
+// logged so they do not mask the original allocation error.
+func (s *scope) rollbackAllocations(pod *v1.Pod, container *v1.Container, allocated []HintProvider) {
+	for i := len(allocated) - 1; i >= 0; i-- {
+		provider, ok := allocated[i].(DeallocatableHintProvider)
+		if !ok {
+			continue
+		}
+		if err := provider.Deallocate(pod, container); err != nil {
+			metrics.TopologyManagerAllocateErrorsTotal.WithLabelValues(metrics.AllocateResultRollback).Inc()
+			klog.ErrorS(err, "Failed to roll back allocation", "pod", klog.KObj(pod), "containerName", container.Name)
+		}
+	}
+}
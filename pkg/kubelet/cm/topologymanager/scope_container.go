@@ -18,9 +18,12 @@ package topologymanager
 
 import (
 	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 	"k8s.io/kubernetes/pkg/kubelet/cm/admission"
 	"k8s.io/kubernetes/pkg/kubelet/cm/containermap"
+	"k8s.io/kubernetes/pkg/kubelet/cm/topologymanager/bitmask"
 	"k8s.io/kubernetes/pkg/kubelet/lifecycle"
 	"k8s.io/kubernetes/pkg/kubelet/metrics"
 )
@@ -40,6 +43,7 @@ func NewContainerScope(policy Policy) Scope {
 			podTopologyHints: podTopologyHints{},
 			policy:           policy,
 			podMap:           containermap.NewContainerMap(),
+			mergeCache:       newMergeResultCache(),
 		},
 	}
 }
@@ -54,7 +58,11 @@ func (s *containerScope) Admit(pod *v1.Pod) lifecycle.PodAdmitResult {
 				metrics.ContainerAlignedComputeResourcesFailure.WithLabelValues(metrics.AlignScopeContainer, metrics.AlignedNUMANode).Inc()
 			}
 			metrics.TopologyManagerAdmissionErrorsTotal.Inc()
-			return admission.GetPodAdmitResult(&TopologyAffinityError{})
+			// Re-merge each resource on its own so the error can name the
+			// ones that could not be aligned; admission failures are rare
+			// enough that the extra provider round-trip does not matter.
+			unalignable := unalignableResources(s.policy, s.accumulateProvidersHints(pod, &container))
+			return admission.GetPodAdmitResult(&TopologyAffinityError{UnalignableResources: unalignable})
 		}
 		klog.InfoS("Topology Affinity", "bestHint", bestHint, "pod", klog.KObj(pod), "containerName", container.Name)
 		s.setTopologyHints(string(pod.UID), container.Name, bestHint)
@@ -77,8 +85,23 @@ func (s *containerScope) accumulateProvidersHints(pod *v1.Pod, container *v1.Con
 	var providersHints []map[string][]TopologyHint
 
 	for _, provider := range s.hintProviders {
-		// Get the TopologyHints for a Container from a provider.
-		hints := provider.GetTopologyHints(pod, container)
+		// Skip providers that report themselves as unhealthy, so their
+		// stale or empty hints do not influence merging.
+		if !isProviderHealthy(provider) {
+			klog.InfoS("Skipping unhealthy hint provider", "pod", klog.KObj(pod), "containerName", container.Name)
+			metrics.TopologyManagerSkippedUnhealthyProvidersTotal.Inc()
+			continue
+		}
+		// Get the TopologyHints for a Container from a provider, preferring
+		// valid precomputed hints when the provider keeps some.
+		var hints map[string][]TopologyHint
+		cached := false
+		if cachingProvider, ok := provider.(CachedHintsProvider); ok {
+			hints, cached = cachingProvider.CachedHints(pod, container)
+		}
+		if !cached {
+			hints = provider.GetTopologyHints(pod, container)
+		}
 		providersHints = append(providersHints, hints)
 		klog.InfoS("TopologyHints", "hints", hints, "pod", klog.KObj(pod), "containerName", container.Name)
 	}
@@ -87,7 +110,103 @@ func (s *containerScope) accumulateProvidersHints(pod *v1.Pod, container *v1.Con
 
 func (s *containerScope) calculateAffinity(pod *v1.Pod, container *v1.Container) (TopologyHint, bool) {
 	providersHints := s.accumulateProvidersHints(pod, container)
-	bestHint, admit := s.policy.Merge(providersHints)
+	opts := s.policyOptions()
+	if opts.PreferPodSiblingNodes {
+		if siblings := s.podSiblingNodes(pod); siblings != nil {
+			if siblingHints, ok := restrictHintsToSiblingNodes(providersHints, siblings); ok {
+				if bestHint, admit := s.mergeProvidersHints(pod, siblingHints); admit && bestHint.Preferred {
+					klog.V(4).InfoS("Co-located container with its pod siblings", "bestHint", bestHint, "siblings", siblings, "pod", klog.KObj(pod), "containerName", container.Name)
+					return bestHint, admit
+				}
+			}
+			klog.V(4).InfoS("Could not co-locate container with its pod siblings, merging unbiased", "siblings", siblings, "pod", klog.KObj(pod), "containerName", container.Name)
+		}
+	}
+	bestHint, admit := s.mergeProvidersHints(pod, providersHints)
+	if admit && opts.ScoreHysteresis > 0 {
+		bestHint = s.applyScoreHysteresis(string(pod.UID), container.Name, bestHint, opts.ScoreHysteresis)
+	}
 	klog.InfoS("ContainerTopologyHint", "bestHint", bestHint, "pod", klog.KObj(pod), "containerName", container.Name)
 	return bestHint, admit
 }
+
+// applyScoreHysteresis keeps a re-admitted container on its stored affinity
+// unless the freshly merged hint improves the score by more than the
+// score-hysteresis margin, so recomputed scores that differ only marginally
+// do not move containers around. Hints without scores commit as merged.
+func (s *containerScope) applyScoreHysteresis(podUID string, containerName string, merged TopologyHint, hysteresis float64) TopologyHint {
+	stored := s.getTopologyHints(podUID, containerName)
+	if stored.NUMANodeAffinity == nil || merged.NUMANodeAffinity == nil || stored.NUMANodeAffinity.IsEqual(merged.NUMANodeAffinity) {
+		return merged
+	}
+	if !stored.HasScore() || !merged.HasScore() {
+		return merged
+	}
+	if improvement := stored.GetScore() - merged.GetScore(); improvement <= hysteresis {
+		klog.V(4).InfoS("Keeping the stored affinity within the score hysteresis margin", "podUID", podUID, "containerName", containerName, "storedHint", stored, "mergedHint", merged, "improvement", improvement, "scoreHysteresis", hysteresis)
+		return stored
+	}
+	return merged
+}
+
+// podSiblingNodes returns the union of the NUMA nodes already committed to
+// earlier containers of the pod, or nil when none of them pins a node yet.
+// With exclude-init-containers set, regular init containers do not count as
+// siblings: their resources are released before the app containers run, so
+// their placement should not pull the app containers after them. Restartable
+// init containers keep running beside the app containers and always count.
+func (s *containerScope) podSiblingNodes(pod *v1.Pod) bitmask.BitMask {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	excluded := sets.New[string]()
+	if s.policyOptions().ExcludeInitContainers {
+		for _, initContainer := range pod.Spec.InitContainers {
+			if !podutil.IsRestartableInitContainer(&initContainer) {
+				excluded.Insert(initContainer.Name)
+			}
+		}
+	}
+	var nodes []int
+	for containerName, hint := range s.podTopologyHints[string(pod.UID)] {
+		if excluded.Has(containerName) {
+			continue
+		}
+		nodes = append(nodes, hint.NodeIDs()...)
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+	siblings, _ := bitmask.NewBitMask(nodes...)
+	return siblings
+}
+
+// restrictHintsToSiblingNodes returns a copy of the provider hints keeping
+// only hints whose affinity fits inside the siblings mask, so merging them
+// can only place the container beside its pod siblings. It reports false when
+// any resource would lose all of its hints, meaning co-location is infeasible
+// and the caller should merge the unrestricted hints instead.
+func restrictHintsToSiblingNodes(providersHints []map[string][]TopologyHint, siblings bitmask.BitMask) ([]map[string][]TopologyHint, bool) {
+	restricted := make([]map[string][]TopologyHint, 0, len(providersHints))
+	for _, providerHints := range providersHints {
+		restrictedProvider := make(map[string][]TopologyHint, len(providerHints))
+		for resource, hints := range providerHints {
+			if hints == nil {
+				restrictedProvider[resource] = nil
+				continue
+			}
+			restrictedHints := []TopologyHint{}
+			for _, hint := range hints {
+				if hint.NUMANodeAffinity == nil || bitmask.And(hint.NUMANodeAffinity, siblings).IsEqual(hint.NUMANodeAffinity) {
+					restrictedHints = append(restrictedHints, hint)
+				}
+			}
+			if len(restrictedHints) == 0 && len(hints) > 0 {
+				return nil, false
+			}
+			restrictedProvider[resource] = restrictedHints
+		}
+		restricted = append(restricted, restrictedProvider)
+	}
+	return restricted, true
+}
@@ -21,6 +21,10 @@ import (
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	pkgfeatures "k8s.io/kubernetes/pkg/features"
+	"k8s.io/kubernetes/pkg/kubelet/cm/containermap"
 )
 
 func TestContainerCalculateAffinity(t *testing.T) {
@@ -35,17 +39,19 @@ func TestContainerCalculateAffinity(t *testing.T) {
 			expected: ([]map[string][]TopologyHint)(nil),
 		},
 		{
+			// Agnostic hints short-circuit admission, so the merger never
+			// sees them.
 			name: "HintProvider returns empty non-nil map[string][]TopologyHint",
 			hp: []HintProvider{
 				&mockHintProvider{
 					map[string][]TopologyHint{},
 				},
 			},
-			expected: []map[string][]TopologyHint{
-				{},
-			},
+			expected: ([]map[string][]TopologyHint)(nil),
 		},
 		{
+			// Agnostic hints short-circuit admission, so the merger never
+			// sees them.
 			name: "HintProvider returns -nil map[string][]TopologyHint from provider",
 			hp: []HintProvider{
 				&mockHintProvider{
@@ -54,11 +60,7 @@ func TestContainerCalculateAffinity(t *testing.T) {
 					},
 				},
 			},
-			expected: []map[string][]TopologyHint{
-				{
-					"resource": nil,
-				},
-			},
+			expected: ([]map[string][]TopologyHint)(nil),
 		},
 		{
 			name: "Assorted HintProviders",
@@ -266,3 +268,333 @@ func TestContainerAccumulateProvidersHints(t *testing.T) {
 		}
 	}
 }
+
+func TestContainerScopeSkipsUnhealthyHintProviders(t *testing.T) {
+	tcases := []struct {
+		name     string
+		hp       []HintProvider
+		expected []map[string][]TopologyHint
+	}{
+		{
+			name: "Unhealthy HintProvider hints are excluded",
+			hp: []HintProvider{
+				&mockHintProvider{
+					map[string][]TopologyHint{
+						"resource1": {TopologyHint{}},
+					},
+				},
+				&mockHealthCheckableHintProvider{
+					mockHintProvider: mockHintProvider{
+						map[string][]TopologyHint{
+							"resource2": {TopologyHint{}},
+						},
+					},
+					healthy: false,
+				},
+			},
+			expected: []map[string][]TopologyHint{
+				{
+					"resource1": {TopologyHint{}},
+				},
+			},
+		},
+		{
+			name: "Healthy HintProvider hints still contribute",
+			hp: []HintProvider{
+				&mockHealthCheckableHintProvider{
+					mockHintProvider: mockHintProvider{
+						map[string][]TopologyHint{
+							"resource1": {TopologyHint{}},
+						},
+					},
+					healthy: true,
+				},
+			},
+			expected: []map[string][]TopologyHint{
+				{
+					"resource1": {TopologyHint{}},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tcases {
+		ctnScope := containerScope{
+			scope{
+				hintProviders: tc.hp,
+			},
+		}
+		actual := ctnScope.accumulateProvidersHints(&v1.Pod{}, &v1.Container{})
+		if !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("Test Case %s: Expected hints to be %v, got %v", tc.name, tc.expected, actual)
+		}
+	}
+}
+
+// perContainerHintProvider returns different hints per container name, unlike
+// mockHintProvider which answers every container identically.
+type perContainerHintProvider struct {
+	hints map[string]map[string][]TopologyHint
+}
+
+func (p *perContainerHintProvider) GetTopologyHints(pod *v1.Pod, container *v1.Container) map[string][]TopologyHint {
+	return p.hints[container.Name]
+}
+
+func (p *perContainerHintProvider) GetPodTopologyHints(pod *v1.Pod) map[string][]TopologyHint {
+	return nil
+}
+
+func (p *perContainerHintProvider) Allocate(pod *v1.Pod, container *v1.Container) error {
+	return nil
+}
+
+func TestContainerScopePreferPodSiblingNodes(t *testing.T) {
+	provider := &perContainerHintProvider{
+		hints: map[string]map[string][]TopologyHint{
+			// Container A can only go to node 1, so it seeds the sibling mask
+			// away from the node the unbiased tie-break would pick.
+			"a": {
+				"resource": {
+					{NUMANodeAffinity: NewTestBitMask(1), Preferred: true},
+				},
+			},
+			// Container B fits on either node; without the bias the merger
+			// would keep the lower-numbered node 0.
+			"b": {
+				"resource": {
+					{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+					{NUMANodeAffinity: NewTestBitMask(1), Preferred: true},
+				},
+			},
+			// Container C cannot fit beside its siblings and must fall back
+			// to the unbiased merge.
+			"c": {
+				"resource": {
+					{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+				},
+			},
+		},
+	}
+	scope := NewContainerScope(NewBestEffortPolicy(commonNUMAInfoTwoNodes(), PolicyOptions{PreferPodSiblingNodes: true}))
+	scope.AddHintProvider(provider)
+
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+		},
+	}
+	pod.UID = "siblingPod"
+
+	if result := scope.Admit(pod); !result.Admit {
+		t.Fatalf("Expected the pod to be admitted, got %v", result.Message)
+	}
+
+	expected := map[string]TopologyHint{
+		"a": {NUMANodeAffinity: NewTestBitMask(1), Preferred: true},
+		"b": {NUMANodeAffinity: NewTestBitMask(1), Preferred: true},
+		"c": {NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+	}
+	for containerName, expectedHint := range expected {
+		hint := scope.GetAffinity("siblingPod", containerName)
+		if !hint.IsEqual(expectedHint) {
+			t.Errorf("Expected container %q to be pinned to %v, got %v", containerName, expectedHint, hint)
+		}
+	}
+
+	// With the option off, container B keeps the unbiased lower-numbered node.
+	unbiasedScope := NewContainerScope(NewBestEffortPolicy(commonNUMAInfoTwoNodes(), PolicyOptions{}))
+	unbiasedScope.AddHintProvider(provider)
+	pod.UID = "unbiasedPod"
+	if result := unbiasedScope.Admit(pod); !result.Admit {
+		t.Fatalf("Expected the pod to be admitted, got %v", result.Message)
+	}
+	hint := unbiasedScope.GetAffinity("unbiasedPod", "b")
+	if !hint.NUMANodeAffinity.IsEqual(NewTestBitMask(0)) {
+		t.Errorf("Expected container \"b\" to stay on node 0 without the option, got %v", hint)
+	}
+}
+
+func TestContainerScopeExcludeInitContainers(t *testing.T) {
+	provider := &perContainerHintProvider{
+		hints: map[string]map[string][]TopologyHint{
+			// The init container only fits on node 1.
+			"init": {
+				"resource": {
+					{NUMANodeAffinity: NewTestBitMask(1), Preferred: true},
+				},
+			},
+			// The app container fits on either node; without a sibling bias
+			// the merger keeps the lower-numbered node 0.
+			"app": {
+				"resource": {
+					{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+					{NUMANodeAffinity: NewTestBitMask(1), Preferred: true},
+				},
+			},
+		},
+	}
+	scope := NewContainerScope(NewBestEffortPolicy(commonNUMAInfoTwoNodes(), PolicyOptions{PreferPodSiblingNodes: true, ExcludeInitContainers: true}))
+	scope.AddHintProvider(provider)
+
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			InitContainers: []v1.Container{{Name: "init"}},
+			Containers:     []v1.Container{{Name: "app"}},
+		},
+	}
+	pod.UID = "excludedPod"
+	if result := scope.Admit(pod); !result.Admit {
+		t.Fatalf("Expected the pod to be admitted, got %v", result.Message)
+	}
+	if hint := scope.GetAffinity("excludedPod", "app"); !hint.NUMANodeAffinity.IsEqual(NewTestBitMask(0)) {
+		t.Errorf("Expected the app container placement to ignore the init container, got %v", hint)
+	}
+
+	// A restartable init container keeps running beside the app containers,
+	// so it still counts as a sibling.
+	restartAlways := v1.ContainerRestartPolicyAlways
+	pod.Spec.InitContainers[0].RestartPolicy = &restartAlways
+	pod.UID = "sidecarPod"
+	if result := scope.Admit(pod); !result.Admit {
+		t.Fatalf("Expected the pod to be admitted, got %v", result.Message)
+	}
+	if hint := scope.GetAffinity("sidecarPod", "app"); !hint.NUMANodeAffinity.IsEqual(NewTestBitMask(1)) {
+		t.Errorf("Expected the app container to follow the restartable init container, got %v", hint)
+	}
+
+	// Without the option, the init container placement pulls the app
+	// container to its node.
+	biasedScope := NewContainerScope(NewBestEffortPolicy(commonNUMAInfoTwoNodes(), PolicyOptions{PreferPodSiblingNodes: true}))
+	biasedScope.AddHintProvider(provider)
+	pod.Spec.InitContainers[0].RestartPolicy = nil
+	pod.UID = "biasedPod"
+	if result := biasedScope.Admit(pod); !result.Admit {
+		t.Fatalf("Expected the pod to be admitted, got %v", result.Message)
+	}
+	if hint := biasedScope.GetAffinity("biasedPod", "app"); !hint.NUMANodeAffinity.IsEqual(NewTestBitMask(1)) {
+		t.Errorf("Expected the app container to follow the init container without the option, got %v", hint)
+	}
+}
+
+// mockCachingHintProvider serves precomputed hints while its cache is valid
+// and counts how often each path is taken.
+type mockCachingHintProvider struct {
+	cachedHints   map[string][]TopologyHint
+	cacheValid    bool
+	cachedCalls   int
+	computedCalls int
+}
+
+func (m *mockCachingHintProvider) CachedHints(pod *v1.Pod, container *v1.Container) (map[string][]TopologyHint, bool) {
+	m.cachedCalls++
+	return m.cachedHints, m.cacheValid
+}
+
+func (m *mockCachingHintProvider) GetTopologyHints(pod *v1.Pod, container *v1.Container) map[string][]TopologyHint {
+	m.computedCalls++
+	return map[string][]TopologyHint{
+		"resource": {{NUMANodeAffinity: NewTestBitMask(1), Preferred: true}},
+	}
+}
+
+func (m *mockCachingHintProvider) GetPodTopologyHints(pod *v1.Pod) map[string][]TopologyHint {
+	return nil
+}
+
+func (m *mockCachingHintProvider) Allocate(pod *v1.Pod, container *v1.Container) error {
+	return nil
+}
+
+func TestContainerScopeCachedHints(t *testing.T) {
+	provider := &mockCachingHintProvider{
+		cachedHints: map[string][]TopologyHint{
+			"resource": {{NUMANodeAffinity: NewTestBitMask(0), Preferred: true}},
+		},
+		cacheValid: true,
+	}
+	scope := NewContainerScope(NewBestEffortPolicy(commonNUMAInfoTwoNodes(), PolicyOptions{})).(*containerScope)
+	scope.AddHintProvider(provider)
+
+	pod := &v1.Pod{}
+	container := &v1.Container{Name: "nginx"}
+
+	// With a valid cache the scope takes the cached hints and never asks the
+	// provider to recompute.
+	providersHints := scope.accumulateProvidersHints(pod, container)
+	if !reflect.DeepEqual(providersHints, []map[string][]TopologyHint{provider.cachedHints}) {
+		t.Errorf("Expected the cached hints to be used, got %v", providersHints)
+	}
+	if provider.cachedCalls != 1 || provider.computedCalls != 0 {
+		t.Errorf("Expected 1 cached call and 0 computed calls, got %d and %d", provider.cachedCalls, provider.computedCalls)
+	}
+
+	// Once the cache is invalid the scope falls back to GetTopologyHints.
+	provider.cacheValid = false
+	providersHints = scope.accumulateProvidersHints(pod, container)
+	if provider.computedCalls != 1 {
+		t.Errorf("Expected the hints to be recomputed with an invalid cache, got %d computed calls", provider.computedCalls)
+	}
+	expected := []map[string][]TopologyHint{
+		{"resource": {{NUMANodeAffinity: NewTestBitMask(1), Preferred: true}}},
+	}
+	if !reflect.DeepEqual(providersHints, expected) {
+		t.Errorf("Expected the recomputed hints to be used, got %v", providersHints)
+	}
+}
+
+func TestContainerScopeScoreHysteresis(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.EnhancedTopologyHints, true)
+
+	hopNear := 0
+	hopFar := 1
+	provider := &mockHintProvider{
+		map[string][]TopologyHint{
+			"resource": {
+				{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, HopCount: &hopFar},
+				{NUMANodeAffinity: NewTestBitMask(1), Preferred: true, HopCount: &hopNear},
+			},
+		},
+	}
+
+	pod := &v1.Pod{}
+	pod.UID = "fakePod"
+	pod.Spec.Containers = []v1.Container{{Name: "fakeContainer"}}
+
+	// The container sits on node 0 with score 10 from an earlier admission;
+	// the fresh merge now favors node 1 with score 0, an improvement of 10.
+	storedScore := 10.0
+	stored := TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, HopCount: &hopFar, Score: &storedScore}
+
+	newScope := func(hysteresis float64) *containerScope {
+		s := &containerScope{
+			scope{
+				name:             containerTopologyScope,
+				podTopologyHints: podTopologyHints{},
+				policy:           NewBestEffortPolicy(commonNUMAInfoTwoNodes(), PolicyOptions{ScoreOverridesPreference: true, ScoreHysteresis: hysteresis}),
+				podMap:           containermap.NewContainerMap(),
+			},
+		}
+		s.AddHintProvider(provider)
+		s.setTopologyHints(string(pod.UID), "fakeContainer", stored)
+		return s
+	}
+
+	// A sub-margin improvement keeps the stored node.
+	bestHint, admit := newScope(15.0).calculateAffinity(pod, &pod.Spec.Containers[0])
+	if !admit {
+		t.Fatalf("Expected admission, got %v", bestHint)
+	}
+	if !bestHint.NUMANodeAffinity.IsEqual(NewTestBitMask(0)) {
+		t.Errorf("Expected a sub-margin improvement to keep node 0, got %v", bestHint.NUMANodeAffinity)
+	}
+
+	// A supra-margin improvement moves the container.
+	bestHint, admit = newScope(5.0).calculateAffinity(pod, &pod.Spec.Containers[0])
+	if !admit {
+		t.Fatalf("Expected admission, got %v", bestHint)
+	}
+	if !bestHint.NUMANodeAffinity.IsEqual(NewTestBitMask(1)) {
+		t.Errorf("Expected a supra-margin improvement to move to node 1, got %v", bestHint.NUMANodeAffinity)
+	}
+}
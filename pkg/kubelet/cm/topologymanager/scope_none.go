@@ -17,13 +17,22 @@ limitations under the License.
 package topologymanager
 
 import (
+	"strconv"
+
 	"k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/kubelet/cm/containermap"
 	"k8s.io/kubernetes/pkg/kubelet/lifecycle"
+	"k8s.io/kubernetes/pkg/kubelet/metrics"
 )
 
 type noneScope struct {
 	scope
+	// shadowPolicy, when set, computes what the policy would have chosen for
+	// every admitted container without enforcing it, recording the would-be
+	// score and preference as metrics. Set from the none-policy-shadow-metrics
+	// policy option.
+	shadowPolicy Policy
 }
 
 // Ensure noneScope implements Scope interface
@@ -32,7 +41,7 @@ var _ Scope = &noneScope{}
 // NewNoneScope returns a none scope.
 func NewNoneScope() Scope {
 	return &noneScope{
-		scope{
+		scope: scope{
 			name:             noneTopologyScope,
 			podTopologyHints: podTopologyHints{},
 			policy:           NewNonePolicy(),
@@ -41,6 +50,42 @@ func NewNoneScope() Scope {
 	}
 }
 
+// NewNoneScopeWithShadowPolicy returns a none scope that additionally runs the
+// given policy's merge for every admitted container and records the outcome as
+// metrics, without it ever influencing admission.
+func NewNoneScopeWithShadowPolicy(shadowPolicy Policy) Scope {
+	scope := NewNoneScope().(*noneScope)
+	scope.shadowPolicy = shadowPolicy
+	return scope
+}
+
 func (s *noneScope) Admit(pod *v1.Pod) lifecycle.PodAdmitResult {
+	if s.shadowPolicy != nil {
+		s.recordShadowMerges(pod)
+	}
 	return s.admitPolicyNone(pod)
 }
+
+// recordShadowMerges merges the provider hints for each of the pod's
+// containers through the shadow policy and records the would-be placement as
+// metrics, so operators running the none policy can quantify what topology
+// management would have done. Containers no provider expresses a preference
+// for are skipped: an enforcing policy would admit them with a don't-care
+// affinity, so they carry no signal.
+func (s *noneScope) recordShadowMerges(pod *v1.Pod) {
+	for _, container := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+		var providersHints []map[string][]TopologyHint
+		for _, provider := range s.hintProviders {
+			providersHints = append(providersHints, provider.GetTopologyHints(pod, &container))
+		}
+		if providersHintsAreAgnostic(providersHints) {
+			continue
+		}
+		hint, _ := s.shadowPolicy.Merge(providersHints)
+		metrics.TopologyManagerShadowMergesTotal.WithLabelValues(strconv.FormatBool(hint.Preferred)).Inc()
+		if hint.HasScore() {
+			metrics.TopologyManagerShadowMergeScore.Observe(hint.GetScore())
+		}
+		klog.V(4).InfoS("Shadow topology merge under the none policy", "pod", klog.KObj(pod), "containerName", container.Name, "shadowHint", hint)
+	}
+}
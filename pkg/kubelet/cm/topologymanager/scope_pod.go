@@ -21,6 +21,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/kubelet/cm/admission"
 	"k8s.io/kubernetes/pkg/kubelet/cm/containermap"
+	"k8s.io/kubernetes/pkg/kubelet/cm/topologymanager/bitmask"
 	"k8s.io/kubernetes/pkg/kubelet/lifecycle"
 	"k8s.io/kubernetes/pkg/kubelet/metrics"
 )
@@ -40,6 +41,7 @@ func NewPodScope(policy Policy) Scope {
 			podTopologyHints: podTopologyHints{},
 			policy:           policy,
 			podMap:           containermap.NewContainerMap(),
+			mergeCache:       newMergeResultCache(),
 		},
 	}
 }
@@ -53,12 +55,17 @@ func (s *podScope) Admit(pod *v1.Pod) lifecycle.PodAdmitResult {
 			metrics.ContainerAlignedComputeResourcesFailure.WithLabelValues(metrics.AlignScopePod, metrics.AlignedNUMANode).Inc()
 		}
 		metrics.TopologyManagerAdmissionErrorsTotal.Inc()
-		return admission.GetPodAdmitResult(&TopologyAffinityError{})
+		// Re-merge each resource on its own so the error can name the ones
+		// that could not be aligned; admission failures are rare enough that
+		// the extra provider round-trip does not matter.
+		unalignable := unalignableResources(s.policy, s.accumulateProvidersHints(pod))
+		return admission.GetPodAdmitResult(&TopologyAffinityError{UnalignableResources: unalignable})
 	}
 
 	for _, container := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
-		klog.InfoS("Topology Affinity", "bestHint", bestHint, "pod", klog.KObj(pod), "containerName", container.Name)
-		s.setTopologyHints(string(pod.UID), container.Name, bestHint)
+		containerHint := s.refineContainerAffinity(pod, &container, bestHint)
+		klog.InfoS("Topology Affinity", "bestHint", containerHint, "pod", klog.KObj(pod), "containerName", container.Name)
+		s.setTopologyHints(string(pod.UID), container.Name, containerHint)
 
 		err := s.allocateAlignedResources(pod, &container)
 		if err != nil {
@@ -78,6 +85,13 @@ func (s *podScope) accumulateProvidersHints(pod *v1.Pod) []map[string][]Topology
 	var providersHints []map[string][]TopologyHint
 
 	for _, provider := range s.hintProviders {
+		// Skip providers that report themselves as unhealthy, so their
+		// stale or empty hints do not influence merging.
+		if !isProviderHealthy(provider) {
+			klog.InfoS("Skipping unhealthy hint provider", "pod", klog.KObj(pod))
+			metrics.TopologyManagerSkippedUnhealthyProvidersTotal.Inc()
+			continue
+		}
 		// Get the TopologyHints for a Pod from a provider.
 		hints := provider.GetPodTopologyHints(pod)
 		providersHints = append(providersHints, hints)
@@ -86,9 +100,69 @@ func (s *podScope) accumulateProvidersHints(pod *v1.Pod) []map[string][]Topology
 	return providersHints
 }
 
+// refineContainerAffinity narrows the pod-level hint for a single container
+// using the providers' container-level hints, so a pod mixing pod-scoped
+// resources (e.g. shared memory) with container-scoped ones (e.g. per-container
+// devices) can give each container a tighter affinity than the pod-wide union.
+//
+// The pod-level hint always takes precedence: only container-level hints whose
+// affinity falls entirely within the pod affinity participate in the refining
+// merge, so a container can never be placed outside the NUMA nodes committed
+// for the pod. When no container-level hints fit within the pod affinity, or
+// the refining merge does not admit, the pod-level hint is used unchanged.
+func (s *podScope) refineContainerAffinity(pod *v1.Pod, container *v1.Container, podHint TopologyHint) TopologyHint {
+	if podHint.NUMANodeAffinity == nil {
+		return podHint
+	}
+
+	var providersHints []map[string][]TopologyHint
+	for _, provider := range s.hintProviders {
+		if !isProviderHealthy(provider) {
+			continue
+		}
+		hints := provider.GetTopologyHints(pod, container)
+		filtered := make(map[string][]TopologyHint)
+		for resource, resourceHints := range hints {
+			// A nil hint list means the resource has no topology preference;
+			// keep it as-is so the merge treats it that way.
+			if resourceHints == nil {
+				filtered[resource] = nil
+				continue
+			}
+			kept := []TopologyHint{}
+			for _, hint := range resourceHints {
+				if hint.NUMANodeAffinity == nil || bitmask.And(hint.NUMANodeAffinity, podHint.NUMANodeAffinity).IsEqual(hint.NUMANodeAffinity) {
+					kept = append(kept, hint)
+				}
+			}
+			// A resource whose hints all fall outside the pod affinity cannot
+			// refine the placement; the pod-level hint wins.
+			if len(kept) == 0 {
+				return podHint
+			}
+			filtered[resource] = kept
+		}
+		if len(filtered) > 0 {
+			providersHints = append(providersHints, filtered)
+		}
+	}
+
+	// Without container-level hints there is nothing to refine.
+	if len(providersHints) == 0 {
+		return podHint
+	}
+
+	refined, admit := s.policy.Merge(providersHints)
+	if !admit || refined.NUMANodeAffinity == nil {
+		return podHint
+	}
+	klog.V(4).InfoS("Refined pod-scope affinity for container", "pod", klog.KObj(pod), "containerName", container.Name, "podHint", podHint, "containerHint", refined)
+	return refined
+}
+
 func (s *podScope) calculateAffinity(pod *v1.Pod) (TopologyHint, bool) {
 	providersHints := s.accumulateProvidersHints(pod)
-	bestHint, admit := s.policy.Merge(providersHints)
+	bestHint, admit := s.mergeProvidersHints(pod, providersHints)
 	klog.InfoS("PodTopologyHint", "bestHint", bestHint, "pod", klog.KObj(pod))
 	return bestHint, admit
 }
@@ -21,6 +21,11 @@ import (
 	"testing"
 
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	pkgfeatures "k8s.io/kubernetes/pkg/features"
+	"k8s.io/kubernetes/pkg/kubelet/cm/containermap"
 )
 
 func TestPodCalculateAffinity(t *testing.T) {
@@ -35,17 +40,19 @@ func TestPodCalculateAffinity(t *testing.T) {
 			expected: ([]map[string][]TopologyHint)(nil),
 		},
 		{
+			// Agnostic hints short-circuit admission, so the merger never
+			// sees them.
 			name: "HintProvider returns empty non-nil map[string][]TopologyHint",
 			hp: []HintProvider{
 				&mockHintProvider{
 					map[string][]TopologyHint{},
 				},
 			},
-			expected: []map[string][]TopologyHint{
-				{},
-			},
+			expected: ([]map[string][]TopologyHint)(nil),
 		},
 		{
+			// Agnostic hints short-circuit admission, so the merger never
+			// sees them.
 			name: "HintProvider returns -nil map[string][]TopologyHint from provider",
 			hp: []HintProvider{
 				&mockHintProvider{
@@ -54,11 +61,7 @@ func TestPodCalculateAffinity(t *testing.T) {
 					},
 				},
 			},
-			expected: []map[string][]TopologyHint{
-				{
-					"resource": nil,
-				},
-			},
+			expected: ([]map[string][]TopologyHint)(nil),
 		},
 		{
 			name: "Assorted HintProviders",
@@ -266,3 +269,186 @@ func TestPodAccumulateProvidersHints(t *testing.T) {
 		}
 	}
 }
+
+// mockSplitScopeHintProvider reports different hints at pod and container
+// scope, keyed by container name for the latter.
+type mockSplitScopeHintProvider struct {
+	podHints       map[string][]TopologyHint
+	containerHints map[string]map[string][]TopologyHint
+}
+
+func (m *mockSplitScopeHintProvider) GetTopologyHints(pod *v1.Pod, container *v1.Container) map[string][]TopologyHint {
+	return m.containerHints[container.Name]
+}
+
+func (m *mockSplitScopeHintProvider) GetPodTopologyHints(pod *v1.Pod) map[string][]TopologyHint {
+	return m.podHints
+}
+
+func (m *mockSplitScopeHintProvider) Allocate(pod *v1.Pod, container *v1.Container) error {
+	return nil
+}
+
+func TestPodScopeRefinesContainerAffinity(t *testing.T) {
+	// A pod-scoped memory hint spans both NUMA nodes, while each container's
+	// device sits on a single node. The pod-level merge commits {0,1} and the
+	// per-container refinement narrows each container to its device's node.
+	memoryProvider := &mockSplitScopeHintProvider{
+		podHints: map[string][]TopologyHint{
+			"memory": {{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: true}},
+		},
+	}
+	deviceProvider := &mockSplitScopeHintProvider{
+		podHints: map[string][]TopologyHint{
+			"gpu": {{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: true}},
+		},
+		containerHints: map[string]map[string][]TopologyHint{
+			"fakeContainer1": {
+				"gpu": {{NUMANodeAffinity: NewTestBitMask(0), Preferred: true}},
+			},
+			"fakeContainer2": {
+				"gpu": {{NUMANodeAffinity: NewTestBitMask(1), Preferred: true}},
+			},
+		},
+	}
+
+	pScope := &podScope{
+		scope{
+			name:             podTopologyScope,
+			podTopologyHints: podTopologyHints{},
+			policy:           NewBestEffortPolicy(commonNUMAInfoTwoNodes(), PolicyOptions{}),
+			podMap:           containermap.NewContainerMap(),
+			hintProviders:    []HintProvider{memoryProvider, deviceProvider},
+		},
+	}
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			UID: "fakePod",
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{Name: "fakeContainer1"},
+				{Name: "fakeContainer2"},
+			},
+		},
+	}
+
+	if result := pScope.Admit(pod); !result.Admit {
+		t.Fatalf("Expected pod to be admitted, got %v", result)
+	}
+
+	affinity1 := pScope.GetAffinity(string(pod.UID), "fakeContainer1")
+	if !affinity1.NUMANodeAffinity.IsEqual(NewTestBitMask(0)) {
+		t.Errorf("Expected container 1 affinity to be refined to %v, got %v", NewTestBitMask(0), affinity1.NUMANodeAffinity)
+	}
+	affinity2 := pScope.GetAffinity(string(pod.UID), "fakeContainer2")
+	if !affinity2.NUMANodeAffinity.IsEqual(NewTestBitMask(1)) {
+		t.Errorf("Expected container 2 affinity to be refined to %v, got %v", NewTestBitMask(1), affinity2.NUMANodeAffinity)
+	}
+}
+
+func TestPodScopeRefinementRespectsPodAffinity(t *testing.T) {
+	// Container-level hints pointing outside the committed pod affinity
+	// cannot refine it; the container keeps the pod-level hint.
+	provider := &mockSplitScopeHintProvider{
+		podHints: map[string][]TopologyHint{
+			"gpu": {{NUMANodeAffinity: NewTestBitMask(0), Preferred: true}},
+		},
+		containerHints: map[string]map[string][]TopologyHint{
+			"fakeContainer": {
+				"gpu": {{NUMANodeAffinity: NewTestBitMask(1), Preferred: true}},
+			},
+		},
+	}
+
+	pScope := &podScope{
+		scope{
+			name:             podTopologyScope,
+			podTopologyHints: podTopologyHints{},
+			policy:           NewBestEffortPolicy(commonNUMAInfoTwoNodes(), PolicyOptions{}),
+			podMap:           containermap.NewContainerMap(),
+			hintProviders:    []HintProvider{provider},
+		},
+	}
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			UID: "fakePod",
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{Name: "fakeContainer"},
+			},
+		},
+	}
+
+	if result := pScope.Admit(pod); !result.Admit {
+		t.Fatalf("Expected pod to be admitted, got %v", result)
+	}
+
+	affinity := pScope.GetAffinity(string(pod.UID), "fakeContainer")
+	if !affinity.NUMANodeAffinity.IsEqual(NewTestBitMask(0)) {
+		t.Errorf("Expected the pod-level affinity %v to win, got %v", NewTestBitMask(0), affinity.NUMANodeAffinity)
+	}
+}
+
+func TestPodScopeAdmitCarriesEnhancedFields(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.EnhancedTopologyHints, true)
+
+	hopCount := 0
+	distance := 10
+	bandwidth := 80.0
+	score := CalculateTopologyScore(hopCount, distance, bandwidth, 0, PolicyOptions{})
+
+	enhancedHint := TopologyHint{
+		NUMANodeAffinity: NewTestBitMask(0),
+		Preferred:        true,
+		HopCount:         &hopCount,
+		Distance:         &distance,
+		Bandwidth:        &bandwidth,
+		Score:            &score,
+	}
+
+	pScope := &podScope{
+		scope{
+			name:             podTopologyScope,
+			podTopologyHints: podTopologyHints{},
+			policy:           NewBestEffortPolicy(commonNUMAInfoTwoNodes(), PolicyOptions{}),
+			podMap:           containermap.NewContainerMap(),
+			hintProviders: []HintProvider{
+				&mockHintProvider{
+					map[string][]TopologyHint{
+						"resource": {
+							enhancedHint,
+							{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: false},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			UID: "fakePod",
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{Name: "fakeContainer"},
+			},
+		},
+	}
+
+	if result := pScope.Admit(pod); !result.Admit {
+		t.Fatalf("Expected pod to be admitted, got %v", result)
+	}
+
+	affinity := pScope.GetAffinity(string(pod.UID), "fakeContainer")
+	if !affinity.NUMANodeAffinity.IsEqual(NewTestBitMask(0)) {
+		t.Errorf("Expected committed affinity mask to be %v, got %v", NewTestBitMask(0), affinity.NUMANodeAffinity)
+	}
+	if affinity.HopCount == nil || affinity.Distance == nil || affinity.Bandwidth == nil || affinity.Score == nil {
+		t.Errorf("Expected committed affinity to carry enhanced fields, got %#v", affinity)
+	}
+}
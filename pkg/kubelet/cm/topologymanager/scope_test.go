@@ -17,10 +17,14 @@ limitations under the License.
 package topologymanager
 
 import (
+	"errors"
+	"fmt"
 	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/kubernetes/pkg/kubelet/cm/containermap"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -82,6 +86,175 @@ func TestAddContainer(t *testing.T) {
 	}
 }
 
+type mockDeallocatableHintProvider struct {
+	mockHintProvider
+	allocateError error
+	allocated     bool
+	deallocated   bool
+}
+
+func (m *mockDeallocatableHintProvider) Allocate(pod *v1.Pod, container *v1.Container) error {
+	if m.allocateError != nil {
+		return m.allocateError
+	}
+	m.allocated = true
+	return nil
+}
+
+func (m *mockDeallocatableHintProvider) Deallocate(pod *v1.Pod, container *v1.Container) error {
+	m.deallocated = true
+	return nil
+}
+
+func TestAllocateAlignedResourcesRollback(t *testing.T) {
+	first := &mockDeallocatableHintProvider{}
+	second := &mockDeallocatableHintProvider{allocateError: fmt.Errorf("fake allocation failure")}
+	third := &mockDeallocatableHintProvider{}
+
+	scope := scope{}
+	scope.hintProviders = []HintProvider{first, second, third}
+
+	err := scope.allocateAlignedResources(&v1.Pod{}, &v1.Container{Name: "nginx"})
+	if err == nil || !errors.Is(err, second.allocateError) {
+		t.Fatalf("Expected the error to wrap the original allocation error, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "mockDeallocatableHintProvider") || !strings.Contains(err.Error(), "nginx") {
+		t.Fatalf("Expected the error to name the failing provider and container, got: %v", err)
+	}
+	if !first.deallocated {
+		t.Errorf("Expected the first provider to be rolled back")
+	}
+	if second.deallocated {
+		t.Errorf("Expected the failing provider not to be rolled back")
+	}
+	if third.allocated || third.deallocated {
+		t.Errorf("Expected the third provider to never be invoked")
+	}
+}
+
+func TestAdmitSurfacesAllocateErrorContext(t *testing.T) {
+	provider := &mockDeallocatableHintProvider{allocateError: fmt.Errorf("not enough cores for resource %q", "cpu")}
+	scope := NewContainerScope(NewNonePolicy())
+	scope.AddHintProvider(provider)
+
+	pod := &v1.Pod{}
+	pod.UID = "0aafa4c4-38e8-11e9-bcb1-a4bf01040474"
+	pod.Spec.Containers = []v1.Container{{Name: "web"}}
+
+	result := scope.Admit(pod)
+	if result.Admit {
+		t.Fatalf("Expected admission to fail")
+	}
+	for _, want := range []string{"mockDeallocatableHintProvider", `"web"`, `resource "cpu"`} {
+		if !strings.Contains(result.Message, want) {
+			t.Errorf("Expected the admit message to contain %q, got: %q", want, result.Message)
+		}
+	}
+}
+
+func TestResourceDataSizes(t *testing.T) {
+	pod := &v1.Pod{}
+	pod.Annotations = map[string]string{
+		DataSizeAnnotationPrefix + "example.com~1gpu": "1000000000",
+		DataSizeAnnotationPrefix + "hugepages-2Mi":    "2000000",
+		DataSizeAnnotationPrefix + "example.com~1bad": "not a number",
+		"unrelated-annotation":                        "42",
+	}
+
+	sizes := resourceDataSizes(pod)
+	expected := map[string]int64{
+		"example.com/gpu": 1000000000,
+		"hugepages-2Mi":   2000000,
+	}
+	if !reflect.DeepEqual(sizes, expected) {
+		t.Errorf("Expected data sizes to be %v, got %v", expected, sizes)
+	}
+
+	if sizes := resourceDataSizes(&v1.Pod{}); sizes != nil {
+		t.Errorf("Expected no data sizes without annotations, got %v", sizes)
+	}
+}
+
+func TestMergeProvidersHintsBandwidthRequest(t *testing.T) {
+	bandwidthLow := 20.0
+	bandwidthHigh := 100.0
+
+	providersHints := func() []map[string][]TopologyHint {
+		return []map[string][]TopologyHint{
+			{
+				"resource": {
+					{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, Bandwidth: &bandwidthLow},
+					{NUMANodeAffinity: NewTestBitMask(1), Preferred: true, Bandwidth: &bandwidthHigh},
+				},
+			},
+		}
+	}
+
+	podWithRequest := &v1.Pod{
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name: "nginx",
+					Resources: v1.ResourceRequirements{
+						Requests: v1.ResourceList{
+							InterconnectBandwidthResourceName: resource.MustParse("50"),
+						},
+					},
+				},
+			},
+		},
+	}
+	podWithoutRequest := &v1.Pod{
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{Name: "nginx"}},
+		},
+	}
+
+	numaInfo := commonNUMAInfoTwoNodes()
+
+	// Without a bandwidth request both hints stay preferred and the
+	// lower-numbered node wins.
+	s := &scope{policy: NewBestEffortPolicy(numaInfo, PolicyOptions{})}
+	hint, admit := s.mergeProvidersHints(podWithoutRequest, providersHints())
+	if !admit || !hint.NUMANodeAffinity.IsEqual(NewTestBitMask(0)) || !hint.Preferred {
+		t.Errorf("Expected node 0 to win without a bandwidth request, got %v (admit %v)", hint, admit)
+	}
+
+	// With a request of 50 GB/s the 20 GB/s hint is demoted and the
+	// high-bandwidth placement wins.
+	hint, admit = s.mergeProvidersHints(podWithRequest, providersHints())
+	if !admit || !hint.NUMANodeAffinity.IsEqual(NewTestBitMask(1)) || !hint.Preferred {
+		t.Errorf("Expected the high-bandwidth node 1 to win with a bandwidth request, got %v (admit %v)", hint, admit)
+	}
+
+	// Under the restricted policy a pod whose request no hint can meet is
+	// rejected rather than placed on an inadequate interconnect.
+	lowOnly := []map[string][]TopologyHint{
+		{
+			"resource": {
+				{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, Bandwidth: &bandwidthLow},
+			},
+		},
+	}
+	s = &scope{policy: NewRestrictedPolicy(numaInfo, PolicyOptions{})}
+	if hint, admit := s.mergeProvidersHints(podWithRequest, lowOnly); admit {
+		t.Errorf("Expected restricted to reject when no hint meets the bandwidth request, got %v", hint)
+	}
+
+	// Hints without a bandwidth metric are not demoted: absence means the
+	// provider does not know, not that the placement is inadequate.
+	noMetric := []map[string][]TopologyHint{
+		{
+			"resource": {
+				{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+			},
+		},
+	}
+	if hint, admit := s.mergeProvidersHints(podWithRequest, noMetric); !admit || !hint.Preferred {
+		t.Errorf("Expected hints without a bandwidth metric to stay preferred, got %v (admit %v)", hint, admit)
+	}
+}
+
 func TestRemoveContainer(t *testing.T) {
 	testCases := []struct {
 		name        string
@@ -125,3 +298,41 @@ func TestRemoveContainer(t *testing.T) {
 	}
 
 }
+
+func TestAdmitNamesUnalignableResources(t *testing.T) {
+	// cpu and memory align fine on node 0; only the gpu has no preferred
+	// placement, so the restricted policy rejects the pod and the error must
+	// single out the gpu.
+	provider := &mockHintProvider{
+		map[string][]TopologyHint{
+			"cpu": {
+				{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+			},
+			"memory": {
+				{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+			},
+			"example.com/gpu": {
+				{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: false},
+			},
+		},
+	}
+	scope := NewContainerScope(NewRestrictedPolicy(commonNUMAInfoTwoNodes(), PolicyOptions{}))
+	scope.AddHintProvider(provider)
+
+	pod := &v1.Pod{}
+	pod.UID = "0aafa4c4-38e8-11e9-bcb1-a4bf01040474"
+	pod.Spec.Containers = []v1.Container{{Name: "web"}}
+
+	result := scope.Admit(pod)
+	if result.Admit {
+		t.Fatalf("Expected admission to fail")
+	}
+	if !strings.Contains(result.Message, "example.com/gpu") {
+		t.Errorf("Expected the admit message to name the gpu, got: %q", result.Message)
+	}
+	for _, aligned := range []string{"cpu", "memory"} {
+		if strings.Contains(result.Message, aligned) {
+			t.Errorf("Expected the admit message not to blame %q, got: %q", aligned, result.Message)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"math"
+
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/kubernetes/pkg/features"
+)
+
+const (
+	// UnreachableNUMADistance is the ACPI SLIT sentinel some firmware
+	// reports to mean there is no direct path between two NUMA nodes.
+	UnreachableNUMADistance = 255
+
+	// DefaultLocalDistance is the ACPI SLIT convention for the distance of
+	// a NUMA node to itself. Distances above it indicate remote access. It
+	// is the baseline scoring uses unless the local-numa-distance policy
+	// option configures a different one.
+	DefaultLocalDistance = 10
+
+	// scoreHopPenalty is the score cost added per interconnect hop.
+	scoreHopPenalty = 10.0
+
+	// scoreDistancePenalty is the score cost added per distance point
+	// above the local distance.
+	scoreDistancePenalty = 1.0
+
+	// defaultScoreRoundingDecimals disables score rounding, preserving the
+	// full precision of computed scores. It can be overridden with the
+	// score-rounding-decimals policy option.
+	defaultScoreRoundingDecimals = -1
+)
+
+// LocalDistance returns the distance baseline scoring treats as local: the
+// configured local-numa-distance policy option when set, or the ACPI SLIT
+// default. A zero-valued PolicyOptions therefore scores the same as an
+// unconfigured one. Providers that synthesize distances from hop counts
+// should build on this baseline.
+func (o PolicyOptions) LocalDistance() int {
+	if o.LocalNUMADistance > 0 {
+		return o.LocalNUMADistance
+	}
+	return DefaultLocalDistance
+}
+
+// roundMetric rounds v to the number of decimal places configured by the
+// score-rounding-decimals policy option. It returns v unchanged when fewer
+// than one decimal is requested (rounding disabled, including for a
+// zero-valued PolicyOptions) or v is not finite, so the unreachable sentinel
+// keeps its +Inf score.
+func roundMetric(v float64, decimals int) float64 {
+	if decimals < 1 || math.IsInf(v, 0) {
+		return v
+	}
+	factor := math.Pow(10, float64(decimals))
+	return math.Round(v*factor) / factor
+}
+
+// scoringDistance returns the distance scoring should use for the hint: the
+// provider-supplied one, or the configured local baseline when the provider
+// did not supply any, so a hint without the metric never draws a distance
+// penalty regardless of the baseline in effect.
+func (th *TopologyHint) scoringDistance(opts PolicyOptions) int {
+	if th.Distance == nil {
+		return opts.LocalDistance()
+	}
+	return *th.Distance
+}
+
+// CalculateTopologyScore computes a composite placement cost for an
+// allocation with the given interconnect metrics. Lower is better.
+//
+// The score is the sum of:
+//   - a hop term penalizing each interconnect hop,
+//   - a distance term penalizing distances above the local distance,
+//   - a bandwidth term estimating the transfer time in milliseconds for
+//     dataSize bytes at the given bandwidth in GB/s.
+//
+// A bandwidth of 0 means either "unknown" or a fully isolated link with no
+// shared bandwidth; in both cases the transfer time cannot be estimated, so
+// the bandwidth term is skipped rather than divided by zero. The same applies
+// when no data size was supplied, so a purely local allocation scores 0.
+// Negative bandwidths are treated like 0. A distance at or above
+// UnreachableNUMADistance yields +Inf. The local distance baseline and the
+// rounding precision come from opts; a zero-valued PolicyOptions uses the
+// defaults.
+func CalculateTopologyScore(hopCount int, distance int, bandwidth float64, dataSize int64, opts PolicyOptions) float64 {
+	// A distance at or above the unreachable sentinel means the nodes have
+	// no direct path; treat such spans as infinitely expensive rather than
+	// merely distant.
+	if distance >= UnreachableNUMADistance {
+		return math.Inf(1)
+	}
+	score := float64(hopCount) * scoreHopPenalty
+	if localDistance := opts.LocalDistance(); distance > localDistance {
+		score += float64(distance-localDistance) * scoreDistancePenalty
+	}
+	if bandwidth > 0 && dataSize > 0 {
+		score += float64(dataSize) / (bandwidth * 1e9) * 1e3
+	}
+	return roundMetric(score, opts.ScoreRoundingDecimals)
+}
+
+// CalculateTopologyScoreWithDataSizes computes the same composite cost as
+// CalculateTopologyScore, but with one bandwidth term per resource, each
+// using that resource's estimated data size. The per-resource transfer
+// times are summed, so a pod moving data through several resources pays
+// for each of them. An empty map yields the same score as a zero data size.
+func CalculateTopologyScoreWithDataSizes(hopCount int, distance int, bandwidth float64, dataSizes map[string]int64, opts PolicyOptions) float64 {
+	score := CalculateTopologyScore(hopCount, distance, bandwidth, 0, opts)
+	for _, dataSize := range dataSizes {
+		if bandwidth > 0 && dataSize > 0 {
+			score += float64(dataSize) / (bandwidth * 1e9) * 1e3
+		}
+	}
+	return roundMetric(score, opts.ScoreRoundingDecimals)
+}
+
+// UpdateScoreWithDataSizes recomputes the hint's Score from its enhanced
+// fields and per-resource data sizes under the given policy options. Like
+// UpdateScore, it is a no-op for hints without enhanced fields.
+func (th *TopologyHint) UpdateScoreWithDataSizes(dataSizes map[string]int64, opts PolicyOptions) {
+	if !th.hasEnhancedFields() {
+		return
+	}
+	score := CalculateTopologyScoreWithDataSizes(th.GetHopCount(), th.scoringDistance(opts), th.GetBandwidth(), dataSizes, opts)
+	th.Score = &score
+}
+
+// UpdateScore recomputes the hint's Score from its enhanced fields and the
+// estimated amount of data (in bytes) the workload moves across the
+// interconnect, under the given policy options. It is a no-op for hints
+// without enhanced fields, so basic hints never gain a score.
+func (th *TopologyHint) UpdateScore(dataSize int64, opts PolicyOptions) {
+	if !th.hasEnhancedFields() {
+		return
+	}
+	score := CalculateTopologyScore(th.GetHopCount(), th.scoringDistance(opts), th.GetBandwidth(), dataSize, opts)
+	th.Score = &score
+}
+
+// ScoreComponents recomputes the individual terms of the scoring formula for
+// the hint's enhanced fields, so logs and debugging tools can explain a score
+// instead of reverse-engineering it. latency is the unreachable term: +Inf
+// when the hint's distance is at or above UnreachableNUMADistance, and 0
+// otherwise. hop and distance are the per-hop and above-local-distance
+// penalty terms, and bandwidth is the estimated transfer time in milliseconds
+// for dataSize bytes. The components sum to the score CalculateTopologyScore
+// computes from the same inputs and options, up to the configured score
+// rounding, which applies to the sum rather than each term. Every component
+// is 0 when the EnhancedTopologyHints feature gate is disabled or the hint
+// carries no enhanced fields, matching GetScore's 0 default for unscored
+// hints.
+func (th *TopologyHint) ScoreComponents(dataSize int64, opts PolicyOptions) (latency, hop, distance, bandwidth float64) {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.EnhancedTopologyHints) || !th.hasEnhancedFields() {
+		return 0, 0, 0, 0
+	}
+	if th.scoringDistance(opts) >= UnreachableNUMADistance {
+		return math.Inf(1), 0, 0, 0
+	}
+	hop = float64(th.GetHopCount()) * scoreHopPenalty
+	if d, localDistance := th.scoringDistance(opts), opts.LocalDistance(); d > localDistance {
+		distance = float64(d-localDistance) * scoreDistancePenalty
+	}
+	if b := th.GetBandwidth(); b > 0 && dataSize > 0 {
+		bandwidth = float64(dataSize) / (b * 1e9) * 1e3
+	}
+	return latency, hop, distance, bandwidth
+}
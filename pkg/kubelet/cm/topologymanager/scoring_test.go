@@ -0,0 +1,336 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"math"
+	"testing"
+
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"k8s.io/kubernetes/pkg/features"
+)
+
+func TestCalculateTopologyScore(t *testing.T) {
+	tcases := []struct {
+		name      string
+		hopCount  int
+		distance  int
+		bandwidth float64
+		dataSize  int64
+		expected  float64
+	}{
+		{
+			name:     "local allocation scores zero",
+			distance: DefaultLocalDistance,
+			expected: 0,
+		},
+		{
+			name:     "hop and distance terms accumulate",
+			hopCount: 2,
+			distance: 21,
+			expected: 2*scoreHopPenalty + 11*scoreDistancePenalty,
+		},
+		{
+			name:      "bandwidth term estimates transfer time",
+			distance:  DefaultLocalDistance,
+			bandwidth: 1,
+			dataSize:  1e9,
+			expected:  1e3,
+		},
+		{
+			name:      "zero bandwidth isolation link skips the bandwidth term",
+			hopCount:  1,
+			distance:  DefaultLocalDistance,
+			bandwidth: 0,
+			dataSize:  1e9,
+			expected:  scoreHopPenalty,
+		},
+		{
+			name:      "negative bandwidth is treated like zero",
+			distance:  DefaultLocalDistance,
+			bandwidth: -5,
+			dataSize:  1e9,
+			expected:  0,
+		},
+		{
+			name:     "unreachable distance yields infinity",
+			distance: UnreachableNUMADistance,
+			expected: math.Inf(1),
+		},
+	}
+
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			score := CalculateTopologyScore(tcase.hopCount, tcase.distance, tcase.bandwidth, tcase.dataSize, PolicyOptions{})
+			if score != tcase.expected {
+				t.Errorf("Expected score to be %v, got %v", tcase.expected, score)
+			}
+		})
+	}
+}
+
+func TestNonDefaultLocalDistance(t *testing.T) {
+	opts := PolicyOptions{LocalNUMADistance: 20}
+
+	// A distance at the raised baseline is local and costs nothing, even
+	// though it is above the ACPI convention of 10.
+	if score := CalculateTopologyScore(0, 20, 0, 0, opts); score != 0 {
+		t.Errorf("Expected a distance at the baseline to score 0, got %v", score)
+	}
+
+	// Only the margin above the baseline is charged.
+	if score := CalculateTopologyScore(0, 31, 0, 0, opts); score != 11*scoreDistancePenalty {
+		t.Errorf("Expected score to be %v, got %v", 11*scoreDistancePenalty, score)
+	}
+
+	// Hints without a distance default to the baseline and stay local.
+	hint := TopologyHint{}
+	if hint.scoringDistance(opts) != 20 {
+		t.Errorf("Expected the default distance to follow the baseline, got %v", hint.scoringDistance(opts))
+	}
+	if opts.LocalDistance() != 20 {
+		t.Errorf("Expected LocalDistance to report the baseline, got %v", opts.LocalDistance())
+	}
+	// An unconfigured baseline falls back to the ACPI convention.
+	if (PolicyOptions{}).LocalDistance() != DefaultLocalDistance {
+		t.Errorf("Expected LocalDistance to default to %v, got %v", DefaultLocalDistance, (PolicyOptions{}).LocalDistance())
+	}
+}
+
+func TestScoreRounding(t *testing.T) {
+	// Without rounding the bandwidth term keeps its full fractional tail:
+	// 1 GB at 3 GB/s is 333.333... ms.
+	unrounded := CalculateTopologyScore(0, DefaultLocalDistance, 3, 1e9, PolicyOptions{ScoreRoundingDecimals: defaultScoreRoundingDecimals})
+	if unrounded == 333.33 {
+		t.Fatalf("Expected the unrounded score to keep its fractional tail, got %v", unrounded)
+	}
+
+	rounding := PolicyOptions{ScoreRoundingDecimals: 2}
+	if score := CalculateTopologyScore(0, DefaultLocalDistance, 3, 1e9, rounding); score != 333.33 {
+		t.Errorf("Expected the score to be rounded to 333.33, got %v", score)
+	}
+
+	// Rounding must preserve the ordering of scores further apart than the
+	// configured precision.
+	better := CalculateTopologyScore(1, DefaultLocalDistance, 3, 1e9, rounding)
+	worse := CalculateTopologyScore(2, DefaultLocalDistance, 3, 1e9, rounding)
+	if better >= worse {
+		t.Errorf("Expected the one-hop score %v to stay below the two-hop score %v after rounding", better, worse)
+	}
+
+	// The unreachable sentinel keeps its infinite score.
+	if score := CalculateTopologyScore(0, UnreachableNUMADistance, 0, 0, rounding); !math.IsInf(score, 1) {
+		t.Errorf("Expected an unreachable distance to stay infinite, got %v", score)
+	}
+
+	// UpdateScore goes through the same rounding.
+	hopCount := 0
+	distance := DefaultLocalDistance
+	bandwidth := 3.0
+	hint := TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, HopCount: &hopCount, Distance: &distance, Bandwidth: &bandwidth}
+	hint.UpdateScore(1e9, rounding)
+	if hint.GetScore() != 333.33 {
+		t.Errorf("Expected UpdateScore to round the score to 333.33, got %v", hint.GetScore())
+	}
+}
+
+func TestUpdateScoreWithDataSizes(t *testing.T) {
+	hopCount := 1
+	distance := DefaultLocalDistance
+	bandwidth := 1.0
+	newHint := func() TopologyHint {
+		return TopologyHint{
+			NUMANodeAffinity: NewTestBitMask(0),
+			Preferred:        true,
+			HopCount:         &hopCount,
+			Distance:         &distance,
+			Bandwidth:        &bandwidth,
+		}
+	}
+
+	// Each resource pays its own transfer time: 1 GB and 2 GB at 1 GB/s are
+	// 1000 ms and 2000 ms on top of the hop term.
+	hint := newHint()
+	hint.UpdateScoreWithDataSizes(map[string]int64{
+		"example.com/gpu": 1e9,
+		"example.com/nic": 2e9,
+	}, PolicyOptions{})
+	expected := scoreHopPenalty + 1e3 + 2e3
+	if hint.GetScore() != expected {
+		t.Errorf("Expected score to be %v, got %v", expected, hint.GetScore())
+	}
+
+	// An absent map behaves like the single-scalar path with no data.
+	withoutSizes := newHint()
+	withoutSizes.UpdateScoreWithDataSizes(nil, PolicyOptions{})
+	scalar := newHint()
+	scalar.UpdateScore(0, PolicyOptions{})
+	if withoutSizes.GetScore() != scalar.GetScore() {
+		t.Errorf("Expected empty data sizes to score like no data, got %v and %v", withoutSizes.GetScore(), scalar.GetScore())
+	}
+}
+
+func TestUpdateScoreZeroBandwidth(t *testing.T) {
+	hopCount := 1
+	distance := 20
+	bandwidth := 0.0
+	hint := TopologyHint{
+		NUMANodeAffinity: NewTestBitMask(0),
+		Preferred:        true,
+		HopCount:         &hopCount,
+		Distance:         &distance,
+		Bandwidth:        &bandwidth,
+	}
+
+	hint.UpdateScore(1e9, PolicyOptions{})
+
+	if hint.Score == nil {
+		t.Fatalf("Expected score to be set")
+	}
+	if math.IsInf(*hint.Score, 0) || math.IsNaN(*hint.Score) {
+		t.Fatalf("Expected a finite score with zero bandwidth, got %v", *hint.Score)
+	}
+	expected := scoreHopPenalty + float64(distance-DefaultLocalDistance)*scoreDistancePenalty
+	if *hint.Score != expected {
+		t.Errorf("Expected score to be %v, got %v", expected, *hint.Score)
+	}
+}
+
+func TestScoreComponents(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.EnhancedTopologyHints, true)
+
+	newHint := func(hopCount, distance int, bandwidth float64) TopologyHint {
+		return TopologyHint{
+			NUMANodeAffinity: NewTestBitMask(0),
+			Preferred:        true,
+			HopCount:         &hopCount,
+			Distance:         &distance,
+			Bandwidth:        &bandwidth,
+		}
+	}
+
+	tcases := []struct {
+		name     string
+		hint     TopologyHint
+		dataSize int64
+	}{
+		{
+			name: "local allocation",
+			hint: newHint(0, DefaultLocalDistance, 0),
+		},
+		{
+			name:     "remote allocation with data movement",
+			hint:     newHint(2, 21, 4),
+			dataSize: 1e9,
+		},
+		{
+			name: "remote allocation without data",
+			hint: newHint(1, 31, 80),
+		},
+	}
+	for _, tcase := range tcases {
+		t.Run(tcase.name, func(t *testing.T) {
+			latency, hop, distance, bandwidth := tcase.hint.ScoreComponents(tcase.dataSize, PolicyOptions{})
+			hint := tcase.hint
+			hint.UpdateScore(tcase.dataSize, PolicyOptions{})
+			if sum := latency + hop + distance + bandwidth; sum != hint.GetScore() {
+				t.Errorf("Expected components %v + %v + %v + %v to sum to the score %v, got %v", latency, hop, distance, bandwidth, hint.GetScore(), sum)
+			}
+		})
+	}
+
+	// An unreachable span is entirely the latency term.
+	latency, hop, distance, bandwidth := func() (float64, float64, float64, float64) {
+		hint := newHint(2, UnreachableNUMADistance, 80)
+		return hint.ScoreComponents(1e9, PolicyOptions{})
+	}()
+	if !math.IsInf(latency, 1) || hop != 0 || distance != 0 || bandwidth != 0 {
+		t.Errorf("Expected an unreachable span to yield only an infinite latency term, got %v, %v, %v, %v", latency, hop, distance, bandwidth)
+	}
+
+	// A hint without enhanced fields has no score to explain.
+	basic := TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true}
+	if latency, hop, distance, bandwidth := basic.ScoreComponents(1e9, PolicyOptions{}); latency != 0 || hop != 0 || distance != 0 || bandwidth != 0 {
+		t.Errorf("Expected zero components for a basic hint, got %v, %v, %v, %v", latency, hop, distance, bandwidth)
+	}
+}
+
+func TestScoreComponentsWithGateOff(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.EnhancedTopologyHints, false)
+
+	hopCount := 2
+	distanceValue := 21
+	bandwidthValue := 4.0
+	hint := TopologyHint{
+		NUMANodeAffinity: NewTestBitMask(0),
+		Preferred:        true,
+		HopCount:         &hopCount,
+		Distance:         &distanceValue,
+		Bandwidth:        &bandwidthValue,
+	}
+	if latency, hop, distance, bandwidth := hint.ScoreComponents(1e9, PolicyOptions{}); latency != 0 || hop != 0 || distance != 0 || bandwidth != 0 {
+		t.Errorf("Expected zero components with the gate off, got %v, %v, %v, %v", latency, hop, distance, bandwidth)
+	}
+}
+
+// TestCalculateTopologyScoreMonotonicity pins down the ordering invariants
+// the mergers rely on: with everything else held constant, more hops or more
+// distance must never improve (lower) the score, and more bandwidth must
+// never worsen it. A scoring tweak that breaks one of these silently inverts
+// placement preferences.
+func TestCalculateTopologyScoreMonotonicity(t *testing.T) {
+	const (
+		distance  = 20
+		bandwidth = 80.0
+		hopCount  = 2
+		dataSize  = int64(1e9)
+	)
+
+	// Non-decreasing in hop count.
+	previous := math.Inf(-1)
+	for hops := 0; hops <= 10; hops++ {
+		score := CalculateTopologyScore(hops, distance, bandwidth, dataSize, PolicyOptions{})
+		if score < previous {
+			t.Errorf("Expected score to be non-decreasing in hop count, got %v for %d hops after %v", score, hops, previous)
+		}
+		previous = score
+	}
+
+	// Non-decreasing in distance, including the jump to +Inf at the
+	// unreachable sentinel.
+	previous = math.Inf(-1)
+	for d := 0; d <= UnreachableNUMADistance; d++ {
+		score := CalculateTopologyScore(hopCount, d, bandwidth, dataSize, PolicyOptions{})
+		if score < previous {
+			t.Errorf("Expected score to be non-decreasing in distance, got %v for distance %d after %v", score, d, previous)
+		}
+		previous = score
+	}
+
+	// Non-increasing in bandwidth across positive values. Bandwidth 0 is
+	// excluded deliberately: it means "unknown", which skips the transfer
+	// term entirely instead of modeling an infinitely slow link.
+	previous = math.Inf(1)
+	for _, b := range []float64{0.5, 1, 2, 5, 10, 50, 100, 400} {
+		score := CalculateTopologyScore(hopCount, distance, b, dataSize, PolicyOptions{})
+		if score > previous {
+			t.Errorf("Expected score to be non-increasing in bandwidth, got %v for bandwidth %v after %v", score, b, previous)
+		}
+		previous = score
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"sort"
+)
+
+// ManagerSnapshot is a point-in-time, JSON-marshalable view of the topology
+// manager's state, intended for the kubelet debug surface. It captures the
+// configured policy and scope, the discovered NUMA topology, and the hint
+// committed for every tracked container.
+type ManagerSnapshot struct {
+	PolicyName    string                  `json:"policyName"`
+	ScopeName     string                  `json:"scopeName"`
+	NUMANodes     []int                   `json:"numaNodes,omitempty"`
+	NUMADistances map[int][]uint64        `json:"numaDistances,omitempty"`
+	Hints         []ContainerHintSnapshot `json:"hints,omitempty"`
+}
+
+// ContainerHintSnapshot records the TopologyHint committed for a single
+// container at admission time.
+type ContainerHintSnapshot struct {
+	PodUID        string               `json:"podUID"`
+	ContainerName string               `json:"containerName"`
+	Hint          TopologyHintSnapshot `json:"hint"`
+}
+
+// TopologyHintSnapshot is the JSON-marshalable form of a TopologyHint. The
+// affinity is rendered as the list of set NUMA node ids because the bitmask
+// itself does not marshal; a nil list means the container was admitted
+// without pinning it to any particular set of NUMA nodes.
+type TopologyHintSnapshot struct {
+	NUMANodeAffinity []int    `json:"numaNodeAffinity,omitempty"`
+	Preferred        bool     `json:"preferred"`
+	HopCount         *int     `json:"hopCount,omitempty"`
+	Distance         *int     `json:"distance,omitempty"`
+	Bandwidth        *float64 `json:"bandwidth,omitempty"`
+	Score            *float64 `json:"score,omitempty"`
+}
+
+// snapshotTopologyHint converts a TopologyHint into its serializable form.
+func snapshotTopologyHint(th TopologyHint) TopologyHintSnapshot {
+	snapshot := TopologyHintSnapshot{
+		Preferred: th.Preferred,
+		HopCount:  th.HopCount,
+		Distance:  th.Distance,
+		Bandwidth: th.Bandwidth,
+		Score:     th.Score,
+	}
+	if th.NUMANodeAffinity != nil {
+		snapshot.NUMANodeAffinity = th.NUMANodeAffinity.GetBits()
+	}
+	return snapshot
+}
+
+// HintSnapshots returns a serializable copy of the hints committed for every
+// container tracked by this scope, sorted by pod UID and container name for
+// deterministic output.
+func (s *scope) HintSnapshots() []ContainerHintSnapshot {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var snapshots []ContainerHintSnapshot
+	for podUID, containers := range s.podTopologyHints {
+		for containerName, hint := range containers {
+			snapshots = append(snapshots, ContainerHintSnapshot{
+				PodUID:        podUID,
+				ContainerName: containerName,
+				Hint:          snapshotTopologyHint(hint),
+			})
+		}
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		if snapshots[i].PodUID != snapshots[j].PodUID {
+			return snapshots[i].PodUID < snapshots[j].PodUID
+		}
+		return snapshots[i].ContainerName < snapshots[j].ContainerName
+	})
+	return snapshots
+}
+
+// Snapshot returns a point-in-time view of the manager's state suitable for
+// JSON marshaling. It is safe to call concurrently with admission.
+func (m *manager) Snapshot() ManagerSnapshot {
+	snapshot := ManagerSnapshot{
+		PolicyName: m.GetPolicy().Name(),
+		ScopeName:  m.GetScopeName(),
+		Hints:      m.scope.HintSnapshots(),
+	}
+	if m.podScopeOverride != nil {
+		snapshot.Hints = append(snapshot.Hints, m.podScopeOverride.HintSnapshots()...)
+	}
+	// Pods admitted under a per-pod policy override store their hints in the
+	// override scopes; walk them in policy-name order for deterministic
+	// output.
+	policyNames := make([]string, 0, len(m.policyOverrideScopes))
+	for policyName := range m.policyOverrideScopes {
+		policyNames = append(policyNames, policyName)
+	}
+	sort.Strings(policyNames)
+	for _, policyName := range policyNames {
+		snapshot.Hints = append(snapshot.Hints, m.policyOverrideScopes[policyName].HintSnapshots()...)
+	}
+	if m.numaInfo != nil {
+		snapshot.NUMANodes = append([]int{}, m.numaInfo.Nodes...)
+		snapshot.NUMADistances = make(map[int][]uint64, len(m.numaInfo.NUMADistances))
+		for node, distances := range m.numaInfo.NUMADistances {
+			snapshot.NUMADistances[node] = append([]uint64{}, distances...)
+		}
+	}
+	return snapshot
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/kubelet/cm/containermap"
+)
+
+func TestManagerSnapshot(t *testing.T) {
+	hopCount := 1
+	score := 10.0
+
+	scope := &containerScope{
+		scope{
+			name:             containerTopologyScope,
+			podTopologyHints: podTopologyHints{},
+			policy:           NewBestEffortPolicy(commonNUMAInfoTwoNodes(), PolicyOptions{}),
+			podMap:           containermap.NewContainerMap(),
+		},
+	}
+	scope.setTopologyHints("fakePod1", "fakeContainer1", TopologyHint{
+		NUMANodeAffinity: NewTestBitMask(0, 1),
+		Preferred:        true,
+		HopCount:         &hopCount,
+		Score:            &score,
+	})
+	scope.setTopologyHints("fakePod1", "fakeContainer2", TopologyHint{
+		NUMANodeAffinity: nil,
+		Preferred:        true,
+	})
+
+	// A pod admitted through a per-pod policy override stores its hint in the
+	// override scope and must show up in the snapshot too.
+	overrideScope := NewContainerScope(NewRestrictedPolicy(commonNUMAInfoTwoNodes(), PolicyOptions{})).(*containerScope)
+	overrideScope.setTopologyHints("fakePod2", "fakeContainer3", TopologyHint{
+		NUMANodeAffinity: NewTestBitMask(1),
+		Preferred:        true,
+	})
+
+	mngr := &manager{
+		scope:                scope,
+		policyOverrideScopes: map[string]Scope{PolicyRestricted: overrideScope},
+		numaInfo:             commonNUMAInfoTwoNodes(),
+	}
+
+	snapshot := mngr.Snapshot()
+
+	if snapshot.PolicyName != PolicyBestEffort {
+		t.Errorf("Expected policy name %q, got %q", PolicyBestEffort, snapshot.PolicyName)
+	}
+	if snapshot.ScopeName != containerTopologyScope {
+		t.Errorf("Expected scope name %q, got %q", containerTopologyScope, snapshot.ScopeName)
+	}
+	if !reflect.DeepEqual(snapshot.NUMANodes, []int{0, 1}) {
+		t.Errorf("Expected NUMA nodes [0 1], got %v", snapshot.NUMANodes)
+	}
+	if len(snapshot.Hints) != 3 {
+		t.Fatalf("Expected 3 hint snapshots, got %d", len(snapshot.Hints))
+	}
+	if snapshot.Hints[0].ContainerName != "fakeContainer1" || snapshot.Hints[1].ContainerName != "fakeContainer2" {
+		t.Errorf("Expected hint snapshots sorted by container name, got %v", snapshot.Hints)
+	}
+	if !reflect.DeepEqual(snapshot.Hints[0].Hint.NUMANodeAffinity, []int{0, 1}) {
+		t.Errorf("Expected affinity [0 1], got %v", snapshot.Hints[0].Hint.NUMANodeAffinity)
+	}
+	if snapshot.Hints[0].Hint.HopCount == nil || *snapshot.Hints[0].Hint.HopCount != hopCount {
+		t.Errorf("Expected hop count %d, got %v", hopCount, snapshot.Hints[0].Hint.HopCount)
+	}
+	if snapshot.Hints[1].Hint.NUMANodeAffinity != nil {
+		t.Errorf("Expected nil affinity for unpinned container, got %v", snapshot.Hints[1].Hint.NUMANodeAffinity)
+	}
+	if snapshot.Hints[2].ContainerName != "fakeContainer3" || !reflect.DeepEqual(snapshot.Hints[2].Hint.NUMANodeAffinity, []int{1}) {
+		t.Errorf("Expected the policy override scope's hint last with affinity [1], got %v", snapshot.Hints[2])
+	}
+
+	marshaled, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatalf("Expected snapshot to marshal, got error: %v", err)
+	}
+	var roundtrip ManagerSnapshot
+	if err := json.Unmarshal(marshaled, &roundtrip); err != nil {
+		t.Fatalf("Expected snapshot to unmarshal, got error: %v", err)
+	}
+	if !reflect.DeepEqual(snapshot, roundtrip) {
+		t.Errorf("Expected snapshot to survive a JSON roundtrip, got %v expected %v", roundtrip, snapshot)
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	"k8s.io/kubernetes/pkg/kubelet/cm/topologymanager"
+)
+
+// AllocateCall records a single call to FakeHintProvider.Allocate.
+type AllocateCall struct {
+	PodUID        string
+	ContainerName string
+}
+
+// FakeHintProvider is a configurable topologymanager.HintProvider for use in
+// tests. It returns the hints it was constructed with and records every
+// Allocate call. An error or delay can be injected into Allocate to exercise
+// failure and timeout paths.
+type FakeHintProvider struct {
+	sync.Mutex
+
+	// AllocateError is returned from every Allocate call when set.
+	AllocateError error
+	// AllocateDelay makes every Allocate call sleep for the given duration
+	// before returning.
+	AllocateDelay time.Duration
+
+	hints         map[string][]topologymanager.TopologyHint
+	podHints      map[string][]topologymanager.TopologyHint
+	allocateCalls []AllocateCall
+}
+
+var _ topologymanager.HintProvider = &FakeHintProvider{}
+
+// NewFakeHintProvider returns a FakeHintProvider that serves the given
+// container-scope and pod-scope hints.
+func NewFakeHintProvider(hints map[string][]topologymanager.TopologyHint, podHints map[string][]topologymanager.TopologyHint) *FakeHintProvider {
+	return &FakeHintProvider{
+		hints:    hints,
+		podHints: podHints,
+	}
+}
+
+// GetTopologyHints returns the configured container-scope hints.
+func (f *FakeHintProvider) GetTopologyHints(pod *v1.Pod, container *v1.Container) map[string][]topologymanager.TopologyHint {
+	return f.hints
+}
+
+// GetPodTopologyHints returns the configured pod-scope hints.
+func (f *FakeHintProvider) GetPodTopologyHints(pod *v1.Pod) map[string][]topologymanager.TopologyHint {
+	return f.podHints
+}
+
+// Allocate records the call, sleeps for AllocateDelay if set, and returns
+// AllocateError.
+func (f *FakeHintProvider) Allocate(pod *v1.Pod, container *v1.Container) error {
+	f.Lock()
+	f.allocateCalls = append(f.allocateCalls, AllocateCall{
+		PodUID:        string(pod.UID),
+		ContainerName: container.Name,
+	})
+	delay := f.AllocateDelay
+	err := f.AllocateError
+	f.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+	return err
+}
+
+// AllocateCalls returns the Allocate calls recorded so far.
+func (f *FakeHintProvider) AllocateCalls() []AllocateCall {
+	f.Lock()
+	defer f.Unlock()
+	calls := make([]AllocateCall, len(f.allocateCalls))
+	copy(calls, f.allocateCalls)
+	return calls
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"k8s.io/kubernetes/pkg/kubelet/cm/topologymanager"
+)
+
+func TestFakeHintProvider(t *testing.T) {
+	hints := map[string][]topologymanager.TopologyHint{
+		"resource1": {{Preferred: true}},
+	}
+	podHints := map[string][]topologymanager.TopologyHint{
+		"resource2": {{Preferred: false}},
+	}
+	provider := NewFakeHintProvider(hints, podHints)
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			UID: types.UID("fakePodUID"),
+		},
+	}
+	container := &v1.Container{Name: "fakeContainer"}
+
+	if actual := provider.GetTopologyHints(pod, container); !reflect.DeepEqual(actual, hints) {
+		t.Errorf("expected GetTopologyHints to return %v, got %v", hints, actual)
+	}
+	if actual := provider.GetPodTopologyHints(pod); !reflect.DeepEqual(actual, podHints) {
+		t.Errorf("expected GetPodTopologyHints to return %v, got %v", podHints, actual)
+	}
+
+	if err := provider.Allocate(pod, container); err != nil {
+		t.Errorf("expected Allocate to succeed, got %v", err)
+	}
+
+	allocateError := errors.New("fake allocate error")
+	provider.AllocateError = allocateError
+	if err := provider.Allocate(pod, container); !errors.Is(err, allocateError) {
+		t.Errorf("expected Allocate to return %v, got %v", allocateError, err)
+	}
+
+	expectedCalls := []AllocateCall{
+		{PodUID: "fakePodUID", ContainerName: "fakeContainer"},
+		{PodUID: "fakePodUID", ContainerName: "fakeContainer"},
+	}
+	if actual := provider.AllocateCalls(); !reflect.DeepEqual(actual, expectedCalls) {
+		t.Errorf("expected recorded Allocate calls %v, got %v", expectedCalls, actual)
+	}
+}
@@ -18,11 +18,22 @@ package topologymanager
 
 import (
 	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	cadvisorapi "github.com/google/cadvisor/info/v1"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
+	pkgfeatures "k8s.io/kubernetes/pkg/features"
+	"k8s.io/kubernetes/pkg/kubelet/cm/admission"
 	"k8s.io/kubernetes/pkg/kubelet/cm/topologymanager/bitmask"
 	"k8s.io/kubernetes/pkg/kubelet/lifecycle"
 	"k8s.io/kubernetes/pkg/kubelet/metrics"
@@ -40,12 +51,35 @@ const (
 	defaultMaxAllowableNUMANodes = 8
 	// ErrorTopologyAffinity represents the type for a TopologyAffinityError
 	ErrorTopologyAffinity = "TopologyAffinityError"
+	// PinnedEventReason is the reason of the Normal event emitted on a pod
+	// when its containers are committed to NUMA node affinities and an event
+	// recorder is registered.
+	PinnedEventReason = "TopologyAffinityPinned"
+	// TopologyPlacementScoreCondition is the pod condition type recorded
+	// after a successful admission when a pod status writer is registered,
+	// carrying the committed NUMA nodes and placement score.
+	TopologyPlacementScoreCondition v1.PodConditionType = "TopologyPlacementScore"
 )
 
+// PodStatusWriter records a condition on a pod's status. It is the minimal
+// surface the manager needs from the kubelet's status machinery, so the
+// placement condition can be exported without importing it here.
+type PodStatusWriter interface {
+	SetPodCondition(pod *v1.Pod, condition v1.PodCondition)
+}
+
 // TopologyAffinityError represents an resource alignment error
-type TopologyAffinityError struct{}
+type TopologyAffinityError struct {
+	// UnalignableResources optionally names the resources for which no
+	// admissible placement existed on their own, so the rejection points at
+	// the culprit rather than the whole pod.
+	UnalignableResources []string
+}
 
 func (e TopologyAffinityError) Error() string {
+	if len(e.UnalignableResources) > 0 {
+		return fmt.Sprintf("Resources cannot be allocated with Topology locality: unalignable resources: %s", strings.Join(e.UnalignableResources, ", "))
+	}
 	return "Resources cannot be allocated with Topology locality"
 }
 
@@ -64,6 +98,45 @@ type Manager interface {
 	AddContainer(pod *v1.Pod, container *v1.Container, containerID string)
 	// RemoveContainer removes pod from Manager tracking
 	RemoveContainer(containerID string) error
+	// AlignedCapacity aggregates, per NUMA node, the remaining allocatable
+	// aligned capacity of the given resource across all hint providers that
+	// implement AlignedCapacityReporter.
+	AlignedCapacity(resource string) map[int]int64
+	// PinnedNodeCounts counts, per NUMA node, how many tracked containers
+	// have a committed affinity pinning the node. Containers admitted with
+	// a don't-care affinity pin nothing and are not counted.
+	PinnedNodeCounts() map[int]int
+	// Snapshot returns a point-in-time, JSON-marshalable view of the
+	// manager's state for the kubelet debug surface.
+	Snapshot() ManagerSnapshot
+	// NotifyTopologyChanged records a machine topology change at runtime
+	// (e.g. device hot-plug or CXL memory coming online): it bumps the
+	// manager's generation, clears the merge caches, and invokes registered
+	// TopologyChangeListener providers. Affinities committed before the
+	// change remain stored but belong to the previous generation and should
+	// be treated as stale by consumers that track it.
+	NotifyTopologyChanged()
+	// SetEventRecorder registers the recorder used to surface admission
+	// outcomes as events on the pod: a Normal event with the committed NUMA
+	// nodes (and score, when one was computed) on success, and a Warning
+	// event with the rejection reason on failure. It is optional; without a
+	// recorder admissions proceed silently as before.
+	SetEventRecorder(recorder record.EventRecorder)
+	// SetPodStatusWriter registers the writer used to record a
+	// TopologyPlacementScore condition on admitted pods, carrying the
+	// committed NUMA nodes and score for GitOps-style observability. It is
+	// optional; without a writer no condition is recorded.
+	SetPodStatusWriter(writer PodStatusWriter)
+	// CheckTopologyDrift compares the discovered NUMA distance matrix
+	// against the one persisted in stateDir by the previous kubelet run,
+	// logging a warning and bumping the manager's generation when they
+	// differ, then persists the current matrix for the next run. State file
+	// errors are logged but never fatal.
+	CheckTopologyDrift(stateDir string)
+	// PolicyOptions returns the parsed policy options the manager was
+	// created with, so sibling managers can read the knobs that concern
+	// them (e.g. the memory manager's pressure threshold).
+	PolicyOptions() PolicyOptions
 	// Store is the interface for storing pod topology hints
 	Store
 }
@@ -71,8 +144,82 @@ type Manager interface {
 type manager struct {
 	//Topology Manager Scope
 	scope Scope
+	// podScopeOverride handles pods that request pod-scope alignment via the
+	// scope annotation on a node configured for container scope. It is nil
+	// when the configured scope already is pod scope (or none).
+	podScopeOverride Scope
+	// policyOverrideScopes handles pods in allowlisted namespaces that request
+	// a different policy via the policy annotation, one scope per selectable
+	// policy. It is nil unless the policy-override-namespaces option names at
+	// least one namespace.
+	policyOverrideScopes map[string]Scope
+	// policyOverrideNamespaces is the namespace allowlist from the
+	// policy-override-namespaces policy option.
+	policyOverrideNamespaces sets.Set[string]
+	// opts are the parsed policy options the manager was created with,
+	// retained so sibling managers can read the knobs that concern them.
+	opts PolicyOptions
+	// numaInfo is the NUMA topology the policy was built with, retained so
+	// debug snapshots can report the node list and distance matrix. It is
+	// nil when the none policy is in effect.
+	numaInfo *NUMAInfo
+	// generation counts machine topology changes reported through
+	// NotifyTopologyChanged, so consumers can tell whether a stored affinity
+	// predates the current topology.
+	generation atomic.Uint64
+	// changeListeners are the registered hint providers that asked to be
+	// notified of topology changes.
+	changeListeners []TopologyChangeListener
+	// recorder, when set, emits per-pod admission outcomes as events. Nil
+	// unless SetEventRecorder was called.
+	recorder record.EventRecorder
+	// statusWriter, when set, records the placement score condition on
+	// admitted pods. Nil unless SetPodStatusWriter was called.
+	statusWriter PodStatusWriter
+	// topologyMutex guards the topology snapshot: admissions hold it for
+	// reading while they generate and merge hints, and NotifyTopologyChanged
+	// and AddHintProvider take it for writing, so a topology change or a late
+	// provider registration cannot interleave with an in-flight Admit.
+	topologyMutex sync.RWMutex
 }
 
+// ScopeAnnotationKey is the pod annotation allowing an individual pod to
+// request pod-scope alignment on a node whose kubelet is configured for
+// container scope. The only recognized value is "pod"; unknown values are
+// ignored with a warning.
+const ScopeAnnotationKey = "topology.kubernetes.io/scope"
+
+// PolicyAnnotationKey is the pod annotation through which an individual pod
+// requests admission under a different topology policy than the node default,
+// e.g. "single-numa-node" on a best-effort node. It is only honored for pods
+// in a namespace allowlisted by the policy-override-namespaces policy option;
+// pods elsewhere have the annotation ignored with a warning. Unknown policy
+// values on an allowlisted pod reject the admission.
+const PolicyAnnotationKey = "topology.kubernetes.io/policy"
+
+// ResourceAntiAffinityAnnotationKey is the pod annotation listing resource
+// names, comma-separated, that must be placed on pairwise distinct NUMA
+// nodes (e.g. redundant NICs kept apart for fault isolation). It is only
+// honored by policies implementing AntiAffinityAwarePolicy; other policies
+// ignore it with a warning.
+const ResourceAntiAffinityAnnotationKey = "topology.kubernetes.io/resource-anti-affinity"
+
+// DataSizeAnnotationPrefix is the prefix of pod annotations declaring the
+// estimated number of bytes the workload moves through one resource, with
+// any "/" in the resource name escaped as "~1" (e.g.
+// topology.kubernetes.io/data-bytes.example.com~1gpu). The sizes feed the
+// bandwidth term of the merged hint's score, one term per resource.
+const DataSizeAnnotationPrefix = "topology.kubernetes.io/data-bytes."
+
+// InterconnectBandwidthResourceName is the well-known extended resource name
+// through which a container declares the interconnect bandwidth, in GB/s, its
+// workload needs. When any container of a pod requests it, hints reporting
+// less bandwidth are demoted to non-preferred before merging, so the
+// restricted policy rejects placements that cannot meet the request while
+// best-effort merely avoids them. Hints without a bandwidth metric are left
+// untouched.
+const InterconnectBandwidthResourceName v1.ResourceName = "topology.kubernetes.io/interconnect-bandwidth"
+
 // HintProvider is an interface for components that want to collaborate to
 // achieve globally optimal concrete resource alignment with respect to
 // NUMA locality.
@@ -94,10 +241,90 @@ type HintProvider interface {
 	Allocate(pod *v1.Pod, container *v1.Container) error
 }
 
+// HealthCheckableHintProvider is an optional interface that a HintProvider
+// can implement to report whether the hints it returns are currently
+// trustworthy. A provider whose backing component is unavailable (e.g. a
+// crashed device plugin) can report false to be skipped while gathering
+// hints, so its stale or empty hints do not influence merging. Providers
+// that do not implement this interface are assumed to be healthy.
+type HealthCheckableHintProvider interface {
+	HintProvider
+	// Healthy returns true if the hints returned by this provider can
+	// currently be trusted.
+	Healthy() bool
+}
+
+// DeallocatableHintProvider is an optional interface that a HintProvider can
+// implement to undo a successful Allocate when a later provider's Allocate
+// fails during the same admission. Providers that do not implement this
+// interface are simply skipped during rollback.
+type DeallocatableHintProvider interface {
+	HintProvider
+	// Deallocate releases any resources the provider allocated to the
+	// given container, so a partially admitted pod does not leak them.
+	Deallocate(pod *v1.Pod, container *v1.Container) error
+}
+
+// isProviderHealthy returns the provider's health if it reports one, and
+// defaults to healthy for providers that do not implement
+// HealthCheckableHintProvider.
+func isProviderHealthy(provider HintProvider) bool {
+	if h, ok := provider.(HealthCheckableHintProvider); ok {
+		return h.Healthy()
+	}
+	return true
+}
+
+// CachedHintsProvider is an optional interface that a HintProvider can
+// implement to serve precomputed hints during container-scope admission. When
+// CachedHints reports ok, the scope uses the returned hints instead of calling
+// GetTopologyHints, sparing providers whose hints rarely change (e.g. a GPU
+// plugin with a fixed NUMA layout) from recomputing them on every admission.
+// Providers are responsible for invalidating their cache when the underlying
+// devices change, e.g. from a TopologyChanged notification.
+type CachedHintsProvider interface {
+	HintProvider
+	// CachedHints returns the provider's precomputed hints for the container
+	// and whether they are currently valid. Reporting ok=false falls back to
+	// GetTopologyHints.
+	CachedHints(pod *v1.Pod, container *v1.Container) (map[string][]TopologyHint, bool)
+}
+
+// TopologyChangeListener is an optional interface that a HintProvider can
+// implement to be told when the machine topology changed at runtime, so it
+// can invalidate any topology-derived caches of its own and recompute on the
+// next hint request.
+type TopologyChangeListener interface {
+	HintProvider
+	// TopologyChanged is invoked after the topology manager has bumped its
+	// generation and cleared its merge caches.
+	TopologyChanged()
+}
+
+// AlignedCapacityReporter is an optional interface that a HintProvider can
+// implement to report how much topology-aligned capacity remains for the
+// resources it manages. It allows external consumers, such as a
+// topology-aware scheduler extender, to ask how much of a resource is still
+// allocatable per NUMA node before committing a pod to this node.
+type AlignedCapacityReporter interface {
+	HintProvider
+	// AlignedCapacity returns the remaining allocatable capacity of the
+	// given resource, keyed by NUMA node id.
+	AlignedCapacity(resource string) map[int]int64
+}
+
 // Store interface is to allow Hint Providers to retrieve pod affinity
 type Store interface {
+	// GetAffinity returns the TopologyHint committed for the given container.
+	// The returned hint may have a nil NUMANodeAffinity, meaning the container
+	// was admitted without pinning it to any particular set of NUMA nodes.
+	// Use TopologyHint.IsPinned to distinguish "don't care" from an actual
+	// pinned allocation.
 	GetAffinity(podUID string, containerName string) TopologyHint
 	GetPolicy() Policy
+	// GetScopeName returns the name of the configured scope: "container",
+	// "pod", or "none" when the none policy is in effect.
+	GetScopeName() string
 }
 
 // TopologyHint is a struct containing the NUMANodeAffinity for a Container
@@ -106,11 +333,152 @@ type TopologyHint struct {
 	// Preferred is set to true when the NUMANodeAffinity encodes a preferred
 	// allocation for the Container. It is set to false otherwise.
 	Preferred bool
+
+	// The fields below carry optional interconnect metrics for the
+	// allocation the hint encodes. They are only populated when the
+	// EnhancedTopologyHints feature gate is enabled; a nil pointer means
+	// the provider did not supply the metric.
+
+	// HopCount is the number of interconnect hops between the NUMA nodes
+	// involved in the allocation.
+	HopCount *int
+	// Distance is the average ACPI SLIT-style distance between the NUMA
+	// nodes involved in the allocation.
+	Distance *int
+	// Bandwidth is the available interconnect bandwidth in GB/s between
+	// the NUMA nodes involved in the allocation.
+	Bandwidth *float64
+	// Score is a composite placement cost for the allocation computed
+	// from the metrics above. Lower is better.
+	Score *float64
+}
+
+// GetHopCount returns the hint's hop count, defaulting to 0 (local) when the
+// provider did not supply one.
+func (th *TopologyHint) GetHopCount() int {
+	if th.HopCount == nil {
+		return 0
+	}
+	return *th.HopCount
+}
+
+// GetDistance returns the hint's NUMA distance, defaulting to the ACPI local
+// distance when the provider did not supply one.
+func (th *TopologyHint) GetDistance() int {
+	if th.Distance == nil {
+		return DefaultLocalDistance
+	}
+	return *th.Distance
+}
+
+// GetBandwidth returns the hint's interconnect bandwidth in GB/s, defaulting
+// to 0 (unknown) when the provider did not supply one.
+func (th *TopologyHint) GetBandwidth() float64 {
+	if th.Bandwidth == nil {
+		return 0
+	}
+	return *th.Bandwidth
+}
+
+// GetScore returns the hint's placement score, defaulting to 0 (best) when
+// no score has been computed.
+func (th *TopologyHint) GetScore() float64 {
+	if th.Score == nil {
+		return 0
+	}
+	return *th.Score
+}
+
+// HasHopCount reports whether the provider actually supplied a hop count.
+// GetHopCount returns 0 when the field is unset, so it cannot distinguish an
+// explicit local placement from a hint without the metric.
+func (th *TopologyHint) HasHopCount() bool {
+	return th.HopCount != nil
+}
+
+// HasDistance reports whether the provider actually supplied a NUMA distance.
+// GetDistance returns the local distance baseline when the field is unset, so
+// an explicit local distance is indistinguishable from an absent one through
+// the getter alone.
+func (th *TopologyHint) HasDistance() bool {
+	return th.Distance != nil
+}
+
+// HasBandwidth reports whether the provider actually supplied an interconnect
+// bandwidth, as opposed to GetBandwidth's 0 default.
+func (th *TopologyHint) HasBandwidth() bool {
+	return th.Bandwidth != nil
+}
+
+// HasScore reports whether a placement score has been computed for the hint,
+// as opposed to GetScore's 0 default.
+func (th *TopologyHint) HasScore() bool {
+	return th.Score != nil
+}
+
+// hasEnhancedFields returns true if the provider supplied any of the
+// enhanced interconnect metrics for this hint.
+func (th *TopologyHint) hasEnhancedFields() bool {
+	return th.HasHopCount() || th.HasDistance() || th.HasBandwidth() || th.HasScore()
+}
+
+// float64EqualityEpsilon is the tolerance used when comparing the float
+// valued enhanced fields (Bandwidth, Score). Values computed through
+// different code paths can differ by a rounding ULP while representing the
+// same quantity, and comparing them exactly would flag spurious hint changes
+// across restarts.
+const float64EqualityEpsilon = 1e-9
+
+func equalIntPointer(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func equalFloat64Pointer(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return equalFloat64WithinEpsilon(*a, *b, float64EqualityEpsilon)
+}
+
+// equalFloat64WithinEpsilon returns true if a and b differ by no more than
+// the given epsilon. Infinities only compare equal to themselves.
+func equalFloat64WithinEpsilon(a, b, epsilon float64) bool {
+	if math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return a == b
+	}
+	return math.Abs(a-b) <= epsilon
+}
+
+// IsPinned returns true if the hint actually pins the container to a
+// non-empty set of NUMA nodes. It returns false for a nil NUMANodeAffinity
+// (meaning "don't care") and for an empty affinity mask.
+func (th TopologyHint) IsPinned() bool {
+	return th.NUMANodeAffinity != nil && th.NUMANodeAffinity.Count() > 0
+}
+
+// NodeIDs returns the NUMA node IDs set in the hint's affinity, in ascending
+// order. A nil or empty affinity yields an empty slice, so callers can
+// iterate without a nil guard.
+func (th TopologyHint) NodeIDs() []int {
+	nodeIDs := []int{}
+	if th.NUMANodeAffinity != nil {
+		nodeIDs = append(nodeIDs, th.NUMANodeAffinity.GetBits()...)
+	}
+	return nodeIDs
 }
 
 // IsEqual checks if TopologyHint are equal
 func (th *TopologyHint) IsEqual(topologyHint TopologyHint) bool {
 	if th.Preferred == topologyHint.Preferred {
+		if !equalIntPointer(th.HopCount, topologyHint.HopCount) ||
+			!equalIntPointer(th.Distance, topologyHint.Distance) ||
+			!equalFloat64Pointer(th.Bandwidth, topologyHint.Bandwidth) ||
+			!equalFloat64Pointer(th.Score, topologyHint.Score) {
+			return false
+		}
 		if th.NUMANodeAffinity == nil || topologyHint.NUMANodeAffinity == nil {
 			return th.NUMANodeAffinity == topologyHint.NUMANodeAffinity
 		}
@@ -119,6 +487,25 @@ func (th *TopologyHint) IsEqual(topologyHint TopologyHint) bool {
 	return false
 }
 
+// EqualIgnoringScore checks if two TopologyHints are equal in every field
+// except Score. Scores are recomputed from machine state and can jitter
+// between kubelet restarts, so stability checks comparing regenerated hints
+// against stored ones should use this instead of IsEqual.
+func (th TopologyHint) EqualIgnoringScore(other TopologyHint) bool {
+	if th.Preferred != other.Preferred {
+		return false
+	}
+	if !equalIntPointer(th.HopCount, other.HopCount) ||
+		!equalIntPointer(th.Distance, other.Distance) ||
+		!equalFloat64Pointer(th.Bandwidth, other.Bandwidth) {
+		return false
+	}
+	if th.NUMANodeAffinity == nil || other.NUMANodeAffinity == nil {
+		return th.NUMANodeAffinity == other.NUMANodeAffinity
+	}
+	return th.NUMANodeAffinity.IsEqual(other.NUMANodeAffinity)
+}
+
 // LessThan checks if TopologyHint `a` is less than TopologyHint `b`
 // this means that either `a` is a preferred hint and `b` is not
 // or `a` NUMANodeAffinity attribute is narrower than `b` NUMANodeAffinity attribute.
@@ -129,14 +516,83 @@ func (th *TopologyHint) LessThan(other TopologyHint) bool {
 	return th.NUMANodeAffinity.IsNarrowerThan(other.NUMANodeAffinity)
 }
 
+// SortHints sorts hints in place into a canonical order: preferred hints
+// first, then narrower NUMANodeAffinity masks, with nil-affinity ("don't
+// care") hints last within their preferred group. Unlike sorting with
+// LessThan directly, SortHints tolerates nil affinities, and the sort is
+// stable, so hints that compare equal keep their original relative order.
+func SortHints(hints []TopologyHint) {
+	sort.SliceStable(hints, func(i, j int) bool {
+		a, b := hints[i], hints[j]
+		if a.Preferred != b.Preferred {
+			return a.Preferred
+		}
+		if a.NUMANodeAffinity == nil || b.NUMANodeAffinity == nil {
+			// A nil affinity means "don't care", which is wider than any
+			// concrete mask.
+			return a.NUMANodeAffinity != nil
+		}
+		return a.NUMANodeAffinity.IsNarrowerThan(b.NUMANodeAffinity)
+	})
+}
+
 var _ Manager = &manager{}
 
+// ValidPolicies lists every policy name NewManager accepts, as a single
+// source of truth for config validators and tests.
+var ValidPolicies = []string{PolicyNone, PolicyBestEffort, PolicyRestricted, PolicySingleNumaNode, PolicyDistributed}
+
+// ValidScopes lists every scope name NewManager accepts.
+var ValidScopes = []string{containerTopologyScope, podTopologyScope}
+
+// IsValidPolicy returns true if name is one of ValidPolicies.
+func IsValidPolicy(name string) bool {
+	for _, policy := range ValidPolicies {
+		if policy == name {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidScope returns true if name is one of ValidScopes.
+func IsValidScope(name string) bool {
+	for _, scope := range ValidScopes {
+		if scope == name {
+			return true
+		}
+	}
+	return false
+}
+
 // NewManager creates a new TopologyManager based on provided policy and scope
 func NewManager(topology []cadvisorapi.Node, topologyPolicyName string, topologyScopeName string, topologyPolicyOptions map[string]string) (Manager, error) {
+	if !IsValidPolicy(topologyPolicyName) {
+		return nil, fmt.Errorf("unknown policy: \"%s\"", topologyPolicyName)
+	}
+	if !IsValidScope(topologyScopeName) && topologyPolicyName != PolicyNone {
+		return nil, fmt.Errorf("unknown scope: \"%s\"", topologyScopeName)
+	}
+
 	// When policy is none, the scope is not relevant, so we can short circuit here.
 	if topologyPolicyName == PolicyNone {
-		klog.InfoS("Creating topology manager with none policy")
-		return &manager{scope: NewNoneScope()}, nil
+		noneOpts, err := NewPolicyOptions(topologyPolicyOptions)
+		if err != nil {
+			return nil, err
+		}
+		if !noneOpts.NonePolicyShadowMetrics {
+			klog.InfoS("Creating topology manager with none policy")
+			return &manager{scope: NewNoneScope(), opts: noneOpts}, nil
+		}
+		// Shadow metrics mode: admit everything like none always does, but
+		// also compute what an enhanced best-effort merge would have chosen
+		// and record it as metrics.
+		numaInfo, err := NewNUMAInfo(topology, noneOpts)
+		if err != nil {
+			return nil, fmt.Errorf("cannot discover NUMA topology: %w", err)
+		}
+		klog.InfoS("Creating topology manager with none policy and shadow merge metrics")
+		return &manager{scope: NewNoneScopeWithShadowPolicy(NewBestEffortPolicy(numaInfo, noneOpts)), opts: noneOpts}, nil
 	}
 
 	opts, err := NewPolicyOptions(topologyPolicyOptions)
@@ -144,6 +600,10 @@ func NewManager(topology []cadvisorapi.Node, topologyPolicyName string, topology
 		return nil, err
 	}
 
+	if opts.DisabledPolicies.Has(topologyPolicyName) {
+		return nil, fmt.Errorf("policy %q is disabled by the %q policy option", topologyPolicyName, DisabledPolicies)
+	}
+
 	klog.InfoS("Creating topology manager with policy per scope", "topologyPolicyName", topologyPolicyName, "topologyScopeName", topologyScopeName, "topologyPolicyOptions", opts)
 
 	numaInfo, err := NewNUMAInfo(topology, opts)
@@ -155,37 +615,73 @@ func NewManager(topology []cadvisorapi.Node, topologyPolicyName string, topology
 		return nil, fmt.Errorf("unsupported on machines with more than %v NUMA Nodes", opts.MaxAllowableNUMANodes)
 	}
 
-	var policy Policy
-	switch topologyPolicyName {
-
-	case PolicyBestEffort:
-		policy = NewBestEffortPolicy(numaInfo, opts)
-
-	case PolicyRestricted:
-		policy = NewRestrictedPolicy(numaInfo, opts)
-
-	case PolicySingleNumaNode:
-		policy = NewSingleNumaNodePolicy(numaInfo, opts)
-
-	default:
-		return nil, fmt.Errorf("unknown policy: \"%s\"", topologyPolicyName)
+	policy, err := newPolicyByName(topologyPolicyName, numaInfo, opts)
+	if err != nil {
+		return nil, err
 	}
 
 	var scope Scope
+	var podScopeOverride Scope
 	switch topologyScopeName {
 
 	case containerTopologyScope:
 		scope = NewContainerScope(policy)
+		// Keep a pod scope around so individual pods can opt into pod-scope
+		// alignment via the scope annotation.
+		podScopeOverride = NewPodScope(policy)
 
 	case podTopologyScope:
 		scope = NewPodScope(policy)
 
 	default:
+		// this should never be reached, ValidScopes is checked above,
+		// but we keep it as further safety.
 		return nil, fmt.Errorf("unknown scope: \"%s\"", topologyScopeName)
 	}
 
+	if capacityPolicy, ok := policy.(CapacityAwarePolicy); ok {
+		// Both scopes register the same hint providers, so either one's
+		// aggregated capacity view works.
+		capacityPolicy.SetAlignedCapacityProvider(scope.AlignedCapacity)
+	}
+
+	// When namespaces are allowlisted for per-pod policy overrides, keep one
+	// scope per selectable policy around, built at the configured scope, so an
+	// annotated pod can be admitted through it.
+	var policyOverrideScopes map[string]Scope
+	if opts.PolicyOverrideNamespaces.Len() > 0 {
+		policyOverrideScopes = make(map[string]Scope)
+		for _, name := range ValidPolicies {
+			if name == PolicyNone || name == topologyPolicyName || opts.DisabledPolicies.Has(name) {
+				continue
+			}
+			overridePolicy, err := newPolicyByName(name, numaInfo, opts)
+			if err != nil {
+				// Policies unavailable on this node (e.g. distributed without
+				// its feature gate) simply cannot be selected via the
+				// annotation.
+				continue
+			}
+			var overrideScope Scope
+			if topologyScopeName == podTopologyScope {
+				overrideScope = NewPodScope(overridePolicy)
+			} else {
+				overrideScope = NewContainerScope(overridePolicy)
+			}
+			if capacityPolicy, ok := overridePolicy.(CapacityAwarePolicy); ok {
+				capacityPolicy.SetAlignedCapacityProvider(overrideScope.AlignedCapacity)
+			}
+			policyOverrideScopes[name] = overrideScope
+		}
+	}
+
 	manager := &manager{
-		scope: scope,
+		scope:                    scope,
+		podScopeOverride:         podScopeOverride,
+		policyOverrideScopes:     policyOverrideScopes,
+		policyOverrideNamespaces: opts.PolicyOverrideNamespaces,
+		numaInfo:                 numaInfo,
+		opts:                     opts,
 	}
 
 	manager.initializeMetrics()
@@ -193,15 +689,61 @@ func NewManager(topology []cadvisorapi.Node, topologyPolicyName string, topology
 	return manager, nil
 }
 
+// newPolicyByName constructs the named enforcing policy against the given
+// topology. It is shared between NewManager and the per-pod policy override
+// scopes, so an override admits pods through the exact policy the operator
+// could have configured node-wide.
+func newPolicyByName(policyName string, numaInfo *NUMAInfo, opts PolicyOptions) (Policy, error) {
+	switch policyName {
+
+	case PolicyBestEffort:
+		return NewBestEffortPolicy(numaInfo, opts), nil
+
+	case PolicyRestricted:
+		return NewRestrictedPolicy(numaInfo, opts), nil
+
+	case PolicySingleNumaNode:
+		return NewSingleNumaNodePolicy(numaInfo, opts), nil
+
+	case PolicyDistributed:
+		if !utilfeature.DefaultFeatureGate.Enabled(pkgfeatures.EnhancedTopologyHints) {
+			return nil, fmt.Errorf("policy %q requires the EnhancedTopologyHints feature gate", PolicyDistributed)
+		}
+		return NewDistributedPolicy(numaInfo, opts), nil
+
+	default:
+		// this should never be reached, ValidPolicies is checked above,
+		// but we keep it as further safety.
+		return nil, fmt.Errorf("unknown policy: \"%s\"", policyName)
+	}
+}
+
 func (m *manager) initializeMetrics() {
 	// ensure the values exist
 	metrics.ContainerAlignedComputeResources.WithLabelValues(metrics.AlignScopeContainer, metrics.AlignedNUMANode).Add(0)
 	metrics.ContainerAlignedComputeResources.WithLabelValues(metrics.AlignScopePod, metrics.AlignedNUMANode).Add(0)
 	metrics.ContainerAlignedComputeResourcesFailure.WithLabelValues(metrics.AlignScopeContainer, metrics.AlignedNUMANode).Add(0)
 	metrics.ContainerAlignedComputeResourcesFailure.WithLabelValues(metrics.AlignScopePod, metrics.AlignedNUMANode).Add(0)
+	metrics.TopologyManagerMergeModeTotal.WithLabelValues(metrics.MergeModeEnhanced).Add(0)
+	metrics.TopologyManagerMergeModeTotal.WithLabelValues(metrics.MergeModeBasicFallback).Add(0)
+	metrics.TopologyManagerMergeModeTotal.WithLabelValues(metrics.MergeModeDistributed).Add(0)
 }
 
 func (m *manager) GetAffinity(podUID string, containerName string) TopologyHint {
+	if m.podScopeOverride != nil {
+		// Pods admitted through the pod-scope override store their hints in
+		// the override scope; prefer those when present.
+		if hint := m.podScopeOverride.GetAffinity(podUID, containerName); hint.NUMANodeAffinity != nil {
+			return hint
+		}
+	}
+	// Likewise for pods admitted under a per-pod policy override; a container
+	// is only ever admitted through one scope, so at most one has a hint.
+	for _, overrideScope := range m.policyOverrideScopes {
+		if hint := overrideScope.GetAffinity(podUID, containerName); hint.NUMANodeAffinity != nil {
+			return hint
+		}
+	}
 	return m.scope.GetAffinity(podUID, containerName)
 }
 
@@ -209,26 +751,242 @@ func (m *manager) GetPolicy() Policy {
 	return m.scope.GetPolicy()
 }
 
+func (m *manager) PolicyOptions() PolicyOptions {
+	return m.opts
+}
+
+func (m *manager) GetScopeName() string {
+	return m.scope.Name()
+}
+
 func (m *manager) AddHintProvider(h HintProvider) {
+	m.topologyMutex.Lock()
+	defer m.topologyMutex.Unlock()
+
 	m.scope.AddHintProvider(h)
+	if m.podScopeOverride != nil {
+		m.podScopeOverride.AddHintProvider(h)
+	}
+	for _, overrideScope := range m.policyOverrideScopes {
+		overrideScope.AddHintProvider(h)
+	}
+	if listener, ok := h.(TopologyChangeListener); ok {
+		m.changeListeners = append(m.changeListeners, listener)
+	}
+}
+
+func (m *manager) NotifyTopologyChanged() {
+	// Wait for in-flight admissions to finish against the old topology before
+	// invalidating caches and notifying listeners, so no Admit observes a
+	// half-applied change.
+	m.topologyMutex.Lock()
+	defer m.topologyMutex.Unlock()
+
+	generation := m.generation.Add(1)
+	klog.InfoS("Machine topology changed", "generation", generation)
+	m.scope.NotifyTopologyChanged()
+	if m.podScopeOverride != nil {
+		m.podScopeOverride.NotifyTopologyChanged()
+	}
+	for _, overrideScope := range m.policyOverrideScopes {
+		overrideScope.NotifyTopologyChanged()
+	}
+	for _, listener := range m.changeListeners {
+		listener.TopologyChanged()
+	}
 }
 
 func (m *manager) AddContainer(pod *v1.Pod, container *v1.Container, containerID string) {
 	m.scope.AddContainer(pod, container, containerID)
+	if m.podScopeOverride != nil {
+		m.podScopeOverride.AddContainer(pod, container, containerID)
+	}
+	for _, overrideScope := range m.policyOverrideScopes {
+		overrideScope.AddContainer(pod, container, containerID)
+	}
 }
 
 func (m *manager) RemoveContainer(containerID string) error {
+	if m.podScopeOverride != nil {
+		if err := m.podScopeOverride.RemoveContainer(containerID); err != nil {
+			return err
+		}
+	}
+	for _, overrideScope := range m.policyOverrideScopes {
+		if err := overrideScope.RemoveContainer(containerID); err != nil {
+			return err
+		}
+	}
 	return m.scope.RemoveContainer(containerID)
 }
 
+// admitScope returns the scope that should admit the given pod, honoring the
+// scope annotation on nodes configured for container scope. Unknown
+// annotation values fall back to the configured scope with a warning.
+func (m *manager) admitScope(pod *v1.Pod) Scope {
+	if m.podScopeOverride == nil {
+		return m.scope
+	}
+	value, ok := pod.Annotations[ScopeAnnotationKey]
+	if !ok {
+		return m.scope
+	}
+	switch value {
+	case podTopologyScope:
+		klog.V(4).InfoS("Pod requested pod-scope alignment via annotation", "pod", klog.KObj(pod))
+		return m.podScopeOverride
+	case containerTopologyScope:
+		return m.scope
+	default:
+		klog.InfoS("Ignoring unknown topology scope annotation value", "pod", klog.KObj(pod), "annotation", ScopeAnnotationKey, "value", value)
+		return m.scope
+	}
+}
+
+// policyOverrideScope returns the scope admitting the given pod under the
+// policy its annotation names, or nil when the pod carries no override. The
+// annotation is only honored in allowlisted namespaces; elsewhere it is
+// ignored with a warning. An allowlisted pod naming an unknown or unavailable
+// policy gets an error, which rejects its admission.
+func (m *manager) policyOverrideScope(pod *v1.Pod) (Scope, error) {
+	if len(m.policyOverrideScopes) == 0 {
+		return nil, nil
+	}
+	value, ok := pod.Annotations[PolicyAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+	if !m.policyOverrideNamespaces.Has(pod.Namespace) {
+		klog.InfoS("Ignoring topology policy annotation: namespace not allowlisted", "pod", klog.KObj(pod), "annotation", PolicyAnnotationKey, "value", value)
+		return nil, nil
+	}
+	if !IsValidPolicy(value) {
+		return nil, fmt.Errorf("unknown policy %q in %s annotation", value, PolicyAnnotationKey)
+	}
+	if value == m.scope.GetPolicy().Name() {
+		// The pod asked for the policy already in effect.
+		return nil, nil
+	}
+	overrideScope, ok := m.policyOverrideScopes[value]
+	if !ok {
+		return nil, fmt.Errorf("policy %q in %s annotation is not selectable on this node", value, PolicyAnnotationKey)
+	}
+	klog.V(4).InfoS("Pod requested a topology policy override via annotation", "pod", klog.KObj(pod), "policy", value)
+	return overrideScope, nil
+}
+
+func (m *manager) AlignedCapacity(resource string) map[int]int64 {
+	return m.scope.AlignedCapacity(resource)
+}
+
+func (m *manager) PinnedNodeCounts() map[int]int {
+	counts := m.scope.PinnedNodeCounts()
+	if m.podScopeOverride != nil {
+		for node, count := range m.podScopeOverride.PinnedNodeCounts() {
+			counts[node] += count
+		}
+	}
+	for _, overrideScope := range m.policyOverrideScopes {
+		for node, count := range overrideScope.PinnedNodeCounts() {
+			counts[node] += count
+		}
+	}
+	return counts
+}
+
 func (m *manager) Admit(attrs *lifecycle.PodAdmitAttributes) lifecycle.PodAdmitResult {
 	klog.V(4).InfoS("Topology manager admission check", "pod", klog.KObj(attrs.Pod))
 	metrics.TopologyManagerAdmissionRequestsTotal.Inc()
 
+	// Admissions may run concurrently with each other, but not with a
+	// topology change: hint generation and merging must see a consistent
+	// snapshot from start to finish.
+	m.topologyMutex.RLock()
+	defer m.topologyMutex.RUnlock()
+
 	startTime := time.Now()
-	podAdmitResult := m.scope.Admit(attrs.Pod)
+	admitScope := m.admitScope(attrs.Pod)
+	if overrideScope, err := m.policyOverrideScope(attrs.Pod); err != nil {
+		metrics.TopologyManagerAdmissionErrorsTotal.Inc()
+		return admission.GetPodAdmitResult(err)
+	} else if overrideScope != nil {
+		// A policy override wins over the scope annotation: the pod is
+		// admitted at the configured scope under the requested policy.
+		admitScope = overrideScope
+	}
+	podAdmitResult := admitScope.Admit(attrs.Pod)
 	metrics.TopologyManagerAdmissionDuration.Observe(float64(time.Since(startTime).Milliseconds()))
 
+	m.recordAdmitEvent(attrs.Pod, admitScope, podAdmitResult)
+	m.recordPlacementCondition(attrs.Pod, admitScope, podAdmitResult)
+
 	klog.V(4).InfoS("Pod Admit Result", "Message", podAdmitResult.Message, "pod", klog.KObj(attrs.Pod))
 	return podAdmitResult
 }
+
+func (m *manager) SetEventRecorder(recorder record.EventRecorder) {
+	m.recorder = recorder
+}
+
+func (m *manager) SetPodStatusWriter(writer PodStatusWriter) {
+	m.statusWriter = writer
+}
+
+// recordAdmitEvent surfaces the admission outcome on the pod itself when an
+// event recorder was registered, so operators can see the committed NUMA
+// placement (or the rejection reason) with kubectl describe. Pods that
+// required no pinning produce no event.
+func (m *manager) recordAdmitEvent(pod *v1.Pod, admitScope Scope, result lifecycle.PodAdmitResult) {
+	if m.recorder == nil {
+		return
+	}
+	if !result.Admit {
+		m.recorder.Event(pod, v1.EventTypeWarning, result.Reason, result.Message)
+		return
+	}
+	placements := placementSummaries(pod, admitScope)
+	if len(placements) == 0 {
+		return
+	}
+	m.recorder.Event(pod, v1.EventTypeNormal, PinnedEventReason, strings.Join(placements, "; "))
+}
+
+// recordPlacementCondition records the committed placement on the pod's
+// status as a TopologyPlacementScore condition when a status writer was
+// registered, so the score lands on the pod object itself rather than only
+// in node metrics. Rejected pods and pods that required no pinning record
+// nothing.
+func (m *manager) recordPlacementCondition(pod *v1.Pod, admitScope Scope, result lifecycle.PodAdmitResult) {
+	if m.statusWriter == nil || !result.Admit {
+		return
+	}
+	placements := placementSummaries(pod, admitScope)
+	if len(placements) == 0 {
+		return
+	}
+	m.statusWriter.SetPodCondition(pod, v1.PodCondition{
+		Type:               TopologyPlacementScoreCondition,
+		Status:             v1.ConditionTrue,
+		Reason:             PinnedEventReason,
+		Message:            strings.Join(placements, "; "),
+		LastTransitionTime: metav1.Now(),
+	})
+}
+
+// placementSummaries describes, per container with a committed affinity, the
+// pinned NUMA nodes and the placement score when one was computed.
+func placementSummaries(pod *v1.Pod, admitScope Scope) []string {
+	var placements []string
+	for _, container := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+		hint := admitScope.GetAffinity(string(pod.UID), container.Name)
+		if hint.NUMANodeAffinity == nil {
+			continue
+		}
+		placement := fmt.Sprintf("container %q pinned to NUMA nodes %v", container.Name, hint.NodeIDs())
+		if hint.HasScore() {
+			placement += fmt.Sprintf(" (score %v)", hint.GetScore())
+		}
+		placements = append(placements, placement)
+	}
+	return placements
+}
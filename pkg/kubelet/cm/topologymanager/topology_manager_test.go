@@ -18,15 +18,24 @@ package topologymanager
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
+	"sync"
 	"testing"
 
 	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/tools/record"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"k8s.io/component-base/metrics/testutil"
 
 	cadvisorapi "github.com/google/cadvisor/info/v1"
 
+	pkgfeatures "k8s.io/kubernetes/pkg/features"
 	"k8s.io/kubernetes/pkg/kubelet/cm/topologymanager/bitmask"
 	"k8s.io/kubernetes/pkg/kubelet/lifecycle"
+	kubeletmetrics "k8s.io/kubernetes/pkg/kubelet/metrics"
 )
 
 func NewTestBitMask(sockets ...int) bitmask.BitMask {
@@ -136,6 +145,46 @@ func TestNewManager(t *testing.T) {
 				},
 			},
 		},
+		{
+			description:    "10 NUMA nodes allowed with a raised max-allowable-numa-nodes",
+			policyName:     "best-effort",
+			expectedPolicy: "best-effort",
+			policyOptions: map[string]string{
+				MaxAllowableNUMANodes: "10",
+			},
+			topology: []cadvisorapi.Node{
+				{
+					Id: 0,
+				},
+				{
+					Id: 1,
+				},
+				{
+					Id: 2,
+				},
+				{
+					Id: 3,
+				},
+				{
+					Id: 4,
+				},
+				{
+					Id: 5,
+				},
+				{
+					Id: 6,
+				},
+				{
+					Id: 7,
+				},
+				{
+					Id: 8,
+				},
+				{
+					Id: 9,
+				},
+			},
+		},
 	}
 
 	for _, tc := range tcases {
@@ -161,6 +210,73 @@ func TestNewManager(t *testing.T) {
 	}
 }
 
+func TestNewManagerDisabledPolicies(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.TopologyManagerPolicyAlphaOptions, true)
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.EnhancedTopologyHints, true)
+
+	topology := []cadvisorapi.Node{{Id: 0}, {Id: 1}}
+	policyOptions := map[string]string{
+		DisabledPolicies: "distributed",
+	}
+
+	_, err := NewManager(topology, PolicyDistributed, "container", policyOptions)
+	if err == nil {
+		t.Fatalf("Expected an error selecting a disabled policy")
+	}
+	if !strings.Contains(err.Error(), "disabled") {
+		t.Errorf("Unexpected error message. Have: %s wants mention of the policy being disabled", err.Error())
+	}
+
+	// A policy outside the disabled list still constructs normally.
+	mngr, err := NewManager(topology, PolicyBestEffort, "container", policyOptions)
+	if err != nil {
+		t.Fatalf("Unexpected error creating topology manager: %v", err)
+	}
+	rawMgr := mngr.(*manager)
+	if policyName := rawMgr.scope.(*containerScope).policy.Name(); policyName != PolicyBestEffort {
+		t.Errorf("Unexpected policy name. Have: %q wants %q", policyName, PolicyBestEffort)
+	}
+}
+
+func TestValidPolicies(t *testing.T) {
+	// The distributed policy only constructs with the feature gate enabled.
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.EnhancedTopologyHints, true)
+
+	topology := []cadvisorapi.Node{{Id: 0}, {Id: 1}}
+	for _, policy := range ValidPolicies {
+		if !IsValidPolicy(policy) {
+			t.Errorf("Expected %q to be reported as a valid policy", policy)
+		}
+		mngr, err := NewManager(topology, policy, "container", nil)
+		if err != nil {
+			t.Errorf("Expected policy %q to construct a manager, got: %v", policy, err)
+			continue
+		}
+		if mngr == nil {
+			t.Errorf("Expected a manager for policy %q", policy)
+		}
+	}
+
+	for _, scope := range ValidScopes {
+		if !IsValidScope(scope) {
+			t.Errorf("Expected %q to be reported as a valid scope", scope)
+		}
+	}
+
+	if IsValidPolicy("unknown") {
+		t.Errorf("Expected %q to be reported as an invalid policy", "unknown")
+	}
+	if IsValidScope("unknown") {
+		t.Errorf("Expected %q to be reported as an invalid scope", "unknown")
+	}
+	if _, err := NewManager(topology, "unknown", "container", nil); err == nil {
+		t.Errorf("Expected an unknown policy name to be rejected")
+	}
+	if _, err := NewManager(topology, PolicyBestEffort, "unknown", nil); err == nil {
+		t.Errorf("Expected an unknown scope name to be rejected")
+	}
+}
+
 func TestManagerScope(t *testing.T) {
 	tcases := []struct {
 		description   string
@@ -221,6 +337,15 @@ func (m *mockHintProvider) Allocate(pod *v1.Pod, container *v1.Container) error
 	return nil
 }
 
+type mockAlignedCapacityProvider struct {
+	mockHintProvider
+	capacity map[string]map[int]int64
+}
+
+func (m *mockAlignedCapacityProvider) AlignedCapacity(resource string) map[int]int64 {
+	return m.capacity[resource]
+}
+
 type mockPolicy struct {
 	nonePolicy
 	ph []map[string][]TopologyHint
@@ -581,3 +706,1133 @@ func TestAdmit(t *testing.T) {
 		}
 	}
 }
+
+func TestIsPinned(t *testing.T) {
+	emptyMask, _ := bitmask.NewBitMask()
+	tcases := []struct {
+		description string
+		hint        TopologyHint
+		expected    bool
+	}{
+		{
+			description: "Hint with nil affinity is not pinned",
+			hint:        TopologyHint{NUMANodeAffinity: nil, Preferred: true},
+			expected:    false,
+		},
+		{
+			description: "Hint with empty affinity is not pinned",
+			hint:        TopologyHint{NUMANodeAffinity: emptyMask, Preferred: true},
+			expected:    false,
+		},
+		{
+			description: "Hint with populated affinity is pinned",
+			hint:        TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: true},
+			expected:    true,
+		},
+	}
+	for _, tc := range tcases {
+		if actual := tc.hint.IsPinned(); actual != tc.expected {
+			t.Errorf("%v: expected IsPinned() to be %v, got %v", tc.description, tc.expected, actual)
+		}
+	}
+}
+
+type mockHealthCheckableHintProvider struct {
+	mockHintProvider
+	healthy bool
+}
+
+func (m *mockHealthCheckableHintProvider) Healthy() bool {
+	return m.healthy
+}
+
+func TestAlignedCapacity(t *testing.T) {
+	tcases := []struct {
+		name     string
+		hp       []HintProvider
+		resource string
+		expected map[int]int64
+	}{
+		{
+			name:     "No hint providers",
+			hp:       []HintProvider{},
+			resource: "resource",
+			expected: map[int]int64{},
+		},
+		{
+			name: "Providers that do not report capacity are skipped",
+			hp: []HintProvider{
+				&mockHintProvider{},
+			},
+			resource: "resource",
+			expected: map[int]int64{},
+		},
+		{
+			name: "Single provider reporting per-node capacity",
+			hp: []HintProvider{
+				&mockAlignedCapacityProvider{
+					capacity: map[string]map[int]int64{
+						"resource": {0: 4, 1: 2},
+					},
+				},
+			},
+			resource: "resource",
+			expected: map[int]int64{0: 4, 1: 2},
+		},
+		{
+			name: "Multiple providers aggregate per node",
+			hp: []HintProvider{
+				&mockAlignedCapacityProvider{
+					capacity: map[string]map[int]int64{
+						"resource": {0: 4, 1: 2},
+					},
+				},
+				&mockAlignedCapacityProvider{
+					capacity: map[string]map[int]int64{
+						"resource": {1: 1},
+					},
+				},
+				&mockHintProvider{},
+			},
+			resource: "resource",
+			expected: map[int]int64{0: 4, 1: 3},
+		},
+		{
+			name: "Unknown resource yields empty map",
+			hp: []HintProvider{
+				&mockAlignedCapacityProvider{
+					capacity: map[string]map[int]int64{
+						"resource": {0: 4},
+					},
+				},
+			},
+			resource: "other-resource",
+			expected: map[int]int64{},
+		},
+	}
+
+	for _, tc := range tcases {
+		mngr := manager{}
+		mngr.scope = NewContainerScope(NewNonePolicy())
+		for _, hp := range tc.hp {
+			mngr.AddHintProvider(hp)
+		}
+		actual := mngr.AlignedCapacity(tc.resource)
+		if !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("Test Case: %s: Expected aligned capacity to be %v, got %v", tc.name, tc.expected, actual)
+		}
+	}
+}
+
+func TestTopologyHintIsEqualFloatTolerance(t *testing.T) {
+	base := 48.0
+	withinEpsilon := base + float64EqualityEpsilon/10
+	beyondEpsilon := base + 1e-6
+
+	hopCount := 1
+	otherHopCount := 2
+
+	tcases := []struct {
+		name     string
+		hint1    TopologyHint
+		hint2    TopologyHint
+		expected bool
+	}{
+		{
+			name:     "Bandwidth differing by less than epsilon compares equal",
+			hint1:    TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, Bandwidth: &base},
+			hint2:    TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, Bandwidth: &withinEpsilon},
+			expected: true,
+		},
+		{
+			name:     "Bandwidth differing by more than epsilon compares unequal",
+			hint1:    TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, Bandwidth: &base},
+			hint2:    TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, Bandwidth: &beyondEpsilon},
+			expected: false,
+		},
+		{
+			name:     "Score differing by less than epsilon compares equal",
+			hint1:    TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, Score: &base},
+			hint2:    TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, Score: &withinEpsilon},
+			expected: true,
+		},
+		{
+			name:     "Score differing by more than epsilon compares unequal",
+			hint1:    TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, Score: &base},
+			hint2:    TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, Score: &beyondEpsilon},
+			expected: false,
+		},
+		{
+			name:     "Integer fields still compare exactly",
+			hint1:    TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, HopCount: &hopCount},
+			hint2:    TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, HopCount: &otherHopCount},
+			expected: false,
+		},
+	}
+
+	for _, tc := range tcases {
+		if actual := tc.hint1.IsEqual(tc.hint2); actual != tc.expected {
+			t.Errorf("Test Case: %s: Expected IsEqual to be %v, got %v", tc.name, tc.expected, actual)
+		}
+	}
+}
+
+func TestTopologyHintEqualIgnoringScore(t *testing.T) {
+	hopCount := 1
+	distance := 21
+	bandwidth := 48.0
+	otherBandwidth := 32.0
+	score := 10.0
+	otherScore := 10.5
+
+	tcases := []struct {
+		name                 string
+		hint1                TopologyHint
+		hint2                TopologyHint
+		expected             bool
+		expectedUnderIsEqual bool
+	}{
+		{
+			name:                 "Hints differing only in Score compare equal",
+			hint1:                TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: true, HopCount: &hopCount, Distance: &distance, Bandwidth: &bandwidth, Score: &score},
+			hint2:                TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: true, HopCount: &hopCount, Distance: &distance, Bandwidth: &bandwidth, Score: &otherScore},
+			expected:             true,
+			expectedUnderIsEqual: false,
+		},
+		{
+			name:                 "Hints with and without a Score compare equal",
+			hint1:                TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, Score: &score},
+			hint2:                TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+			expected:             true,
+			expectedUnderIsEqual: false,
+		},
+		{
+			name:                 "Hints differing in Bandwidth compare unequal",
+			hint1:                TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, Bandwidth: &bandwidth, Score: &score},
+			hint2:                TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, Bandwidth: &otherBandwidth, Score: &score},
+			expected:             false,
+			expectedUnderIsEqual: false,
+		},
+		{
+			name:                 "Hints differing in affinity compare unequal",
+			hint1:                TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true},
+			hint2:                TopologyHint{NUMANodeAffinity: NewTestBitMask(1), Preferred: true},
+			expected:             false,
+			expectedUnderIsEqual: false,
+		},
+		{
+			name:                 "Fully equal hints compare equal under both",
+			hint1:                TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, HopCount: &hopCount, Score: &score},
+			hint2:                TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, HopCount: &hopCount, Score: &score},
+			expected:             true,
+			expectedUnderIsEqual: true,
+		},
+	}
+
+	for _, tc := range tcases {
+		if actual := tc.hint1.EqualIgnoringScore(tc.hint2); actual != tc.expected {
+			t.Errorf("Test Case: %s: Expected EqualIgnoringScore to be %v, got %v", tc.name, tc.expected, actual)
+		}
+		if actual := tc.hint1.IsEqual(tc.hint2); actual != tc.expectedUnderIsEqual {
+			t.Errorf("Test Case: %s: Expected IsEqual to be %v, got %v", tc.name, tc.expectedUnderIsEqual, actual)
+		}
+	}
+}
+
+func TestTopologyHintFieldPresence(t *testing.T) {
+	localDistance := DefaultLocalDistance
+	unset := TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true}
+	explicitLocal := TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, Distance: &localDistance}
+
+	// GetDistance defaults to the local baseline, so the getter alone cannot
+	// tell an explicitly local hint from one without the metric.
+	if unset.GetDistance() != explicitLocal.GetDistance() {
+		t.Fatalf("Expected the getters to agree for an unset and an explicitly local distance, got %v and %v", unset.GetDistance(), explicitLocal.GetDistance())
+	}
+	if unset.HasDistance() {
+		t.Errorf("Expected HasDistance to be false for an unset distance")
+	}
+	if !explicitLocal.HasDistance() {
+		t.Errorf("Expected HasDistance to be true for an explicitly supplied local distance")
+	}
+	if unset.hasEnhancedFields() {
+		t.Errorf("Expected a hint without enhanced fields not to count as enhanced")
+	}
+	if !explicitLocal.hasEnhancedFields() {
+		t.Errorf("Expected a hint with an explicit distance to count as enhanced")
+	}
+
+	if unset.HasHopCount() || unset.HasBandwidth() || unset.HasScore() {
+		t.Errorf("Expected all presence checks to be false for a basic hint")
+	}
+	hopCount := 0
+	bandwidth := 0.0
+	score := 0.0
+	full := TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, HopCount: &hopCount, Bandwidth: &bandwidth, Score: &score}
+	if !full.HasHopCount() || !full.HasBandwidth() || !full.HasScore() {
+		t.Errorf("Expected the presence checks to be true for explicitly zero-valued fields")
+	}
+}
+
+func TestTopologyHintNodeIDs(t *testing.T) {
+	tcases := []struct {
+		name     string
+		hint     TopologyHint
+		expected []int
+	}{
+		{
+			name:     "nil affinity yields an empty slice",
+			hint:     TopologyHint{NUMANodeAffinity: nil, Preferred: true},
+			expected: []int{},
+		},
+		{
+			name:     "empty affinity yields an empty slice",
+			hint:     TopologyHint{NUMANodeAffinity: NewTestBitMask(), Preferred: false},
+			expected: []int{},
+		},
+		{
+			name:     "single node affinity",
+			hint:     TopologyHint{NUMANodeAffinity: NewTestBitMask(1), Preferred: true},
+			expected: []int{1},
+		},
+		{
+			name:     "multi node affinity in ascending order",
+			hint:     TopologyHint{NUMANodeAffinity: NewTestBitMask(3, 0), Preferred: true},
+			expected: []int{0, 3},
+		},
+	}
+
+	for _, tc := range tcases {
+		actual := tc.hint.NodeIDs()
+		if actual == nil || len(actual) != len(tc.expected) {
+			t.Errorf("Test Case: %s: Expected node IDs %v, got %v", tc.name, tc.expected, actual)
+			continue
+		}
+		for i := range tc.expected {
+			if actual[i] != tc.expected[i] {
+				t.Errorf("Test Case: %s: Expected node IDs %v, got %v", tc.name, tc.expected, actual)
+				break
+			}
+		}
+	}
+}
+
+func TestSortHints(t *testing.T) {
+	hopCount := 1
+	score := 12.5
+
+	preferredNode0 := TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true}
+	preferredNode0Enhanced := TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, HopCount: &hopCount}
+	preferredNode1 := TopologyHint{NUMANodeAffinity: NewTestBitMask(1), Preferred: true}
+	preferredBothNodes := TopologyHint{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: true}
+	preferredNilAffinity := TopologyHint{NUMANodeAffinity: nil, Preferred: true}
+	nonPreferredNode0Enhanced := TopologyHint{NUMANodeAffinity: NewTestBitMask(0), Preferred: false, Score: &score}
+	nonPreferredNilAffinity := TopologyHint{NUMANodeAffinity: nil, Preferred: false}
+
+	// Deliberately scrambled: preferred and non-preferred interleaved, nil
+	// affinities first, and the two equal-comparing node 0 hints (basic and
+	// enhanced) in a known relative order to exercise stability.
+	hints := []TopologyHint{
+		nonPreferredNilAffinity,
+		preferredBothNodes,
+		nonPreferredNode0Enhanced,
+		preferredNilAffinity,
+		preferredNode1,
+		preferredNode0,
+		preferredNode0Enhanced,
+	}
+	expected := []TopologyHint{
+		preferredNode0,
+		preferredNode0Enhanced,
+		preferredNode1,
+		preferredBothNodes,
+		preferredNilAffinity,
+		nonPreferredNode0Enhanced,
+		nonPreferredNilAffinity,
+	}
+
+	SortHints(hints)
+
+	if !reflect.DeepEqual(expected, hints) {
+		t.Errorf("Expected sorted hints to be %v, got %v", expected, hints)
+	}
+}
+
+type mockScopeAwareHintProvider struct {
+	containerHints map[string][]TopologyHint
+	podHints       map[string][]TopologyHint
+}
+
+func (m *mockScopeAwareHintProvider) GetTopologyHints(pod *v1.Pod, container *v1.Container) map[string][]TopologyHint {
+	return m.containerHints
+}
+
+func (m *mockScopeAwareHintProvider) GetPodTopologyHints(pod *v1.Pod) map[string][]TopologyHint {
+	return m.podHints
+}
+
+func (m *mockScopeAwareHintProvider) Allocate(pod *v1.Pod, container *v1.Container) error {
+	return nil
+}
+
+func TestScopeAnnotationOverride(t *testing.T) {
+	topology := []cadvisorapi.Node{{Id: 0}, {Id: 1}}
+	mngrIface, err := NewManager(topology, PolicyBestEffort, "container", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error creating topology manager: %v", err)
+	}
+	mngr := mngrIface.(*manager)
+
+	// The provider prefers node 0 at container scope and node 1 at pod scope,
+	// so the committed affinity reveals which scope admitted the pod.
+	mngr.AddHintProvider(&mockScopeAwareHintProvider{
+		containerHints: map[string][]TopologyHint{
+			"resource": {{NUMANodeAffinity: NewTestBitMask(0), Preferred: true}},
+		},
+		podHints: map[string][]TopologyHint{
+			"resource": {{NUMANodeAffinity: NewTestBitMask(1), Preferred: true}},
+		},
+	})
+
+	makeScopedPod := func(uid string, annotations map[string]string) *v1.Pod {
+		pod := &v1.Pod{
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{Name: "fakeContainer"},
+				},
+			},
+		}
+		pod.UID = types.UID(uid)
+		pod.Annotations = annotations
+		return pod
+	}
+
+	tcases := []struct {
+		name         string
+		pod          *v1.Pod
+		expectedMask bitmask.BitMask
+	}{
+		{
+			name:         "Un-annotated pod uses the configured container scope",
+			pod:          makeScopedPod("plainPod", nil),
+			expectedMask: NewTestBitMask(0),
+		},
+		{
+			name:         "Annotated pod is admitted at pod scope",
+			pod:          makeScopedPod("annotatedPod", map[string]string{ScopeAnnotationKey: "pod"}),
+			expectedMask: NewTestBitMask(1),
+		},
+		{
+			name:         "Unknown annotation value falls back to the configured scope",
+			pod:          makeScopedPod("bogusPod", map[string]string{ScopeAnnotationKey: "bogus"}),
+			expectedMask: NewTestBitMask(0),
+		},
+	}
+
+	for _, tc := range tcases {
+		if result := mngr.Admit(&lifecycle.PodAdmitAttributes{Pod: tc.pod}); !result.Admit {
+			t.Fatalf("Test Case: %s: Expected pod to be admitted, got %v", tc.name, result)
+		}
+		affinity := mngr.GetAffinity(string(tc.pod.UID), "fakeContainer")
+		if !affinity.NUMANodeAffinity.IsEqual(tc.expectedMask) {
+			t.Errorf("Test Case: %s: Expected affinity %v, got %v", tc.name, tc.expectedMask, affinity.NUMANodeAffinity)
+		}
+	}
+}
+
+func TestPolicyAnnotationOverride(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.TopologyManagerPolicyAlphaOptions, true)
+
+	topology := []cadvisorapi.Node{{Id: 0}, {Id: 1}}
+	mngrIface, err := NewManager(topology, PolicyBestEffort, "container", map[string]string{PolicyOverrideNamespaces: "trusted"})
+	if err != nil {
+		t.Fatalf("Unexpected error creating topology manager: %v", err)
+	}
+	mngr := mngrIface.(*manager)
+
+	// The provider's only preferred placement spans both NUMA nodes:
+	// best-effort admits it while single-numa-node rejects it, so the
+	// admission outcome reveals which policy was applied.
+	mngr.AddHintProvider(&mockHintProvider{
+		map[string][]TopologyHint{
+			"resource": {{NUMANodeAffinity: NewTestBitMask(0, 1), Preferred: true}},
+		},
+	})
+
+	makeNamespacedPod := func(uid, namespace string, annotations map[string]string) *v1.Pod {
+		pod := &v1.Pod{
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{Name: "fakeContainer"},
+				},
+			},
+		}
+		pod.UID = types.UID(uid)
+		pod.Namespace = namespace
+		pod.Annotations = annotations
+		return pod
+	}
+
+	tcases := []struct {
+		name          string
+		pod           *v1.Pod
+		expectedAdmit bool
+	}{
+		{
+			name:          "Un-annotated pod is admitted under the configured best-effort policy",
+			pod:           makeNamespacedPod("plainPod", "trusted", nil),
+			expectedAdmit: true,
+		},
+		{
+			name:          "Allowlisted pod's single-numa-node override takes effect",
+			pod:           makeNamespacedPod("overridePod", "trusted", map[string]string{PolicyAnnotationKey: PolicySingleNumaNode}),
+			expectedAdmit: false,
+		},
+		{
+			name:          "Non-allowlisted pod's override is ignored",
+			pod:           makeNamespacedPod("ignoredPod", "untrusted", map[string]string{PolicyAnnotationKey: PolicySingleNumaNode}),
+			expectedAdmit: true,
+		},
+		{
+			name:          "Allowlisted pod naming an unknown policy is rejected",
+			pod:           makeNamespacedPod("bogusPod", "trusted", map[string]string{PolicyAnnotationKey: "bogus"}),
+			expectedAdmit: false,
+		},
+	}
+
+	for _, tc := range tcases {
+		result := mngr.Admit(&lifecycle.PodAdmitAttributes{Pod: tc.pod})
+		if result.Admit != tc.expectedAdmit {
+			t.Errorf("Test Case: %s: Expected admit %v, got %v (%s)", tc.name, tc.expectedAdmit, result.Admit, result.Message)
+		}
+		if result.Admit {
+			affinity := mngr.GetAffinity(string(tc.pod.UID), "fakeContainer")
+			if !affinity.NUMANodeAffinity.IsEqual(NewTestBitMask(0, 1)) {
+				t.Errorf("Test Case: %s: Expected the best-effort affinity {0,1}, got %v", tc.name, affinity.NUMANodeAffinity)
+			}
+		}
+	}
+}
+
+func TestRemoveContainerClearsPinnedNodeCounts(t *testing.T) {
+	topology := []cadvisorapi.Node{{Id: 0}, {Id: 1}}
+	mngrIface, err := NewManager(topology, PolicyBestEffort, "container", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error creating topology manager: %v", err)
+	}
+	mngr := mngrIface.(*manager)
+
+	// The provider pins node 0 at container scope and node 1 at pod scope, so
+	// both the configured scope and the pod-scope override contribute to the
+	// per-node counts.
+	mngr.AddHintProvider(&mockScopeAwareHintProvider{
+		containerHints: map[string][]TopologyHint{
+			"resource": {{NUMANodeAffinity: NewTestBitMask(0), Preferred: true}},
+		},
+		podHints: map[string][]TopologyHint{
+			"resource": {{NUMANodeAffinity: NewTestBitMask(1), Preferred: true}},
+		},
+	})
+
+	makeCountedPod := func(uid string, annotations map[string]string) *v1.Pod {
+		pod := &v1.Pod{
+			Spec: v1.PodSpec{
+				Containers: []v1.Container{
+					{Name: "fakeContainer"},
+				},
+			},
+		}
+		pod.UID = types.UID(uid)
+		pod.Annotations = annotations
+		return pod
+	}
+
+	before := mngr.PinnedNodeCounts()
+	if len(before) != 0 {
+		t.Fatalf("Expected no pinned nodes before any admission, got %v", before)
+	}
+
+	containerScopedPod := makeCountedPod("countedPod", nil)
+	podScopedPod := makeCountedPod("countedPodScoped", map[string]string{ScopeAnnotationKey: "pod"})
+	for i, pod := range []*v1.Pod{containerScopedPod, podScopedPod} {
+		if result := mngr.Admit(&lifecycle.PodAdmitAttributes{Pod: pod}); !result.Admit {
+			t.Fatalf("Expected pod to be admitted, got %v", result)
+		}
+		mngr.AddContainer(pod, &pod.Spec.Containers[0], fmt.Sprintf("containerID-%d", i))
+	}
+
+	during := mngr.PinnedNodeCounts()
+	if during[0] != 1 || during[1] != 1 {
+		t.Fatalf("Expected one container pinned to each node, got %v", during)
+	}
+
+	// Removing the containers must reverse every per-node count they
+	// contributed, in every scope that tracked them; anything left over would
+	// permanently bias consumers of the counts toward the other nodes.
+	for i := range []*v1.Pod{containerScopedPod, podScopedPod} {
+		if err := mngr.RemoveContainer(fmt.Sprintf("containerID-%d", i)); err != nil {
+			t.Fatalf("Unexpected error removing container: %v", err)
+		}
+	}
+
+	after := mngr.PinnedNodeCounts()
+	if !reflect.DeepEqual(after, before) {
+		t.Errorf("Expected the pinned node counts to return to %v after removal, got %v", before, after)
+	}
+}
+
+func TestAdmitGetAffinityConsistency(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.EnhancedTopologyHints, true)
+
+	topology := []cadvisorapi.Node{{Id: 0}, {Id: 1}}
+	mngrIface, err := NewManager(topology, PolicyBestEffort, "container", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error creating topology manager: %v", err)
+	}
+	mngr := mngrIface.(*manager)
+
+	hopCount := 1
+	distance := 21
+	bandwidth := 40.0
+	mngr.AddHintProvider(&mockHintProvider{
+		map[string][]TopologyHint{
+			"resource": {
+				{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, HopCount: &hopCount, Distance: &distance, Bandwidth: &bandwidth},
+			},
+		},
+	})
+
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{Name: "fakeContainer"},
+			},
+		},
+	}
+	pod.UID = "enhancedPod"
+
+	if result := mngr.Admit(&lifecycle.PodAdmitAttributes{Pod: pod}); !result.Admit {
+		t.Fatalf("Expected pod to be admitted, got %v", result)
+	}
+
+	// GetAffinity must return the committed hint unchanged, enhanced fields
+	// included: the same metrics that drove admission are what downstream
+	// consumers (hint providers' Allocate calls) get to see.
+	expectedHopCount := hopCount
+	expectedDistance := distance
+	expectedBandwidth := bandwidth
+	expected := TopologyHint{
+		NUMANodeAffinity: NewTestBitMask(0),
+		Preferred:        true,
+		HopCount:         &expectedHopCount,
+		Distance:         &expectedDistance,
+		Bandwidth:        &expectedBandwidth,
+	}
+	expected.UpdateScore(0, PolicyOptions{})
+
+	affinity := mngr.GetAffinity(string(pod.UID), "fakeContainer")
+	if !affinity.IsEqual(expected) {
+		t.Errorf("Expected GetAffinity to return the committed hint %+v, got %+v", expected, affinity)
+	}
+	if !affinity.hasEnhancedFields() || !affinity.HasScore() {
+		t.Errorf("Expected the committed hint to retain its enhanced fields, got %+v", affinity)
+	}
+}
+
+func TestAdmitTopologyAgnosticPodShortCircuit(t *testing.T) {
+	topology := []cadvisorapi.Node{{Id: 0}, {Id: 1}}
+
+	for _, policyName := range []string{PolicyBestEffort, PolicyRestricted, PolicySingleNumaNode} {
+		t.Run(policyName, func(t *testing.T) {
+			mngrIface, err := NewManager(topology, policyName, "container", nil)
+			if err != nil {
+				t.Fatalf("Unexpected error creating topology manager: %v", err)
+			}
+			mngr := mngrIface.(*manager)
+			// A provider with a nil hint map, as providers return for pods
+			// that request nothing topology-relevant.
+			mngr.AddHintProvider(&mockHintProvider{})
+
+			pod := &v1.Pod{
+				Spec: v1.PodSpec{
+					Containers: []v1.Container{
+						{Name: "fakeContainer"},
+					},
+				},
+			}
+			pod.UID = types.UID("agnosticPod-" + policyName)
+
+			if result := mngr.Admit(&lifecycle.PodAdmitAttributes{Pod: pod}); !result.Admit {
+				t.Fatalf("Expected the pod to be admitted, got %v", result)
+			}
+			affinity := mngr.GetAffinity(string(pod.UID), "fakeContainer")
+			if affinity.NUMANodeAffinity != nil {
+				t.Errorf("Expected a don't-care affinity from the short circuit, got %v", affinity.NUMANodeAffinity)
+			}
+			if !affinity.Preferred {
+				t.Errorf("Expected the short-circuited hint to be preferred, got %+v", affinity)
+			}
+
+			// The full merge path must agree: a no-preference pod is admitted
+			// and left unrestricted (either a nil affinity or the full
+			// machine mask).
+			merged, admit := mngr.scope.GetPolicy().Merge([]map[string][]TopologyHint{nil})
+			if !admit {
+				t.Errorf("Expected the full merge path to admit a no-preference pod")
+			}
+			if merged.NUMANodeAffinity != nil && !merged.NUMANodeAffinity.IsEqual(NewTestBitMask(0, 1)) {
+				t.Errorf("Expected the full merge path to leave the pod unrestricted, got %v", merged.NUMANodeAffinity)
+			}
+		})
+	}
+}
+
+func BenchmarkAdmitTopologyAgnosticPod(b *testing.B) {
+	topology := []cadvisorapi.Node{{Id: 0}, {Id: 1}}
+	mngrIface, err := NewManager(topology, PolicyBestEffort, "container", nil)
+	if err != nil {
+		b.Fatalf("Unexpected error creating topology manager: %v", err)
+	}
+	mngr := mngrIface.(*manager)
+	mngr.AddHintProvider(&mockHintProvider{})
+
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{Name: "fakeContainer"},
+			},
+		},
+	}
+	pod.UID = types.UID("benchmarkPod")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mngr.Admit(&lifecycle.PodAdmitAttributes{Pod: pod})
+	}
+}
+
+type mockChangeListenerProvider struct {
+	mockHintProvider
+	notified int
+}
+
+func (m *mockChangeListenerProvider) TopologyChanged() {
+	m.notified++
+}
+
+func TestNotifyTopologyChanged(t *testing.T) {
+	topology := []cadvisorapi.Node{{Id: 0}, {Id: 1}}
+	mngrIface, err := NewManager(topology, PolicyBestEffort, "container", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error creating topology manager: %v", err)
+	}
+	mngr := mngrIface.(*manager)
+
+	listener := &mockChangeListenerProvider{
+		mockHintProvider: mockHintProvider{
+			th: map[string][]TopologyHint{
+				"resource": {{NUMANodeAffinity: NewTestBitMask(0), Preferred: true}},
+			},
+		},
+	}
+	mngr.AddHintProvider(listener)
+
+	cScope := mngr.scope.(*containerScope)
+	pScope := mngr.podScopeOverride.(*podScope)
+	cacheGeneration := cScope.mergeCache.generation
+
+	mngr.NotifyTopologyChanged()
+
+	if generation := mngr.generation.Load(); generation != 1 {
+		t.Errorf("Expected the manager generation to be bumped to 1, got %d", generation)
+	}
+	if cScope.mergeCache.generation != cacheGeneration+1 {
+		t.Errorf("Expected the container scope merge cache to be invalidated, got generation %d", cScope.mergeCache.generation)
+	}
+	if pScope.mergeCache.generation != cacheGeneration+1 {
+		t.Errorf("Expected the pod scope merge cache to be invalidated, got generation %d", pScope.mergeCache.generation)
+	}
+	if listener.notified != 1 {
+		t.Errorf("Expected the change listener to be notified once, got %d", listener.notified)
+	}
+}
+
+func TestAdmitConcurrentTopologyChange(t *testing.T) {
+	topology := []cadvisorapi.Node{{Id: 0}, {Id: 1}}
+	mngr, err := NewManager(topology, PolicyBestEffort, "container", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error creating topology manager: %v", err)
+	}
+	mngr.AddHintProvider(&mockHintProvider{
+		th: map[string][]TopologyHint{
+			"resource": {{NUMANodeAffinity: NewTestBitMask(0), Preferred: true}},
+		},
+	})
+
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{Name: "fakeContainer"},
+			},
+		},
+	}
+	pod.UID = types.UID("racePod")
+
+	// Hammer admissions while topology changes arrive concurrently. The test
+	// asserts nothing beyond completion; under -race it fails if an admission
+	// can observe a half-applied topology change.
+	var wg sync.WaitGroup
+	for worker := 0; worker < 4; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				mngr.Admit(&lifecycle.PodAdmitAttributes{Pod: pod})
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			mngr.NotifyTopologyChanged()
+		}
+	}()
+	wg.Wait()
+}
+
+func TestAdmitEvents(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.EnhancedTopologyHints, true)
+
+	topology := []cadvisorapi.Node{{Id: 0}, {Id: 1}}
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{Name: "nginx"},
+			},
+		},
+	}
+	pod.UID = types.UID("eventPod")
+
+	// A preferred single-node hint admits and pins; the recorder should see a
+	// Normal event naming the node and the computed score.
+	hopCountOne := 1
+	admitMngr, err := NewManager(topology, PolicyRestricted, "container", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error creating topology manager: %v", err)
+	}
+	admitRecorder := record.NewFakeRecorder(10)
+	admitMngr.SetEventRecorder(admitRecorder)
+	admitMngr.AddHintProvider(&mockHintProvider{
+		th: map[string][]TopologyHint{
+			"resource": {{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, HopCount: &hopCountOne}},
+		},
+	})
+	if result := admitMngr.Admit(&lifecycle.PodAdmitAttributes{Pod: pod}); !result.Admit {
+		t.Fatalf("Expected the pod to be admitted, got %v", result)
+	}
+	select {
+	case event := <-admitRecorder.Events:
+		for _, expected := range []string{v1.EventTypeNormal, PinnedEventReason, `container "nginx" pinned to NUMA nodes [0]`, "score 10"} {
+			if !strings.Contains(event, expected) {
+				t.Errorf("Expected the admit event to contain %q, got %q", expected, event)
+			}
+		}
+	default:
+		t.Errorf("Expected a pinning event to be emitted")
+	}
+
+	// A resource with no feasible affinity makes the restricted policy
+	// reject; the recorder should see a Warning event with the reason.
+	rejectMngr, err := NewManager(topology, PolicyRestricted, "container", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error creating topology manager: %v", err)
+	}
+	rejectRecorder := record.NewFakeRecorder(10)
+	rejectMngr.SetEventRecorder(rejectRecorder)
+	rejectMngr.AddHintProvider(&mockHintProvider{
+		th: map[string][]TopologyHint{
+			"resource": {},
+		},
+	})
+	if result := rejectMngr.Admit(&lifecycle.PodAdmitAttributes{Pod: pod}); result.Admit {
+		t.Fatalf("Expected the pod to be rejected, got %v", result)
+	}
+	select {
+	case event := <-rejectRecorder.Events:
+		for _, expected := range []string{v1.EventTypeWarning, ErrorTopologyAffinity} {
+			if !strings.Contains(event, expected) {
+				t.Errorf("Expected the rejection event to contain %q, got %q", expected, event)
+			}
+		}
+	default:
+		t.Errorf("Expected a rejection event to be emitted")
+	}
+}
+
+type fakePodStatusWriter struct {
+	pods       []*v1.Pod
+	conditions []v1.PodCondition
+}
+
+func (w *fakePodStatusWriter) SetPodCondition(pod *v1.Pod, condition v1.PodCondition) {
+	w.pods = append(w.pods, pod)
+	w.conditions = append(w.conditions, condition)
+}
+
+func TestAdmitRecordsPlacementCondition(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.EnhancedTopologyHints, true)
+
+	topology := []cadvisorapi.Node{{Id: 0}, {Id: 1}}
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{Name: "nginx"},
+			},
+		},
+	}
+	pod.UID = types.UID("conditionPod")
+
+	// An admitted pod with a committed placement gets the placement score
+	// condition recorded through the registered status writer.
+	hopCountOne := 1
+	admitMngr, err := NewManager(topology, PolicyRestricted, "container", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error creating topology manager: %v", err)
+	}
+	admitWriter := &fakePodStatusWriter{}
+	admitMngr.SetPodStatusWriter(admitWriter)
+	admitMngr.AddHintProvider(&mockHintProvider{
+		th: map[string][]TopologyHint{
+			"resource": {{NUMANodeAffinity: NewTestBitMask(0), Preferred: true, HopCount: &hopCountOne}},
+		},
+	})
+	if result := admitMngr.Admit(&lifecycle.PodAdmitAttributes{Pod: pod}); !result.Admit {
+		t.Fatalf("Expected the pod to be admitted, got %v", result)
+	}
+	if len(admitWriter.conditions) != 1 {
+		t.Fatalf("Expected exactly one pod condition to be recorded, got %d", len(admitWriter.conditions))
+	}
+	if admitWriter.pods[0] != pod {
+		t.Errorf("Expected the condition to be recorded on the admitted pod, got %v", admitWriter.pods[0])
+	}
+	condition := admitWriter.conditions[0]
+	if condition.Type != TopologyPlacementScoreCondition {
+		t.Errorf("Expected the condition type to be %q, got %q", TopologyPlacementScoreCondition, condition.Type)
+	}
+	if condition.Status != v1.ConditionTrue {
+		t.Errorf("Expected the condition status to be %q, got %q", v1.ConditionTrue, condition.Status)
+	}
+	if condition.Reason != PinnedEventReason {
+		t.Errorf("Expected the condition reason to be %q, got %q", PinnedEventReason, condition.Reason)
+	}
+	for _, expected := range []string{`container "nginx" pinned to NUMA nodes [0]`, "score 10"} {
+		if !strings.Contains(condition.Message, expected) {
+			t.Errorf("Expected the condition message to contain %q, got %q", expected, condition.Message)
+		}
+	}
+
+	// A rejected pod must not get a condition.
+	rejectMngr, err := NewManager(topology, PolicyRestricted, "container", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error creating topology manager: %v", err)
+	}
+	rejectWriter := &fakePodStatusWriter{}
+	rejectMngr.SetPodStatusWriter(rejectWriter)
+	rejectMngr.AddHintProvider(&mockHintProvider{
+		th: map[string][]TopologyHint{
+			"resource": {},
+		},
+	})
+	if result := rejectMngr.Admit(&lifecycle.PodAdmitAttributes{Pod: pod}); result.Admit {
+		t.Fatalf("Expected the pod to be rejected, got %v", result)
+	}
+	if len(rejectWriter.conditions) != 0 {
+		t.Errorf("Expected no pod condition for a rejected pod, got %v", rejectWriter.conditions)
+	}
+
+	// Without a registered writer admission works unchanged.
+	noWriterMngr, err := NewManager(topology, PolicyRestricted, "container", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error creating topology manager: %v", err)
+	}
+	noWriterMngr.AddHintProvider(&mockHintProvider{
+		th: map[string][]TopologyHint{
+			"resource": {{NUMANodeAffinity: NewTestBitMask(0), Preferred: true}},
+		},
+	})
+	if result := noWriterMngr.Admit(&lifecycle.PodAdmitAttributes{Pod: pod}); !result.Admit {
+		t.Fatalf("Expected the pod to be admitted without a status writer, got %v", result)
+	}
+}
+
+func TestNonePolicyShadowMetrics(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.TopologyManagerPolicyAlphaOptions, true)
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, pkgfeatures.EnhancedTopologyHints, true)
+
+	// The metrics are normally created during registration; force creation so
+	// they return live values in this test.
+	kubeletmetrics.TopologyManagerShadowMergesTotal.Create(nil)
+	kubeletmetrics.TopologyManagerShadowMergeScore.Create(nil)
+
+	shadowMergesValue := func(preferred string) float64 {
+		value, err := testutil.GetCounterMetricValue(kubeletmetrics.TopologyManagerShadowMergesTotal.WithLabelValues(preferred))
+		if err != nil {
+			t.Fatalf("Could not read the shadow merges metric for %q: %v", preferred, err)
+		}
+		return value
+	}
+	shadowScoreValue := func() float64 {
+		value, err := testutil.GetHistogramMetricValue(kubeletmetrics.TopologyManagerShadowMergeScore.ObserverMetric)
+		if err != nil {
+			t.Fatalf("Could not read the shadow merge score metric: %v", err)
+		}
+		return value
+	}
+
+	topology := []cadvisorapi.Node{{Id: 0}, {Id: 1}}
+	mngr, err := NewManager(topology, PolicyNone, "container", map[string]string{NonePolicyShadowMetrics: "true"})
+	if err != nil {
+		t.Fatalf("Unexpected error creating topology manager: %v", err)
+	}
+	// A single non-preferred hint: an enforcing best-effort policy would still
+	// admit, but the shadow merge must be counted as non-preferred.
+	hopCountOne := 1
+	mngr.AddHintProvider(&mockHintProvider{
+		th: map[string][]TopologyHint{
+			"resource": {{NUMANodeAffinity: NewTestBitMask(0), Preferred: false, HopCount: &hopCountOne}},
+		},
+	})
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{Name: "nginx"},
+			},
+		},
+	}
+	pod.UID = types.UID("shadowPod")
+
+	beforePreferred := shadowMergesValue("true")
+	beforeNonPreferred := shadowMergesValue("false")
+	beforeScore := shadowScoreValue()
+
+	if result := mngr.Admit(&lifecycle.PodAdmitAttributes{Pod: pod}); !result.Admit {
+		t.Fatalf("Expected the none policy to admit the pod, got %v", result)
+	}
+	if delta := shadowMergesValue("false") - beforeNonPreferred; delta != 1.0 {
+		t.Errorf("Expected one non-preferred shadow merge to be recorded, got %v", delta)
+	}
+	if delta := shadowMergesValue("true") - beforePreferred; delta != 0.0 {
+		t.Errorf("Expected no preferred shadow merge to be recorded, got %v", delta)
+	}
+	// One hop at the local distance scores 10; the histogram sum must grow by
+	// exactly the would-be score.
+	if delta := shadowScoreValue() - beforeScore; delta != 10.0 {
+		t.Errorf("Expected the shadow score sum to grow by 10, got %v", delta)
+	}
+	// The shadow merge must not commit anything.
+	if affinity := mngr.GetAffinity("shadowPod", "nginx"); affinity.NUMANodeAffinity != nil {
+		t.Errorf("Expected no committed affinity under the none policy, got %v", affinity)
+	}
+
+	// Without the option the none policy records no shadow merges at all.
+	plainMngr, err := NewManager(topology, PolicyNone, "container", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error creating topology manager: %v", err)
+	}
+	plainMngr.AddHintProvider(&mockHintProvider{
+		th: map[string][]TopologyHint{
+			"resource": {{NUMANodeAffinity: NewTestBitMask(0), Preferred: false, HopCount: &hopCountOne}},
+		},
+	})
+	beforePreferred = shadowMergesValue("true")
+	beforeNonPreferred = shadowMergesValue("false")
+	if result := plainMngr.Admit(&lifecycle.PodAdmitAttributes{Pod: pod}); !result.Admit {
+		t.Fatalf("Expected the none policy to admit the pod, got %v", result)
+	}
+	if shadowMergesValue("true") != beforePreferred || shadowMergesValue("false") != beforeNonPreferred {
+		t.Errorf("Expected no shadow merges without the none-policy-shadow-metrics option")
+	}
+}
+
+func TestPinnedNodeCounts(t *testing.T) {
+	topology := []cadvisorapi.Node{{Id: 0}, {Id: 1}}
+	mngrIface, err := NewManager(topology, PolicyBestEffort, "container", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error creating topology manager: %v", err)
+	}
+	mngr := mngrIface.(*manager)
+	cScope := mngr.scope.(*containerScope)
+
+	pod1 := &v1.Pod{}
+	pod1.UID = types.UID("pod1")
+	pod2 := &v1.Pod{}
+	pod2.UID = types.UID("pod2")
+	containers := []struct {
+		pod           *v1.Pod
+		containerName string
+		containerID   string
+		affinity      bitmask.BitMask
+	}{
+		{pod1, "containerA", "containerA-id", NewTestBitMask(0)},
+		{pod1, "containerB", "containerB-id", NewTestBitMask(0, 1)},
+		{pod2, "containerC", "containerC-id", NewTestBitMask(1)},
+		// Admitted with a don't-care affinity, so it pins nothing.
+		{pod2, "containerD", "containerD-id", nil},
+	}
+	for _, container := range containers {
+		mngr.AddContainer(container.pod, &v1.Container{Name: container.containerName}, container.containerID)
+		cScope.setTopologyHints(string(container.pod.UID), container.containerName, TopologyHint{NUMANodeAffinity: container.affinity, Preferred: true})
+	}
+
+	expected := map[int]int{0: 2, 1: 2}
+	if actual := mngr.PinnedNodeCounts(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected pinned node counts to be %v, got %v", expected, actual)
+	}
+
+	if err := mngr.RemoveContainer("containerB-id"); err != nil {
+		t.Fatalf("Unexpected error removing container: %v", err)
+	}
+	expected = map[int]int{0: 1, 1: 1}
+	if actual := mngr.PinnedNodeCounts(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected pinned node counts after removal to be %v, got %v", expected, actual)
+	}
+}
+
+func TestGetScopeName(t *testing.T) {
+	tcases := []struct {
+		description string
+		policyName  string
+		scopeName   string
+		expected    string
+	}{
+		{
+			description: "container scope",
+			policyName:  "best-effort",
+			scopeName:   "container",
+			expected:    "container",
+		},
+		{
+			description: "pod scope",
+			policyName:  "best-effort",
+			scopeName:   "pod",
+			expected:    "pod",
+		},
+		{
+			description: "none policy reports the none scope",
+			policyName:  "none",
+			scopeName:   "container",
+			expected:    "none",
+		},
+	}
+
+	for _, tc := range tcases {
+		mngr, err := NewManager(nil, tc.policyName, tc.scopeName, nil)
+		if err != nil {
+			t.Fatalf("%v: Unexpected error: %v", tc.description, err)
+		}
+		if name := mngr.GetScopeName(); name != tc.expected {
+			t.Errorf("%v: Unexpected scope name. Have: %q wants %q", tc.description, name, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"k8s.io/klog/v2"
+)
+
+// topologyStateFileName is the file in the kubelet root directory recording
+// the NUMA distance matrix discovered during the previous kubelet run.
+const topologyStateFileName = "topology_manager_state"
+
+// topologyState is the serialized form of the persisted topology snapshot.
+// Only the distance matrix is recorded: it is the part of the machine
+// topology that BIOS/firmware updates and CXL memory re-provisioning can
+// silently change across reboots.
+type topologyState struct {
+	NUMADistances NUMADistances `json:"numaDistances"`
+}
+
+// CheckTopologyDrift compares the freshly discovered NUMA distance matrix
+// against the one persisted from the previous kubelet run in stateDir. When
+// they differ, placement assumptions cached by hint providers or external
+// consumers no longer hold, so the manager logs a warning and bumps its
+// generation through NotifyTopologyChanged before persisting the new matrix.
+// State file errors are logged but never fatal: drift detection is an
+// advisory facility and must not keep the kubelet from starting.
+func (m *manager) CheckTopologyDrift(stateDir string) {
+	if m.numaInfo == nil || stateDir == "" {
+		return
+	}
+
+	statePath := filepath.Join(stateDir, topologyStateFileName)
+	data, err := os.ReadFile(statePath)
+	switch {
+	case err == nil:
+		var persisted topologyState
+		if err := json.Unmarshal(data, &persisted); err != nil {
+			klog.ErrorS(err, "Could not parse persisted topology state, overwriting it", "path", statePath)
+			break
+		}
+		if !m.numaInfo.DistancesEqual(&NUMAInfo{NUMADistances: persisted.NUMADistances}) {
+			klog.InfoS("NUMA distance matrix changed since the previous kubelet run, placements computed before the change are stale", "path", statePath)
+			m.NotifyTopologyChanged()
+		}
+	case os.IsNotExist(err):
+		// First run on this node, or the state was cleaned up: nothing to
+		// compare against, just record the current matrix below.
+	default:
+		klog.ErrorS(err, "Could not read persisted topology state", "path", statePath)
+	}
+
+	data, err = json.Marshal(topologyState{NUMADistances: m.numaInfo.NUMADistances})
+	if err != nil {
+		klog.ErrorS(err, "Could not serialize topology state")
+		return
+	}
+	if err := os.WriteFile(statePath, data, 0644); err != nil {
+		klog.ErrorS(err, "Could not persist topology state", "path", statePath)
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologymanager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	cadvisorapi "github.com/google/cadvisor/info/v1"
+)
+
+// newDriftTestManager builds a manager whose NUMAInfo carries the given
+// distance rows, using prefer-closest-numa-nodes so NewNUMAInfo records them.
+func newDriftTestManager(t *testing.T, distances map[int][]uint64) *manager {
+	t.Helper()
+	var topology []cadvisorapi.Node
+	for _, id := range []int{0, 1} {
+		topology = append(topology, cadvisorapi.Node{Id: id, Distances: distances[id]})
+	}
+	mngr, err := NewManager(topology, PolicyBestEffort, "container", map[string]string{PreferClosestNUMANodes: "true"})
+	if err != nil {
+		t.Fatalf("Unexpected error creating topology manager: %v", err)
+	}
+	return mngr.(*manager)
+}
+
+func TestCheckTopologyDrift(t *testing.T) {
+	stateDir := t.TempDir()
+	distances := map[int][]uint64{
+		0: {10, 20},
+		1: {20, 10},
+	}
+
+	// First run: nothing persisted yet, so no drift is reported, but the
+	// current matrix is written out for the next run.
+	mngr := newDriftTestManager(t, distances)
+	mngr.CheckTopologyDrift(stateDir)
+	if generation := mngr.generation.Load(); generation != 0 {
+		t.Errorf("Expected no drift on the first run, got generation %d", generation)
+	}
+	if _, err := os.Stat(filepath.Join(stateDir, topologyStateFileName)); err != nil {
+		t.Fatalf("Expected the topology state file to be written: %v", err)
+	}
+
+	// Restart with an unchanged matrix: still no drift.
+	mngr = newDriftTestManager(t, distances)
+	mngr.CheckTopologyDrift(stateDir)
+	if generation := mngr.generation.Load(); generation != 0 {
+		t.Errorf("Expected no drift with an unchanged matrix, got generation %d", generation)
+	}
+
+	// Restart after a firmware change altered the distances: drift is
+	// flagged by bumping the generation, and the new matrix replaces the
+	// persisted one.
+	changed := map[int][]uint64{
+		0: {10, 62},
+		1: {62, 10},
+	}
+	mngr = newDriftTestManager(t, changed)
+	mngr.CheckTopologyDrift(stateDir)
+	if generation := mngr.generation.Load(); generation != 1 {
+		t.Errorf("Expected drift to bump the generation to 1, got %d", generation)
+	}
+
+	// The changed matrix is now the persisted baseline.
+	mngr = newDriftTestManager(t, changed)
+	mngr.CheckTopologyDrift(stateDir)
+	if generation := mngr.generation.Load(); generation != 0 {
+		t.Errorf("Expected no drift once the new matrix was persisted, got generation %d", generation)
+	}
+}
+
+func TestCheckTopologyDriftNoStateDir(t *testing.T) {
+	mngr := newDriftTestManager(t, map[int][]uint64{
+		0: {10, 20},
+		1: {20, 10},
+	})
+
+	// Without a state directory the check is a no-op.
+	mngr.CheckTopologyDrift("")
+	if generation := mngr.generation.Load(); generation != 0 {
+		t.Errorf("Expected no drift without a state directory, got generation %d", generation)
+	}
+}
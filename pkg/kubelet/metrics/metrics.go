@@ -83,6 +83,7 @@ const (
 	// Metrics keys of device plugin operations
 	DevicePluginRegistrationCountKey  = "device_plugin_registration_total"
 	DevicePluginAllocationDurationKey = "device_plugin_alloc_duration_seconds"
+	DeviceManagerReclaimedDevicesKey  = "devicemanager_reclaimed_devices_total"
 	// Metrics keys of pod resources operations
 	PodResourcesEndpointRequestsTotalKey          = "pod_resources_endpoint_requests_total"
 	PodResourcesEndpointRequestsListKey           = "pod_resources_endpoint_requests_list"
@@ -125,9 +126,17 @@ const (
 	MemoryManagerPinningErrorsTotalKey   = "memory_manager_pinning_errors_total"
 
 	// Metrics to track the Topology manager behavior
-	TopologyManagerAdmissionRequestsTotalKey = "topology_manager_admission_requests_total"
-	TopologyManagerAdmissionErrorsTotalKey   = "topology_manager_admission_errors_total"
-	TopologyManagerAdmissionDurationKey      = "topology_manager_admission_duration_ms"
+	TopologyManagerAdmissionRequestsTotalKey         = "topology_manager_admission_requests_total"
+	TopologyManagerAdmissionErrorsTotalKey           = "topology_manager_admission_errors_total"
+	TopologyManagerAdmissionDurationKey              = "topology_manager_admission_duration_ms"
+	TopologyManagerSkippedUnhealthyProvidersTotalKey = "topology_manager_skipped_unhealthy_providers_total"
+	TopologyManagerMergeModeTotalKey                 = "topology_manager_merge_mode_total"
+	TopologyManagerAllocateErrorsTotalKey            = "topology_manager_allocate_errors_total"
+	TopologyManagerHintTruncationsTotalKey           = "topology_manager_hint_truncations_total"
+	TopologyManagerNonPreferredAdmissionsTotalKey    = "topology_manager_nonpreferred_admissions_total"
+	TopologyManagerEnhancedDecisionChangesTotalKey   = "topology_manager_enhanced_decision_changes_total"
+	TopologyManagerShadowMergesTotalKey              = "topology_manager_shadow_merges_total"
+	TopologyManagerShadowMergeScoreKey               = "topology_manager_shadow_merge_score"
 
 	// Metrics to track orphan pod cleanup
 	orphanPodCleanedVolumesKey       = "orphan_pod_cleaned_volumes"
@@ -158,6 +167,14 @@ const (
 	AlignedNUMANode    = "numa_node"
 	AlignedUncoreCache = "uncore_cache"
 
+	MergeModeEnhanced      = "enhanced"
+	MergeModeBasicFallback = "basic-fallback"
+	MergeModeDistributed   = "distributed"
+	MergeModeObserveOnly   = "enhanced-observe-only"
+
+	AllocateResultError    = "error"
+	AllocateResultRollback = "rollback_error"
+
 	// Metrics to track kubelet admission rejections.
 	AdmissionRejectionsTotalKey = "admission_rejections_total"
 
@@ -510,6 +527,16 @@ var (
 		},
 		[]string{"resource_name"},
 	)
+	// DeviceManagerReclaimedDevices is a Counter that tracks the cumulative number of stale device
+	// allocations reclaimed from terminated pods during device manager reconciliation.
+	DeviceManagerReclaimedDevices = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      KubeletSubsystem,
+			Name:           DeviceManagerReclaimedDevicesKey,
+			Help:           "Cumulative number of stale device allocations reclaimed from terminated pods.",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
 
 	// PodResourcesEndpointRequestsTotalCount is a Counter that tracks the cumulative number of requests to the PodResource endpoints.
 	// Broken down by server API version.
@@ -935,6 +962,90 @@ var (
 		},
 	)
 
+	// TopologyManagerSkippedUnhealthyProvidersTotal tracks the number of times an unhealthy hint provider was skipped while gathering topology hints.
+	TopologyManagerSkippedUnhealthyProvidersTotal = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      KubeletSubsystem,
+			Name:           TopologyManagerSkippedUnhealthyProvidersTotalKey,
+			Help:           "The number of times an unhealthy hint provider was skipped when gathering topology hints.",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+
+	// TopologyManagerMergeModeTotal tracks which merge strategy served each topology hint merge.
+	TopologyManagerMergeModeTotal = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      KubeletSubsystem,
+			Name:           TopologyManagerMergeModeTotalKey,
+			Help:           "The number of topology hint merges served by each merge mode.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"mode"},
+	)
+
+	// TopologyManagerAllocateErrorsTotal tracks hint provider allocation failures during topology manager admission.
+	TopologyManagerAllocateErrorsTotal = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      KubeletSubsystem,
+			Name:           TopologyManagerAllocateErrorsTotalKey,
+			Help:           "The number of hint provider allocation failures during topology manager admission, by result.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"result"},
+	)
+
+	// TopologyManagerHintTruncationsTotal tracks how often a provider's hint list for one resource exceeded the per-resource cap and was truncated.
+	TopologyManagerHintTruncationsTotal = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      KubeletSubsystem,
+			Name:           TopologyManagerHintTruncationsTotalKey,
+			Help:           "The number of times a hint provider's hints for a single resource exceeded the per-resource cap and were truncated.",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+
+	// TopologyManagerNonPreferredAdmissionsTotal tracks how often the best-effort policy admitted a pod without a fully preferred consensus across all resources.
+	TopologyManagerNonPreferredAdmissionsTotal = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      KubeletSubsystem,
+			Name:           TopologyManagerNonPreferredAdmissionsTotalKey,
+			Help:           "The number of admissions the best-effort topology policy allowed although no preferred consensus existed across all resources.",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+
+	// TopologyManagerEnhancedDecisionChangesTotal tracks how often the enhanced topology merge reached a different decision than the basic merge would have.
+	TopologyManagerEnhancedDecisionChangesTotal = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      KubeletSubsystem,
+			Name:           TopologyManagerEnhancedDecisionChangesTotalKey,
+			Help:           "The number of topology hint merges in which the enhanced merge chose a different placement or preference than the basic merge would have.",
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+
+	// TopologyManagerShadowMergesTotal tracks the shadow merges the none policy computed without enforcing, by whether the would-be placement was preferred.
+	TopologyManagerShadowMergesTotal = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Subsystem:      KubeletSubsystem,
+			Name:           TopologyManagerShadowMergesTotalKey,
+			Help:           "The number of shadow topology merges computed under the none policy, by whether the would-be placement was preferred.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"preferred"},
+	)
+
+	// TopologyManagerShadowMergeScore tracks the scores of the placements the none policy would have chosen, had an enforcing policy been enabled.
+	TopologyManagerShadowMergeScore = metrics.NewHistogram(
+		&metrics.HistogramOpts{
+			Subsystem:      KubeletSubsystem,
+			Name:           TopologyManagerShadowMergeScoreKey,
+			Help:           "Scores of the placements shadow topology merges under the none policy would have chosen.",
+			Buckets:        metrics.ExponentialBuckets(1, 2, 12),
+			StabilityLevel: metrics.ALPHA,
+		},
+	)
+
 	// OrphanPodCleanedVolumes is number of orphaned Pods that times that removeOrphanedPodVolumeDirs was called during the last sweep.
 	OrphanPodCleanedVolumes = metrics.NewGauge(
 		&metrics.GaugeOpts{
@@ -1229,6 +1340,7 @@ func Register() {
 		legacyregistry.MustRegister(Preemptions)
 		legacyregistry.MustRegister(DevicePluginRegistrationCount)
 		legacyregistry.MustRegister(DevicePluginAllocationDuration)
+		legacyregistry.MustRegister(DeviceManagerReclaimedDevices)
 		legacyregistry.MustRegister(RunningContainerCount)
 		legacyregistry.MustRegister(RunningPodCount)
 		legacyregistry.MustRegister(DesiredPodCount)
@@ -1271,6 +1383,14 @@ func Register() {
 		legacyregistry.MustRegister(TopologyManagerAdmissionRequestsTotal)
 		legacyregistry.MustRegister(TopologyManagerAdmissionErrorsTotal)
 		legacyregistry.MustRegister(TopologyManagerAdmissionDuration)
+		legacyregistry.MustRegister(TopologyManagerSkippedUnhealthyProvidersTotal)
+		legacyregistry.MustRegister(TopologyManagerMergeModeTotal)
+		legacyregistry.MustRegister(TopologyManagerAllocateErrorsTotal)
+		legacyregistry.MustRegister(TopologyManagerHintTruncationsTotal)
+		legacyregistry.MustRegister(TopologyManagerNonPreferredAdmissionsTotal)
+		legacyregistry.MustRegister(TopologyManagerEnhancedDecisionChangesTotal)
+		legacyregistry.MustRegister(TopologyManagerShadowMergesTotal)
+		legacyregistry.MustRegister(TopologyManagerShadowMergeScore)
 		legacyregistry.MustRegister(OrphanPodCleanedVolumes)
 		legacyregistry.MustRegister(OrphanPodCleanedVolumesErrors)
 
@@ -102,6 +102,40 @@ type Counter struct {
 	Value resource.Quantity
 }
 
+// NodeTopologyInfo describes one NUMA node of the machine publishing a
+// ResourceSlice. It is not part of the served resource.k8s.io API; drivers
+// hand it to in-process consumers like the kubelet managers, which use it to
+// make topology-aware placement decisions.
+type NodeTopologyInfo struct {
+	NodeID           int32
+	Resources        map[string]resource.Quantity
+	Properties       map[string]string
+	InterconnectInfo *InterconnectInfo
+}
+
+// InterconnectInfo carries the interconnect characteristics of a NUMA node.
+// The scalar fields summarize the node's position in the machine topology;
+// ConnectivityMatrix, when present, provides per-target-node values that take
+// precedence over the scalars.
+type InterconnectInfo struct {
+	HopCount           *int32
+	Bandwidth          *float64
+	Distance           *int32
+	Latency            *int32
+	ConnectivityMatrix []NodeConnectivity
+}
+
+// NodeConnectivity describes the connectivity from one NUMA node to a single
+// target NUMA node. PerResource, when present, refines the connectivity for
+// specific resource types between the same node pair.
+type NodeConnectivity struct {
+	TargetNodeID int32
+	Bandwidth    *float64
+	Latency      *int32
+	HopCount     *int32
+	PerResource  map[string]NodeConnectivity
+}
+
 type DeviceTaint struct {
 	Key       string
 	Value     string
@@ -0,0 +1,128 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// maxInterconnectMetric is the upper bound for hop counts and distances,
+// matching the ACPI SLIT value range where 255 means "unreachable".
+const maxInterconnectMetric = 255
+
+// ValidateNodeTopologyInfo validates one NUMA node entry published by a
+// driver, including its local resource capacities and interconnect
+// characteristics.
+func ValidateNodeTopologyInfo(info *NodeTopologyInfo, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if info.NodeID < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("nodeID"), info.NodeID, "must not be negative"))
+	}
+	for resourceName, quantity := range info.Resources {
+		resourcePath := fldPath.Child("resources").Key(resourceName)
+		// Resource names follow the same qualified-name rules Kubernetes
+		// applies to resource names everywhere else.
+		for _, msg := range validation.IsQualifiedName(resourceName) {
+			allErrs = append(allErrs, field.Invalid(resourcePath, resourceName, msg))
+		}
+		if quantity.Sign() < 0 {
+			allErrs = append(allErrs, field.Invalid(resourcePath, quantity.String(), "must not be negative"))
+		}
+	}
+	if info.InterconnectInfo != nil {
+		allErrs = append(allErrs, ValidateInterconnectInfo(info.InterconnectInfo, fldPath.Child("interconnectInfo"))...)
+	}
+	return allErrs
+}
+
+// ValidateResourceSliceTopology validates node topology published alongside a
+// ResourceSlice spec, including its relationship with the spec's
+// node-targeting fields. Topology describes one concrete machine, so it is
+// only permitted when the slice targets a single node through NodeName;
+// combining it with AllNodes or NodeSelector is rejected.
+func ValidateResourceSliceTopology(spec *ResourceSliceSpec, topology []NodeTopologyInfo, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if len(topology) == 0 {
+		return allErrs
+	}
+	if spec.NodeName == nil || *spec.NodeName == "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "may only be set when the slice targets a single node through nodeName"))
+	}
+	if spec.AllNodes {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "may not be combined with allNodes"))
+	}
+	if spec.NodeSelector != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "may not be combined with nodeSelector"))
+	}
+	for i := range topology {
+		allErrs = append(allErrs, ValidateNodeTopologyInfo(&topology[i], fldPath.Index(i))...)
+	}
+	return allErrs
+}
+
+// ValidateInterconnectInfo validates the interconnect characteristics of a
+// NUMA node, both the scalar summary fields and the optional per-target
+// connectivity matrix.
+func ValidateInterconnectInfo(info *InterconnectInfo, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validateBoundedMetric(info.HopCount, fldPath.Child("hopCount"))...)
+	allErrs = append(allErrs, validateBoundedMetric(info.Distance, fldPath.Child("distance"))...)
+	if info.Bandwidth != nil && *info.Bandwidth < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("bandwidth"), *info.Bandwidth, "must not be negative"))
+	}
+	if info.Latency != nil && *info.Latency < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("latency"), *info.Latency, "must not be negative"))
+	}
+	for i := range info.ConnectivityMatrix {
+		allErrs = append(allErrs, validateNodeConnectivity(&info.ConnectivityMatrix[i], fldPath.Child("connectivityMatrix").Index(i))...)
+	}
+	return allErrs
+}
+
+func validateNodeConnectivity(conn *NodeConnectivity, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if conn.TargetNodeID < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("targetNodeID"), conn.TargetNodeID, "must not be negative"))
+	}
+	if conn.Bandwidth != nil && *conn.Bandwidth < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("bandwidth"), *conn.Bandwidth, "must not be negative"))
+	}
+	if conn.Latency != nil && *conn.Latency < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("latency"), *conn.Latency, "must not be negative"))
+	}
+	allErrs = append(allErrs, validateBoundedMetric(conn.HopCount, fldPath.Child("hopCount"))...)
+	for resource, perResource := range conn.PerResource {
+		allErrs = append(allErrs, validateNodeConnectivity(&perResource, fldPath.Child("perResource").Key(resource))...)
+	}
+	return allErrs
+}
+
+// validateBoundedMetric checks an optional int32 metric constrained to the
+// ACPI SLIT value range.
+func validateBoundedMetric(value *int32, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if value == nil {
+		return allErrs
+	}
+	if *value < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath, *value, "must not be negative"))
+	} else if *value > maxInterconnectMetric {
+		allErrs = append(allErrs, field.Invalid(fldPath, *value, "must not be greater than 255"))
+	}
+	return allErrs
+}
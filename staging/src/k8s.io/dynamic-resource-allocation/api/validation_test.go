@@ -0,0 +1,283 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateInterconnectInfo(t *testing.T) {
+	validHopCount := int32(2)
+	validDistance := int32(21)
+	validBandwidth := float64(80)
+	validLatency := int32(150)
+	boundary := int32(255)
+	tooLarge := int32(256)
+	negative := int32(-1)
+	negativeBandwidth := float64(-1)
+
+	testcases := []struct {
+		name         string
+		info         *InterconnectInfo
+		expectedErrs int
+	}{
+		{
+			name:         "empty info is valid",
+			info:         &InterconnectInfo{},
+			expectedErrs: 0,
+		},
+		{
+			name: "valid scalar fields",
+			info: &InterconnectInfo{
+				HopCount:  &validHopCount,
+				Distance:  &validDistance,
+				Bandwidth: &validBandwidth,
+				Latency:   &validLatency,
+			},
+			expectedErrs: 0,
+		},
+		{
+			name: "boundary values are valid",
+			info: &InterconnectInfo{
+				HopCount: &boundary,
+				Distance: &boundary,
+			},
+			expectedErrs: 0,
+		},
+		{
+			name: "scalar fields out of range",
+			info: &InterconnectInfo{
+				HopCount:  &tooLarge,
+				Distance:  &negative,
+				Bandwidth: &negativeBandwidth,
+				Latency:   &negative,
+			},
+			expectedErrs: 4,
+		},
+		{
+			name: "valid connectivity matrix with hop counts",
+			info: &InterconnectInfo{
+				ConnectivityMatrix: []NodeConnectivity{
+					{TargetNodeID: 1, Bandwidth: &validBandwidth, Latency: &validLatency, HopCount: &validHopCount},
+					{TargetNodeID: 2, HopCount: &boundary},
+				},
+			},
+			expectedErrs: 0,
+		},
+		{
+			name: "connectivity matrix hop count out of range",
+			info: &InterconnectInfo{
+				ConnectivityMatrix: []NodeConnectivity{
+					{TargetNodeID: 1, HopCount: &tooLarge},
+					{TargetNodeID: 2, HopCount: &negative},
+				},
+			},
+			expectedErrs: 2,
+		},
+		{
+			name: "connectivity matrix other fields invalid",
+			info: &InterconnectInfo{
+				ConnectivityMatrix: []NodeConnectivity{
+					{TargetNodeID: -1, Bandwidth: &negativeBandwidth, Latency: &negative},
+				},
+			},
+			expectedErrs: 3,
+		},
+		{
+			name: "valid per-resource connectivity",
+			info: &InterconnectInfo{
+				ConnectivityMatrix: []NodeConnectivity{
+					{
+						TargetNodeID: 1,
+						Bandwidth:    &validBandwidth,
+						PerResource: map[string]NodeConnectivity{
+							"example.com/gpu": {Bandwidth: &validBandwidth, HopCount: &validHopCount},
+						},
+					},
+				},
+			},
+			expectedErrs: 0,
+		},
+		{
+			name: "per-resource connectivity out of range",
+			info: &InterconnectInfo{
+				ConnectivityMatrix: []NodeConnectivity{
+					{
+						TargetNodeID: 1,
+						PerResource: map[string]NodeConnectivity{
+							"example.com/gpu": {Bandwidth: &negativeBandwidth, HopCount: &tooLarge},
+						},
+					},
+				},
+			},
+			expectedErrs: 2,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateInterconnectInfo(tc.info, field.NewPath("interconnectInfo"))
+			if len(errs) != tc.expectedErrs {
+				t.Errorf("expected %d errors, got %d: %v", tc.expectedErrs, len(errs), errs)
+			}
+		})
+	}
+}
+
+func TestValidateNodeTopologyInfo(t *testing.T) {
+	tooLarge := int32(256)
+
+	testcases := []struct {
+		name         string
+		info         *NodeTopologyInfo
+		expectedErrs int
+	}{
+		{
+			name: "negative node ID and invalid interconnect info",
+			info: &NodeTopologyInfo{
+				NodeID: -1,
+				InterconnectInfo: &InterconnectInfo{
+					HopCount: &tooLarge,
+				},
+			},
+			expectedErrs: 2,
+		},
+		{
+			name: "valid resources",
+			info: &NodeTopologyInfo{
+				Resources: map[string]resource.Quantity{
+					"memory":          resource.MustParse("64Gi"),
+					"example.com/gpu": resource.MustParse("2"),
+				},
+			},
+			expectedErrs: 0,
+		},
+		{
+			name: "resource name with invalid characters",
+			info: &NodeTopologyInfo{
+				Resources: map[string]resource.Quantity{
+					"GPU!": resource.MustParse("2"),
+				},
+			},
+			expectedErrs: 1,
+		},
+		{
+			name: "resource name exceeding the qualified-name length",
+			info: &NodeTopologyInfo{
+				Resources: map[string]resource.Quantity{
+					"example.com/" + strings.Repeat("a", 64): resource.MustParse("2"),
+				},
+			},
+			expectedErrs: 1,
+		},
+		{
+			name: "negative resource quantity",
+			info: &NodeTopologyInfo{
+				Resources: map[string]resource.Quantity{
+					"example.com/gpu": resource.MustParse("-2"),
+				},
+			},
+			expectedErrs: 1,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateNodeTopologyInfo(tc.info, field.NewPath("nodeTopology"))
+			if len(errs) != tc.expectedErrs {
+				t.Errorf("expected %d errors, got %d: %v", tc.expectedErrs, len(errs), errs)
+			}
+		})
+	}
+}
+
+func TestValidateResourceSliceTopology(t *testing.T) {
+	nodeName := "worker-0"
+	emptyNodeName := ""
+	topology := []NodeTopologyInfo{
+		{NodeID: 0},
+		{NodeID: 1},
+	}
+
+	testcases := []struct {
+		name         string
+		spec         *ResourceSliceSpec
+		topology     []NodeTopologyInfo
+		expectedErrs int
+	}{
+		{
+			name:         "no topology needs no targeting",
+			spec:         &ResourceSliceSpec{AllNodes: true},
+			topology:     nil,
+			expectedErrs: 0,
+		},
+		{
+			name:         "valid single-node slice",
+			spec:         &ResourceSliceSpec{NodeName: &nodeName},
+			topology:     topology,
+			expectedErrs: 0,
+		},
+		{
+			name:         "topology without any node targeting",
+			spec:         &ResourceSliceSpec{},
+			topology:     topology,
+			expectedErrs: 1,
+		},
+		{
+			name:         "topology with an empty node name",
+			spec:         &ResourceSliceSpec{NodeName: &emptyNodeName},
+			topology:     topology,
+			expectedErrs: 1,
+		},
+		{
+			name:         "topology combined with allNodes",
+			spec:         &ResourceSliceSpec{AllNodes: true},
+			topology:     topology,
+			expectedErrs: 2,
+		},
+		{
+			name: "topology combined with a node selector",
+			spec: &ResourceSliceSpec{
+				NodeSelector: &v1.NodeSelector{},
+			},
+			topology:     topology,
+			expectedErrs: 2,
+		},
+		{
+			name: "invalid entries are validated too",
+			spec: &ResourceSliceSpec{NodeName: &nodeName},
+			topology: []NodeTopologyInfo{
+				{NodeID: -1},
+			},
+			expectedErrs: 1,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := ValidateResourceSliceTopology(tc.spec, tc.topology, field.NewPath("nodeTopology"))
+			if len(errs) != tc.expectedErrs {
+				t.Errorf("expected %d errors, got %d: %v", tc.expectedErrs, len(errs), errs)
+			}
+		})
+	}
+}